@@ -16,9 +16,18 @@ import (
 	"gitlab.com/jodworkspace/mvp/internal/handler/rest"
 	v1 "gitlab.com/jodworkspace/mvp/internal/handler/rest/v1"
 	pgrepo "gitlab.com/jodworkspace/mvp/internal/repository/postgres"
+	redisrepo "gitlab.com/jodworkspace/mvp/internal/repository/redis"
+	"gitlab.com/jodworkspace/mvp/internal/usecase/analytics"
+	"gitlab.com/jodworkspace/mvp/internal/usecase/calendar"
+	"gitlab.com/jodworkspace/mvp/internal/usecase/comment"
 	"gitlab.com/jodworkspace/mvp/internal/usecase/document"
+	"gitlab.com/jodworkspace/mvp/internal/usecase/focus"
+	"gitlab.com/jodworkspace/mvp/internal/usecase/label"
 	"gitlab.com/jodworkspace/mvp/internal/usecase/oauth"
+	"gitlab.com/jodworkspace/mvp/internal/usecase/project"
+	"gitlab.com/jodworkspace/mvp/internal/usecase/reminder"
 	"gitlab.com/jodworkspace/mvp/internal/usecase/task"
+	"gitlab.com/jodworkspace/mvp/internal/usecase/timeentry"
 	"gitlab.com/jodworkspace/mvp/internal/usecase/user"
 	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
 	"gitlab.com/jodworkspace/mvp/pkg/db/redis"
@@ -98,13 +107,98 @@ func main() {
 			// DB Transaction
 			transactionManager := pgrepo.NewTransactionManager(pgClient)
 
+			// Labels
+			labelRepository := pgrepo.NewLabelRepository(pgClient)
+			labelUC := label.NewUseCase(labelRepository, zapLogger)
+			labelHandler := v1.NewLabelHandler(labelUC, zapLogger)
+
+			// Projects
+			projectRepository := pgrepo.NewProjectRepository(pgClient)
+			projectUC := project.NewUseCase(projectRepository, zapLogger)
+			projectHandler := v1.NewProjectHandler(projectUC, zapLogger)
+
 			// Tasks
 			taskRepository := pgrepo.NewTaskRepository(pgClient)
-			taskUC := task.NewUseCase(taskRepository, zapLogger)
-			taskHandler := v1.NewTaskHandler(taskUC, zapLogger)
+			taskSeriesRepository := pgrepo.NewTaskSeriesRepository(pgClient)
+			taskEventRepository := pgrepo.NewTaskEventRepository(pgClient)
+			commentRepository := pgrepo.NewCommentRepository(pgClient)
+			timeEntryRepository := pgrepo.NewTimeEntryRepository(pgClient)
+			analyticsCache := redisrepo.NewAnalyticsCache(redisClient, cfg.Analytics.CacheTTL)
+			taskUC := task.NewUseCase(
+				cfg.Task,
+				taskRepository,
+				labelRepository,
+				projectRepository,
+				taskSeriesRepository,
+				taskEventRepository,
+				commentRepository,
+				timeEntryRepository,
+				analyticsCache,
+				transactionManager,
+				aead,
+				zapLogger,
+			)
+			taskHandler := v1.NewTaskHandler(taskUC, cfg.Task.RequireIfMatch, zapLogger)
 
-			// Users
+			// Task background jobs
+			go taskUC.PurgeTrash(c.Context, cfg.Task.TrashRetention, cfg.Task.PurgeInterval)
+
+			// Comments
+			commentUC := comment.NewUseCase(commentRepository, taskRepository, transactionManager, zapLogger)
+			commentHandler := v1.NewCommentHandler(commentUC, zapLogger)
+
+			// Time tracking
+			timerRepository := redisrepo.NewTimerRepository(redisClient)
+			timeEntryUC := timeentry.NewUseCase(timeEntryRepository, timerRepository, taskRepository, zapLogger)
+			timeEntryHandler := v1.NewTimeEntryHandler(timeEntryUC, zapLogger)
+
+			// Focus sessions, pushed over the WebSocket
+			wsHub := v1.NewWSHub(zapLogger)
+			focusSessionRepository := pgrepo.NewFocusSessionRepository(pgClient)
+			focusUC := focus.NewUseCase(cfg.Focus, focusSessionRepository, taskRepository, wsHub, zapLogger)
+			focusHandler := v1.NewFocusHandler(focusUC, zapLogger)
+
+			// Reminders
 			userRepository := pgrepo.NewUserRepository(pgClient)
+			notifiers := []reminder.Notifier{
+				reminder.NewWSNotifier(wsHub),
+				reminder.NewWebhookNotifier(reminder.NewWebhookClient(10*time.Second), cfg.Reminder.WebhookSecret),
+			}
+			if cfg.SMTP.Host != "" {
+				notifiers = append(notifiers, reminder.NewEmailNotifier(cfg.SMTP))
+			}
+			reminderRepository := pgrepo.NewReminderRepository(pgClient)
+			reminderUC := reminder.NewUseCase(
+				cfg.Reminder,
+				reminderRepository,
+				taskRepository,
+				userRepository,
+				transactionManager,
+				zapLogger,
+				notifiers...,
+			)
+			reminderHandler := v1.NewReminderHandler(reminderUC, zapLogger)
+			go reminderUC.Run(c.Context)
+
+			// Analytics
+			analyticsRepository := pgrepo.NewAnalyticsRepository(pgClient)
+			analyticsUC := analytics.NewUseCase(analyticsRepository, projectRepository, analyticsCache, zapLogger)
+			analyticsHandler := v1.NewAnalyticsHandler(analyticsUC, zapLogger)
+
+			// Calendar
+			calendarFeedRepository := pgrepo.NewCalendarFeedRepository(pgClient)
+			calendarUC := calendar.NewUseCase(
+				cfg.Token,
+				cfg.Calendar,
+				taskRepository,
+				labelRepository,
+				calendarFeedRepository,
+				transactionManager,
+				zapLogger,
+			)
+			calendarHandler := v1.NewCalendarHandler(calendarUC, zapLogger)
+
+			// Users
 			linkRepository := pgrepo.NewLinkRepository(pgClient)
 			userUC := user.NewUseCase(userRepository, linkRepository, transactionManager, aead, zapLogger)
 
@@ -116,14 +210,23 @@ func main() {
 
 			documentUC := document.NewUseCase(httpClient, zapLogger)
 			documentHandler := v1.NewDocumentHandler(documentUC, zapLogger)
-			wsHandler := v1.NewWSHandler(documentUC, zapLogger)
+			wsHandler := v1.NewWSHandler(documentUC, wsHub, cfg.CORS.AllowedOrigins, zapLogger)
 
 			// Start server
 			srv := rest.NewServer(
 				cfg,
 				aead,
 				sessionStore,
+				redisClient,
 				taskHandler,
+				commentHandler,
+				timeEntryHandler,
+				focusHandler,
+				reminderHandler,
+				analyticsHandler,
+				labelHandler,
+				projectHandler,
+				calendarHandler,
 				oauthHandler,
 				documentHandler,
 				wsHandler,
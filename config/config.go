@@ -34,6 +34,13 @@ type Config struct {
 	GoogleOAuth *GoogleOAuthConfig `envconfig:"google_oauth"`
 	Redis       *RedisConfig       `envconfig:"redis"`
 	Postgres    *PostgresConfig    `envconfig:"postgres"`
+	Task        *TaskConfig        `envconfig:"task"`
+	Calendar    *CalendarConfig    `envconfig:"calendar"`
+	Focus       *FocusConfig       `envconfig:"focus"`
+	Reminder    *ReminderConfig    `envconfig:"reminder"`
+	SMTP        *SMTPConfig        `envconfig:"smtp"`
+	Analytics   *AnalyticsConfig   `envconfig:"analytics"`
+	Idempotency *IdempotencyConfig `envconfig:"idempotency"`
 }
 
 type ServerConfig struct {
@@ -101,6 +108,53 @@ type RedisConfig struct {
 	DB       int    `envconfig:"redis_db" default:"0"`
 }
 
+type TaskConfig struct {
+	TrashRetention  time.Duration `envconfig:"trash_retention" default:"720h"` // 30 days
+	PurgeInterval   time.Duration `envconfig:"purge_interval" default:"1h"`
+	BlockCompletion bool          `envconfig:"block_completion" default:"true"` // refuse to complete tasks with open blockers
+	RequireIfMatch  bool          `envconfig:"require_if_match" default:"true"` // answer 428 to task writes without If-Match
+}
+
+type CalendarConfig struct {
+	FeedExpiry time.Duration `envconfig:"feed_expiry" default:"8760h"` // 1 year
+	History    time.Duration `envconfig:"history" default:"2160h"`     // 90 days
+}
+
+type FocusConfig struct {
+	WorkLength   time.Duration `envconfig:"work_length" default:"25m"`
+	BreakLength  time.Duration `envconfig:"break_length" default:"5m"`
+	TickInterval time.Duration `envconfig:"tick_interval" default:"1s"`
+}
+
+type ReminderConfig struct {
+	PollInterval  time.Duration `envconfig:"poll_interval" default:"30s"`
+	BatchSize     int           `envconfig:"batch_size" default:"50"` // reminders delivered per poll and instance
+	MaxAttempts   int           `envconfig:"max_attempts" default:"5"`
+	RetryBackoff  time.Duration `envconfig:"retry_backoff" default:"1m"` // doubled after each failed attempt
+	WebhookSecret string        `envconfig:"webhook_secret"`             // signs webhook payloads when set
+}
+
+// SMTPConfig configures the email notifications, disabled without a host.
+type SMTPConfig struct {
+	Host     string `envconfig:"host"`
+	Port     uint16 `envconfig:"port" default:"587"`
+	Username string `envconfig:"username"`
+	Password string `envconfig:"password"`
+	From     string `envconfig:"from"`
+}
+
+type AnalyticsConfig struct {
+	CacheTTL time.Duration `envconfig:"cache_ttl" default:"10m"`
+}
+
+// IdempotencyConfig configures the replay of POST requests sent with an
+// Idempotency-Key header.
+type IdempotencyConfig struct {
+	TTL          time.Duration `envconfig:"ttl" default:"24h"`                 // how long a response is replayed
+	LockTTL      time.Duration `envconfig:"lock_ttl" default:"1m"`             // how long a request in flight holds its key
+	MaxBodyBytes int64         `envconfig:"max_body_bytes" default:"10485760"` // 10 MiB, the size of the largest import
+}
+
 type PostgresConfig struct {
 	Host     string `envconfig:"host" default:"localhost"`
 	Port     uint16 `envconfig:"port" default:"5432"`
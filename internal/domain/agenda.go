@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// AgendaDay lists what is on a day of the agenda, a date in the time zone of
+// the user. Overdue tasks, due before the day, are only listed on today.
+type AgendaDay struct {
+	Date        string              `json:"date"`
+	Overdue     []*Task             `json:"overdue"`
+	Planned     []*Task             `json:"planned"`
+	Starting    []*Task             `json:"starting"`
+	Due         []*Task             `json:"due"`
+	Occurrences []*AgendaOccurrence `json:"occurrences"`
+}
+
+// AgendaOccurrence is an occurrence of a recurring task that has no task yet.
+// TaskID is the latest task of the series.
+type AgendaOccurrence struct {
+	TaskID     string     `json:"taskID"`
+	SeriesID   string     `json:"seriesID"`
+	Title      string     `json:"title"`
+	Priority   int        `json:"priority"`
+	Occurrence int        `json:"occurrence"`
+	StartDate  *time.Time `json:"startDate"`
+	DueDate    *time.Time `json:"dueDate"`
+}
+
+const (
+	TableDayPlanTasks  = "day_plan_tasks"
+	ColDayPlanOwnerID  = "owner_id"
+	ColDayPlanDay      = "day"
+	ColDayPlanTaskID   = "task_id"
+	ColDayPlanPosition = "position"
+)
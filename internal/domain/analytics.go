@@ -0,0 +1,85 @@
+package domain
+
+import "time"
+
+// AnalyticsFilter selects the tasks of OwnerID completed in [From, To),
+// bucketed by Period in Location.
+type AnalyticsFilter struct {
+	OwnerID  string
+	From     time.Time
+	To       time.Time
+	Period   string
+	Location *time.Location
+}
+
+// Analytics summarises how a user gets through their tasks.
+type Analytics struct {
+	Completed  []*CompletionBucket `json:"completed"`
+	LeadTime   *LeadTime           `json:"leadTime"`
+	Overdue    *OverdueCount       `json:"overdue"`
+	Priorities []*PriorityShare    `json:"priorities"`
+	Streaks    *Streaks            `json:"streaks"`
+}
+
+// CompletionBucket counts the tasks completed in the day or week starting on
+// Date, as YYYY-MM-DD, and in every bucket up to it.
+type CompletionBucket struct {
+	Date       string `json:"date"`
+	Count      int64  `json:"count"`
+	Cumulative int64  `json:"cumulative"`
+}
+
+// LeadTime is the time from creation to completion of the tasks completed in
+// the range.
+type LeadTime struct {
+	Tasks          int64   `json:"tasks"`
+	AverageSeconds float64 `json:"averageSeconds"`
+	MedianSeconds  float64 `json:"medianSeconds"`
+}
+
+// OverdueCount counts the open tasks past their due date, and the tasks
+// completed in the range after theirs.
+type OverdueCount struct {
+	Open          int64 `json:"open"`
+	CompletedLate int64 `json:"completedLate"`
+}
+
+// PriorityShare is the number of open tasks of a priority and their share of
+// all open tasks.
+type PriorityShare struct {
+	Priority int     `json:"priority"`
+	Count    int64   `json:"count"`
+	Share    float64 `json:"share"`
+}
+
+// Streaks are runs of consecutive local days with at least one completed
+// task. Current is the run ending today, or yesterday while today has none.
+type Streaks struct {
+	Current int64 `json:"current"`
+	Longest int64 `json:"longest"`
+}
+
+// BurndownFilter selects the days [From, To) of the burndown of a project,
+// cut in Location.
+type BurndownFilter struct {
+	OwnerID   string
+	ProjectID string
+	From      time.Time
+	To        time.Time
+	Location  *time.Location
+}
+
+// BurndownPoint is a day of a burndown: the tasks created and completed that
+// day, and the tasks still open at its end.
+type BurndownPoint struct {
+	Date      string `json:"date"`
+	Created   int64  `json:"created"`
+	Completed int64  `json:"completed"`
+	Remaining int64  `json:"remaining"`
+}
+
+// AnalyticsPeriod values bucket the completed tasks of a dashboard.
+const (
+	AnalyticsByDay  = "day"
+	AnalyticsByWeek = "week"
+)
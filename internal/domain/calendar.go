@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// CalendarFeed is a subscription URL to the dated tasks of its owner. Its
+// token is a JWT whose ID is the feed ID, so deleting the feed revokes it.
+type CalendarFeed struct {
+	ID        string    `json:"-"`
+	OwnerID   string    `json:"-"`
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// CalendarFeedAudience is the audience of calendar feed tokens, which are
+// not accepted anywhere else.
+const CalendarFeedAudience = "calendar-feed"
+
+// Kinds of calendar components tasks are written as.
+const (
+	CalendarTodo  = "todo"
+	CalendarEvent = "event"
+)
+
+const (
+	TableCalendarFeeds     = "calendar_feeds"
+	ColCalendarFeedOwnerID = "owner_id"
+	ColCalendarExpiresAt   = "expires_at"
+)
+
+var (
+	CalendarFeedAllColumns = []string{
+		ColID,
+		ColCalendarFeedOwnerID,
+		ColCreatedAt,
+		ColCalendarExpiresAt,
+	}
+)
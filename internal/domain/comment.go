@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// Comment is a Markdown comment on a task. Comments reply to a parent comment
+// of the same task and form threads rooted at a top-level comment, ThreadID
+// being the id of that root. HTML is the sanitised rendering of Body, filled
+// in when the comment is read. EditedAt is nil until the body is first edited.
+type Comment struct {
+	ID        string     `json:"id"`
+	TaskID    string     `json:"taskID" db:"task_id"`
+	ThreadID  string     `json:"-" db:"thread_id"`
+	ParentID  *string    `json:"parentID" db:"parent_id"`
+	AuthorID  string     `json:"authorID" db:"author_id"`
+	Body      string     `json:"body"`
+	HTML      string     `json:"html"`
+	Replies   []*Comment `json:"replies"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time  `json:"updatedAt" db:"updated_at"`
+	EditedAt  *time.Time `json:"editedAt" db:"edited_at"`
+}
+
+// CommentRevision is a former body of a comment, replaced at CreatedAt.
+type CommentRevision struct {
+	ID        string    `json:"id"`
+	CommentID string    `json:"commentID" db:"comment_id"`
+	Body      string    `json:"body"`
+	HTML      string    `json:"html"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+const (
+	TableComments      = "task_comments"
+	ColCommentTaskID   = "task_id"
+	ColCommentThreadID = "thread_id"
+	ColCommentParentID = "parent_id"
+	ColCommentAuthorID = "author_id"
+	ColCommentBody     = "body"
+	ColCommentEditedAt = "edited_at"
+
+	TableCommentRevisions     = "task_comment_revisions"
+	ColCommentRevisionComment = "comment_id"
+	ColCommentRevisionBody    = "body"
+)
+
+var (
+	CommentAllColumns = []string{
+		ColID,
+		ColCommentTaskID,
+		ColCommentThreadID,
+		ColCommentParentID,
+		ColCommentAuthorID,
+		ColCommentBody,
+		ColCreatedAt,
+		ColUpdatedAt,
+		ColCommentEditedAt,
+	}
+
+	CommentRevisionAllColumns = []string{
+		ColID,
+		ColCommentRevisionComment,
+		ColCommentRevisionBody,
+		ColCreatedAt,
+	}
+)
@@ -1,7 +1,43 @@
 package domain
 
+import "encoding/json"
+
 type Pagination struct {
 	Page      uint64 `json:"page"`
 	PageToken string `json:"pageToken"`
 	PageSize  uint64 `json:"pageSize"`
 }
+
+// PageTokens are the opaque cursors pointing to the pages around the current one.
+type PageTokens struct {
+	Next string
+	Prev string
+}
+
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// Optional tells apart a JSON field that is absent (Set == false) from one that is
+// present, possibly as an explicit null (Null == true). It is meant for RFC 7396
+// merge-patch documents where null means "remove".
+type Optional[T any] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+
+	if string(data) == "null" {
+		var zero T
+		o.Value = zero
+		o.Null = true
+		return nil
+	}
+
+	o.Null = false
+	return json.Unmarshal(data, &o.Value)
+}
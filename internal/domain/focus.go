@@ -0,0 +1,99 @@
+package domain
+
+import "time"
+
+// FocusSession is a pomodoro run against a task: a work phase followed by a
+// break. It is saved when the work phase ends, Completed telling whether it
+// ran its full length or was stopped early. Phase and PhaseEndsAt are only
+// set while the session runs.
+type FocusSession struct {
+	ID             string     `json:"id"`
+	TaskID         string     `json:"taskID" db:"task_id"`
+	OwnerID        string     `json:"ownerID" db:"owner_id"`
+	Phase          string     `json:"phase,omitempty"`
+	PhaseEndsAt    *time.Time `json:"phaseEndsAt,omitempty"`
+	WorkSeconds    int        `json:"workSeconds" db:"work_seconds"`
+	BreakSeconds   int        `json:"breakSeconds" db:"break_seconds"`
+	FocusedSeconds int        `json:"focusedSeconds" db:"focused_seconds"`
+	Interruptions  int        `json:"interruptions"`
+	Completed      bool       `json:"completed"`
+	StartedAt      time.Time  `json:"startedAt" db:"started_at"`
+	EndedAt        *time.Time `json:"endedAt" db:"ended_at"`
+}
+
+// FocusTick is pushed to the user on every tick of a running session.
+type FocusTick struct {
+	Session          *FocusSession `json:"session"`
+	RemainingSeconds int           `json:"remainingSeconds"`
+}
+
+// FocusStatsFilter selects the sessions of OwnerID started in [From, To),
+// optionally of a single task, bucketed by Period in Location.
+type FocusStatsFilter struct {
+	OwnerID  string
+	TaskID   *string
+	From     time.Time
+	To       time.Time
+	Period   string
+	Location *time.Location
+}
+
+// FocusStats sums up the sessions of a task started in a period, the day or
+// the Monday of the week as YYYY-MM-DD.
+type FocusStats struct {
+	Period         string `json:"period"`
+	TaskID         string `json:"taskID"`
+	TaskTitle      string `json:"taskTitle"`
+	Sessions       int    `json:"sessions"`
+	Completed      int    `json:"completed"`
+	FocusedSeconds int64  `json:"focusedSeconds"`
+	Interruptions  int    `json:"interruptions"`
+}
+
+// FocusPhase values.
+const (
+	FocusPhaseWork  = "work"
+	FocusPhaseBreak = "break"
+	FocusPhaseDone  = "done"
+)
+
+// FocusEvent values name the messages pushed about a session.
+const (
+	FocusEventTick        = "focus.tick"
+	FocusEventPhase       = "focus.phase"
+	FocusEventInterrupted = "focus.interrupted"
+)
+
+// FocusPeriod values bucket focus statistics.
+const (
+	FocusPeriodDay  = "day"
+	FocusPeriodWeek = "week"
+)
+
+const (
+	TableFocusSessions     = "focus_sessions"
+	ColFocusTaskID         = "task_id"
+	ColFocusOwnerID        = "owner_id"
+	ColFocusWorkSeconds    = "work_seconds"
+	ColFocusBreakSeconds   = "break_seconds"
+	ColFocusFocusedSeconds = "focused_seconds"
+	ColFocusInterruptions  = "interruptions"
+	ColFocusCompleted      = "completed"
+	ColFocusStartedAt      = "started_at"
+	ColFocusEndedAt        = "ended_at"
+)
+
+var (
+	FocusSessionAllColumns = []string{
+		ColID,
+		ColFocusTaskID,
+		ColFocusOwnerID,
+		ColFocusWorkSeconds,
+		ColFocusBreakSeconds,
+		ColFocusFocusedSeconds,
+		ColFocusInterruptions,
+		ColFocusCompleted,
+		ColFocusStartedAt,
+		ColFocusEndedAt,
+	}
+)
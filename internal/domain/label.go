@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+type Label struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	OwnerID   string    `json:"ownerID" db:"owner_id"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+const (
+	TableLabels     = "labels"
+	ColLabelName    = "name"
+	ColLabelColor   = "color"
+	ColLabelOwnerID = "owner_id"
+
+	TableTaskLabels = "task_labels"
+	ColTaskID       = "task_id"
+	ColLabelID      = "label_id"
+
+	LabelMatchAny = "any"
+	LabelMatchAll = "all"
+)
+
+var (
+	LabelAllColumns = []string{
+		ColID,
+		ColLabelName,
+		ColLabelColor,
+		ColLabelOwnerID,
+		ColCreatedAt,
+		ColUpdatedAt,
+	}
+)
+
+// LabelRefs turns label ids into labels carrying only their id.
+func LabelRefs(ids []string) []*Label {
+	labels := make([]*Label, 0, len(ids))
+	for _, id := range ids {
+		labels = append(labels, &Label{ID: id})
+	}
+
+	return labels
+}
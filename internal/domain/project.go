@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// Project groups tasks. Archiving a project hides its tasks from the default
+// task listings. TaskCount and OpenTaskCount are computed when reading.
+type Project struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	Color         string    `json:"color"`
+	IsArchived    bool      `json:"isArchived" db:"is_archived"`
+	SortOrder     int       `json:"sortOrder" db:"sort_order"`
+	OwnerID       string    `json:"ownerID" db:"owner_id"`
+	TaskCount     int       `json:"taskCount"`
+	OpenTaskCount int       `json:"openTaskCount"`
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+const (
+	TableProjects         = "projects"
+	ColProjectName        = "name"
+	ColProjectDescription = "description"
+	ColProjectColor       = "color"
+	ColProjectIsArchived  = "is_archived"
+	ColProjectSortOrder   = "sort_order"
+	ColProjectOwnerID     = "owner_id"
+	ProjectFilterNone     = "none"
+)
+
+var (
+	ProjectAllColumns = []string{
+		ColID,
+		ColProjectName,
+		ColProjectDescription,
+		ColProjectColor,
+		ColProjectIsArchived,
+		ColProjectSortOrder,
+		ColProjectOwnerID,
+		ColCreatedAt,
+		ColUpdatedAt,
+	}
+)
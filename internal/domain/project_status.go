@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// ProjectStatus is a column of the board of a project, tasks moving through
+// the columns in Position order. Moving a task into a column marked IsDone
+// completes it, and moving it into another column reopens it.
+type ProjectStatus struct {
+	ID        string    `json:"id"`
+	ProjectID string    `json:"projectID" db:"project_id"`
+	OwnerID   string    `json:"ownerID" db:"owner_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	IsDone    bool      `json:"isDone" db:"is_done"`
+	Position  string    `json:"position"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// BoardColumn is a status of a project with its tasks in board order. The
+// tasks without status are gathered in a column with a nil Status.
+type BoardColumn struct {
+	Status *ProjectStatus `json:"status"`
+	Tasks  []*Task        `json:"tasks"`
+}
+
+// Placement puts an item of an ordered list right after AfterID and/or right
+// before BeforeID. With neither set, the item goes to the end of the list.
+type Placement struct {
+	AfterID  *string
+	BeforeID *string
+}
+
+const (
+	TableProjectStatuses = "project_statuses"
+	ColStatusProjectID   = "project_id"
+	ColStatusOwnerID     = "owner_id"
+	ColStatusName        = "name"
+	ColStatusColor       = "color"
+	ColStatusIsDone      = "is_done"
+	ColStatusPosition    = "position"
+)
+
+var (
+	ProjectStatusAllColumns = []string{
+		ColID,
+		ColStatusProjectID,
+		ColStatusOwnerID,
+		ColStatusName,
+		ColStatusColor,
+		ColStatusIsDone,
+		ColStatusPosition,
+		ColCreatedAt,
+		ColUpdatedAt,
+	}
+)
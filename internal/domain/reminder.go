@@ -0,0 +1,109 @@
+package domain
+
+import "time"
+
+// Reminder notifies the owner of a task at RemindAt, or OffsetMinutes before
+// the due date of the task, through each of its channels. Reminders of
+// completed or trashed tasks wait until the task is reopened or restored.
+// Failed deliveries are retried at NextAttemptAt until the attempts run out.
+type Reminder struct {
+	ID            string     `json:"id"`
+	TaskID        string     `json:"taskID" db:"task_id"`
+	OwnerID       string     `json:"ownerID" db:"owner_id"`
+	RemindAt      *time.Time `json:"remindAt" db:"remind_at"`
+	OffsetMinutes *int       `json:"offsetMinutes" db:"offset_minutes"`
+	Channels      []string   `json:"channels"`
+	WebhookURL    string     `json:"webhookURL,omitempty" db:"webhook_url"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt" db:"next_attempt_at"`
+	SentAt        *time.Time `json:"sentAt" db:"sent_at"`
+	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// ReminderDelivery logs an attempt to deliver a reminder through a channel.
+// Error is the category of a failure, such as timeout or http_5xx.
+type ReminderDelivery struct {
+	ID         string    `json:"id"`
+	ReminderID string    `json:"reminderID" db:"reminder_id"`
+	Channel    string    `json:"channel"`
+	Attempt    int       `json:"attempt"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Notification is what a reminder tells its owner, Email being the address
+// of the owner.
+type Notification struct {
+	Reminder *Reminder `json:"reminder"`
+	Task     *Task     `json:"task"`
+	Email    string    `json:"-"`
+}
+
+// ReminderChannel values name the ways a reminder is delivered.
+const (
+	ReminderChannelWS      = "ws"
+	ReminderChannelEmail   = "email"
+	ReminderChannelWebhook = "webhook"
+)
+
+// ReminderStatus values.
+const (
+	ReminderStatusPending = "pending"
+	ReminderStatusSent    = "sent"
+	ReminderStatusFailed  = "failed"
+)
+
+// ReminderEvent names the message pushed over the WebSocket.
+const ReminderEvent = "reminder"
+
+const (
+	TableReminders           = "task_reminders"
+	ColReminderTaskID        = "task_id"
+	ColReminderOwnerID       = "owner_id"
+	ColReminderRemindAt      = "remind_at"
+	ColReminderOffsetMinutes = "offset_minutes"
+	ColReminderChannels      = "channels"
+	ColReminderWebhookURL    = "webhook_url"
+	ColReminderStatus        = "status"
+	ColReminderAttempts      = "attempts"
+	ColReminderNextAttemptAt = "next_attempt_at"
+	ColReminderSentAt        = "sent_at"
+
+	TableReminderDeliveries = "reminder_deliveries"
+	ColDeliveryReminderID   = "reminder_id"
+	ColDeliveryChannel      = "channel"
+	ColDeliveryAttempt      = "attempt"
+	ColDeliveryStatus       = "status"
+	ColDeliveryError        = "error"
+)
+
+var (
+	ReminderAllColumns = []string{
+		ColID,
+		ColReminderTaskID,
+		ColReminderOwnerID,
+		ColReminderRemindAt,
+		ColReminderOffsetMinutes,
+		ColReminderChannels,
+		ColReminderWebhookURL,
+		ColReminderStatus,
+		ColReminderAttempts,
+		ColReminderNextAttemptAt,
+		ColReminderSentAt,
+		ColCreatedAt,
+		ColUpdatedAt,
+	}
+
+	ReminderDeliveryAllColumns = []string{
+		ColID,
+		ColDeliveryReminderID,
+		ColDeliveryChannel,
+		ColDeliveryAttempt,
+		ColDeliveryStatus,
+		ColDeliveryError,
+		ColCreatedAt,
+	}
+)
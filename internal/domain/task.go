@@ -2,17 +2,284 @@ package domain
 
 import "time"
 
+// Task is a to-do item of a user. CommentCount, the ids of the tasks it is
+// blocked by and blocks, and TrackedSeconds, the time logged against the task
+// to compare with EstimateMinutes, are loaded when reading. Version is bumped
+// by the database on every write and serves as the ETag of the task.
+// TimeZone, given when writing a recurring task, is the zone a new series of
+// the task is expanded in.
 type Task struct {
-	ID          string     `json:"id"`
-	Title       string     `json:"title"`
-	Details     string     `json:"details"`
-	Priority    int        `json:"priority"`
-	IsCompleted bool       `json:"isCompleted" db:"is_completed"`
-	StartDate   *time.Time `json:"startDate" db:"start_date"`
-	DueDate     *time.Time `json:"dueDate" db:"due_date"`
-	OwnerID     string     `json:"ownerID" db:"owner_id"`
-	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
+	ID              string     `json:"id"`
+	Title           string     `json:"title"`
+	Details         string     `json:"details"`
+	Priority        int        `json:"priority"`
+	IsCompleted     bool       `json:"isCompleted" db:"is_completed"`
+	StartDate       *time.Time `json:"startDate" db:"start_date"`
+	DueDate         *time.Time `json:"dueDate" db:"due_date"`
+	OwnerID         string     `json:"ownerID" db:"owner_id"`
+	ParentID        *string    `json:"parentID" db:"parent_id"`
+	ProjectID       *string    `json:"projectID" db:"project_id"`
+	SeriesID        *string    `json:"seriesID" db:"series_id"`
+	Occurrence      int        `json:"occurrence"`
+	RRule           string     `json:"rrule"`
+	TimeZone        string     `json:"timezone,omitempty"`
+	StatusID        *string    `json:"statusID" db:"status_id"`
+	Position        string     `json:"position"`
+	EstimateMinutes *int       `json:"estimateMinutes" db:"estimate_minutes"`
+	TrackedSeconds  int64      `json:"trackedSeconds"`
+	Labels          []*Label   `json:"labels"`
+	CommentCount    int        `json:"commentCount"`
+	BlockedBy       []string   `json:"blockedBy"`
+	Blocks          []string   `json:"blocks"`
+	CreatedAt       time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updatedAt" db:"updated_at"`
+	DeletedAt       *time.Time `json:"deletedAt,omitempty" db:"deleted_at"`
+	Version         int64      `json:"version"`
+}
+
+// LabelIDs returns the ids of the labels attached to the task.
+func (t *Task) LabelIDs() []string {
+	ids := make([]string, 0, len(t.Labels))
+	for _, label := range t.Labels {
+		ids = append(ids, label.ID)
+	}
+
+	return ids
+}
+
+// TaskNode is a task with its subtasks. Progress is the percentage of completed
+// descendants, or 0/100 for a task without subtasks depending on its own state.
+type TaskNode struct {
+	*Task
+	Progress float64     `json:"progress"`
+	Subtasks []*TaskNode `json:"subtasks"`
+}
+
+// TaskFilter narrows down and orders the tasks of a single owner.
+// Nil pointers and empty values mean "no constraint", except that tasks of
+// archived projects are left out unless IncludeArchived is set or the
+// project is selected explicitly. Trashed selects the trash instead of the
+// live tasks.
+type TaskFilter struct {
+	OwnerID         string
+	Trashed         bool
+	ProjectID       *string
+	NoProject       bool
+	IncludeArchived bool
+	IsCompleted     *bool
+	MinPriority     *int
+	MaxPriority     *int
+	StartFrom       *time.Time
+	StartTo         *time.Time
+	DueFrom         *time.Time
+	DueTo           *time.Time
+	Overdue         bool
+	Search          string
+	LabelIDs        []string
+	LabelMatch      string
+	Sort            []SortField
+}
+
+// TaskDueWindow values select common due date ranges.
+const (
+	TaskDueOverdue  = "overdue"
+	TaskDueToday    = "today"
+	TaskDueThisWeek = "week"
+)
+
+// TaskSortColumns whitelists the sort keys accepted from clients and maps them to columns.
+var TaskSortColumns = map[string]string{
+	"title":     ColTaskTitle,
+	"priority":  ColTaskPriority,
+	"startDate": ColTaskStartDate,
+	"dueDate":   ColTaskDueDate,
+	"createdAt": ColCreatedAt,
+	"updatedAt": ColUpdatedAt,
+	"deletedAt": ColTaskDeletedAt,
+	"position":  ColTaskPosition,
+}
+
+// TaskSearch is a parsed full-text query. All terms must match.
+type TaskSearch struct {
+	OwnerID  string
+	Words    []string
+	Phrases  []string
+	Prefixes []string
+}
+
+func (s *TaskSearch) IsEmpty() bool {
+	return len(s.Words) == 0 && len(s.Phrases) == 0 && len(s.Prefixes) == 0
+}
+
+// TaskSearchResult is a task matched by a full-text search. The highlights are
+// HTML escaped with the matched terms wrapped in <mark> tags.
+type TaskSearchResult struct {
+	Task             *Task   `json:"task"`
+	Rank             float32 `json:"rank"`
+	TitleHighlight   string  `json:"titleHighlight"`
+	DetailsHighlight string  `json:"detailsHighlight"`
+}
+
+// Markers around matched terms in raw search highlights, from the Unicode
+// private use area so they never clash with HTML or user text.
+const (
+	HighlightStart = "\ue000"
+	HighlightStop  = "\ue001"
+)
+
+// TaskCursor marks a position in a sorted task listing for keyset pagination.
+// Only the ID and the sorted columns of Last are meaningful.
+type TaskCursor struct {
+	Last     Task   `json:"last"`
+	Sort     string `json:"sort"`
+	Backward bool   `json:"backward,omitempty"`
+}
+
+// ColumnValue returns the value of the given sortable column, or nil when
+// the column is unknown or holds SQL NULL.
+func (t *Task) ColumnValue(column string) any {
+	switch column {
+	case ColID:
+		return t.ID
+	case ColTaskTitle:
+		return t.Title
+	case ColTaskPriority:
+		return t.Priority
+	case ColTaskStartDate:
+		if t.StartDate == nil {
+			return nil
+		}
+		return *t.StartDate
+	case ColTaskDueDate:
+		if t.DueDate == nil {
+			return nil
+		}
+		return *t.DueDate
+	case ColTaskPosition:
+		return t.Position
+	case ColCreatedAt:
+		return t.CreatedAt
+	case ColUpdatedAt:
+		return t.UpdatedAt
+	case ColTaskDeletedAt:
+		if t.DeletedAt == nil {
+			return nil
+		}
+		return *t.DeletedAt
+	default:
+		return nil
+	}
+}
+
+// SortKey returns a task holding only the ID and the sortable columns of t,
+// enough to resume a listing after t whatever its order.
+func (t *Task) SortKey() Task {
+	return Task{
+		ID:        t.ID,
+		Title:     t.Title,
+		Priority:  t.Priority,
+		StartDate: t.StartDate,
+		DueDate:   t.DueDate,
+		Position:  t.Position,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+		DeletedAt: t.DeletedAt,
+	}
+}
+
+// TaskPatch is a JSON merge patch (RFC 7396) document for a task.
+type TaskPatch struct {
+	Title           Optional[string]     `json:"title"`
+	Details         Optional[string]     `json:"details"`
+	Priority        Optional[int]        `json:"priority"`
+	IsCompleted     Optional[bool]       `json:"isCompleted"`
+	StartDate       Optional[*time.Time] `json:"startDate"`
+	DueDate         Optional[*time.Time] `json:"dueDate"`
+	ParentID        Optional[*string]    `json:"parentID"`
+	ProjectID       Optional[*string]    `json:"projectID"`
+	RRule           Optional[*string]    `json:"rrule"`
+	TimeZone        Optional[string]     `json:"timezone"`
+	LabelIDs        Optional[[]string]   `json:"labelIDs"`
+	EstimateMinutes Optional[*int]       `json:"estimateMinutes"`
+}
+
+// Apply merges the patch into task in place.
+func (p *TaskPatch) Apply(task *Task) {
+	if p.Title.Set {
+		task.Title = p.Title.Value
+	}
+	if p.Details.Set {
+		task.Details = p.Details.Value
+	}
+	if p.Priority.Set {
+		task.Priority = p.Priority.Value
+	}
+	if p.IsCompleted.Set {
+		task.IsCompleted = p.IsCompleted.Value
+	}
+	if p.StartDate.Set {
+		task.StartDate = p.StartDate.Value
+	}
+	if p.DueDate.Set {
+		task.DueDate = p.DueDate.Value
+	}
+	if p.ParentID.Set {
+		task.ParentID = p.ParentID.Value
+	}
+	if p.ProjectID.Set {
+		task.ProjectID = p.ProjectID.Value
+	}
+	if p.RRule.Set {
+		task.RRule = ""
+		if p.RRule.Value != nil {
+			task.RRule = *p.RRule.Value
+		}
+	}
+	if p.TimeZone.Set {
+		task.TimeZone = p.TimeZone.Value
+	}
+	if p.LabelIDs.Set {
+		task.Labels = LabelRefs(p.LabelIDs.Value)
+	}
+	if p.EstimateMinutes.Set {
+		task.EstimateMinutes = p.EstimateMinutes.Value
+	}
+}
+
+// Changes returns the columns touched by the patch mapped to their new values.
+// Labels, the recurrence rule and its time zone are not columns of the task
+// and are left out.
+func (p *TaskPatch) Changes() map[string]any {
+	changes := make(map[string]any)
+	if p.Title.Set {
+		changes[ColTaskTitle] = p.Title.Value
+	}
+	if p.Details.Set {
+		changes[ColTaskDetails] = p.Details.Value
+	}
+	if p.Priority.Set {
+		changes[ColTaskPriority] = p.Priority.Value
+	}
+	if p.IsCompleted.Set {
+		changes[ColTaskIsCompleted] = p.IsCompleted.Value
+	}
+	if p.StartDate.Set {
+		changes[ColTaskStartDate] = p.StartDate.Value
+	}
+	if p.DueDate.Set {
+		changes[ColTaskDueDate] = p.DueDate.Value
+	}
+	if p.ParentID.Set {
+		changes[ColTaskParentID] = p.ParentID.Value
+	}
+	if p.ProjectID.Set {
+		changes[ColTaskProjectID] = p.ProjectID.Value
+	}
+	if p.EstimateMinutes.Set {
+		changes[ColTaskEstimate] = p.EstimateMinutes.Value
+	}
+
+	return changes
 }
 
 const (
@@ -24,6 +291,17 @@ const (
 	ColTaskStartDate   = "start_date"
 	ColTaskDueDate     = "due_date"
 	ColTaskOwnerID     = "owner_id"
+	ColTaskParentID    = "parent_id"
+	ColTaskProjectID   = "project_id"
+	ColTaskSeriesID    = "series_id"
+	ColTaskOccurrence  = "occurrence"
+	ColTaskStatusID    = "status_id"
+	ColTaskPosition    = "position"
+	ColTaskEstimate    = "estimate_minutes"
+	ColTaskDeletedAt   = "deleted_at"
+	ColTaskSearch      = "search_vector"
+	ColTaskCompletedAt = "completed_at" // maintained by a trigger, see migrations
+	ColTaskVersion     = "version"      // bumped by a trigger on every update
 )
 
 var (
@@ -36,7 +314,16 @@ var (
 		ColTaskStartDate,
 		ColTaskDueDate,
 		ColTaskOwnerID,
+		ColTaskParentID,
+		ColTaskProjectID,
+		ColTaskSeriesID,
+		ColTaskOccurrence,
+		ColTaskStatusID,
+		ColTaskPosition,
+		ColTaskEstimate,
 		ColCreatedAt,
 		ColUpdatedAt,
+		ColTaskDeletedAt,
+		ColTaskVersion,
 	}
 )
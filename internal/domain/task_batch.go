@@ -0,0 +1,50 @@
+package domain
+
+// Modes of a batch of task operations. An atomic batch applies every item or
+// none of them, a best-effort batch applies every item that succeeds.
+const (
+	TaskBatchAtomic     = "atomic"
+	TaskBatchBestEffort = "bestEffort"
+)
+
+// Operations applicable to tasks in a batch.
+const (
+	TaskOpComplete   = "complete"
+	TaskOpReopen     = "reopen"
+	TaskOpPrioritise = "prioritise"
+	TaskOpRelabel    = "relabel"
+	TaskOpMove       = "move"
+	TaskOpDelete     = "delete"
+)
+
+// Outcomes of a batch item. Applied items of an atomic batch that failed are
+// rolled back, and the items after the failure are skipped.
+const (
+	TaskBatchOK         = "ok"
+	TaskBatchFailed     = "failed"
+	TaskBatchRolledBack = "rolledBack"
+	TaskBatchSkipped    = "skipped"
+)
+
+// TaskBatchOperation applies Op to every task of IDs. Priority is used by
+// prioritise, AddLabelIDs and RemoveLabelIDs by relabel, and ProjectID by
+// move, where nil moves the tasks out of their project. A task with an entry
+// in Versions is only written if it is still at that version.
+type TaskBatchOperation struct {
+	Op             string
+	IDs            []string
+	Versions       map[string]int64
+	Priority       int
+	AddLabelIDs    []string
+	RemoveLabelIDs []string
+	ProjectID      *string
+}
+
+// TaskBatchResult is the outcome of an operation on one task, Operation being
+// the index of the operation in the batch.
+type TaskBatchResult struct {
+	Operation int
+	ID        string
+	Status    string
+	Err       error
+}
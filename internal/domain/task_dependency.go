@@ -0,0 +1,16 @@
+package domain
+
+// NextTask is an open task in the order work can be done. Level is the length
+// of the longest chain of open tasks blocking it, so tasks of level 0 can be
+// started right away.
+type NextTask struct {
+	*Task
+	Level int `json:"level"`
+}
+
+const (
+	TableTaskDependencies  = "task_dependencies"
+	ColDependencyTaskID    = "task_id"
+	ColDependencyBlockerID = "blocker_id"
+	ColDependencyOwnerID   = "owner_id"
+)
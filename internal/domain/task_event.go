@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// TaskEvent is an entry of the append-only history of a task. Changes maps
+// the JSON names of the fields that changed to their values before and after.
+// ActorID is nil for changes made by the system.
+type TaskEvent struct {
+	ID        string                  `json:"id"`
+	TaskID    string                  `json:"taskID"`
+	OwnerID   string                  `json:"-"`
+	ActorID   *string                 `json:"actorID"`
+	Type      string                  `json:"type"`
+	Changes   map[string]*FieldChange `json:"changes"`
+	RequestID string                  `json:"requestID,omitempty"`
+	CreatedAt time.Time               `json:"createdAt"`
+}
+
+// FieldChange is the value of a field before and after a change.
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Types of task events.
+const (
+	TaskEventCreated   = "created"
+	TaskEventUpdated   = "updated"
+	TaskEventCompleted = "completed"
+	TaskEventReopened  = "reopened"
+	TaskEventDeleted   = "deleted"
+	TaskEventRestored  = "restored"
+)
+
+const (
+	TableTaskEvents       = "task_events"
+	ColTaskEventTaskID    = "task_id"
+	ColTaskEventOwnerID   = "owner_id"
+	ColTaskEventActorID   = "actor_id"
+	ColTaskEventType      = "type"
+	ColTaskEventChanges   = "changes"
+	ColTaskEventRequestID = "request_id"
+)
+
+// taskEventFieldCompleted is the field whose change names an update.
+const taskEventFieldCompleted = "isCompleted"
+
+var (
+	TaskEventAllColumns = []string{
+		ColID,
+		ColTaskEventTaskID,
+		ColTaskEventOwnerID,
+		ColTaskEventActorID,
+		ColTaskEventType,
+		ColTaskEventChanges,
+		ColTaskEventRequestID,
+		ColCreatedAt,
+	}
+)
+
+// TaskChanges lists the fields that differ between two versions of a task.
+// A nil before stands for a task being created, whose fields holding a value
+// are all listed.
+func TaskChanges(before, after *Task) map[string]*FieldChange {
+	var from map[string]any
+	if before != nil {
+		from = auditedFields(before)
+	}
+
+	changes := make(map[string]*FieldChange)
+	for name, to := range auditedFields(after) {
+		old, ok := from[name]
+		if !ok && before == nil && isZeroField(to) {
+			continue
+		}
+		if ok && slices.Equal(fieldKey(old), fieldKey(to)) {
+			continue
+		}
+		changes[name] = &FieldChange{From: old, To: to}
+	}
+
+	return changes
+}
+
+// TaskEventType names an update by its most significant change.
+func TaskEventType(changes map[string]*FieldChange) string {
+	change, ok := changes[taskEventFieldCompleted]
+	switch {
+	case !ok:
+		return TaskEventUpdated
+	case change.To == true:
+		return TaskEventCompleted
+	default:
+		return TaskEventReopened
+	}
+}
+
+// auditedFields returns the fields of a task recorded in its history, keyed
+// by their JSON names, with pointers dereferenced.
+func auditedFields(t *Task) map[string]any {
+	labelIDs := t.LabelIDs()
+	slices.Sort(labelIDs)
+
+	return map[string]any{
+		"title":                 t.Title,
+		"details":               t.Details,
+		"priority":              t.Priority,
+		taskEventFieldCompleted: t.IsCompleted,
+		"startDate":             timeField(t.StartDate),
+		"dueDate":               timeField(t.DueDate),
+		"parentID":              stringField(t.ParentID),
+		"projectID":             stringField(t.ProjectID),
+		"statusID":              stringField(t.StatusID),
+		"estimateMinutes":       intField(t.EstimateMinutes),
+		"labelIDs":              labelIDs,
+		"rrule":                 t.RRule,
+	}
+}
+
+func timeField(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+
+	return t.UTC()
+}
+
+func intField(i *int) any {
+	if i == nil {
+		return nil
+	}
+
+	return *i
+}
+
+func stringField(s *string) any {
+	if s == nil {
+		return nil
+	}
+
+	return *s
+}
+
+func isZeroField(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case bool:
+		return !v
+	case []string:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// fieldKey turns a field value into comparable strings.
+func fieldKey(v any) []string {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []string:
+		return append([]string{"labels"}, v...)
+	case time.Time:
+		return []string{v.Format(time.RFC3339Nano)}
+	}
+
+	return []string{fmt.Sprint(v)}
+}
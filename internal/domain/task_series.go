@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// TaskSeries is the template of a recurring task. Occurrence n of the series
+// is scheduled at the nth instance of RRule starting at DTStart, occurrence 0
+// being DTStart itself, and its dates are offset from that instant.
+// LastOccurrence, when set, ends the series early. The rule is expanded in
+// TimeZone, so that occurrences keep their wall clock time across DST changes.
+type TaskSeries struct {
+	ID             string
+	OwnerID        string
+	RRule          string
+	DTStart        time.Time
+	TimeZone       string
+	LastOccurrence *int
+	Title          string
+	Details        string
+	Priority       int
+	ProjectID      *string
+	ParentID       *string
+	LabelIDs       []string
+	StartOffset    *time.Duration
+	DueOffset      *time.Duration
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TaskOccurrence is a scheduled occurrence of a recurring task.
+type TaskOccurrence struct {
+	Occurrence int        `json:"occurrence"`
+	StartDate  *time.Time `json:"startDate"`
+	DueDate    *time.Time `json:"dueDate"`
+}
+
+// Scopes of an edit made to an occurrence of a recurring task.
+const (
+	TaskScopeThis   = "this"
+	TaskScopeFuture = "future"
+)
+
+const (
+	TableTaskSeries         = "task_series"
+	ColSeriesOwnerID        = "owner_id"
+	ColSeriesRRule          = "rrule"
+	ColSeriesDTStart        = "dtstart"
+	ColSeriesLastOccurrence = "last_occurrence"
+	ColSeriesTitle          = "title"
+	ColSeriesDetails        = "details"
+	ColSeriesPriority       = "priority"
+	ColSeriesProjectID      = "project_id"
+	ColSeriesParentID       = "parent_id"
+	ColSeriesLabelIDs       = "label_ids"
+	ColSeriesStartOffset    = "start_offset"
+	ColSeriesDueOffset      = "due_offset"
+	ColSeriesTimeZone       = "timezone"
+)
+
+var (
+	TaskSeriesAllColumns = []string{
+		ColID,
+		ColSeriesOwnerID,
+		ColSeriesRRule,
+		ColSeriesDTStart,
+		ColSeriesLastOccurrence,
+		ColSeriesTitle,
+		ColSeriesDetails,
+		ColSeriesPriority,
+		ColSeriesProjectID,
+		ColSeriesParentID,
+		ColSeriesLabelIDs,
+		ColSeriesStartOffset,
+		ColSeriesDueOffset,
+		ColSeriesTimeZone,
+		ColCreatedAt,
+		ColUpdatedAt,
+	}
+)
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Formats tasks are imported from and exported to.
+const (
+	TaskFormatCSV     = "csv"
+	TaskFormatJSONL   = "jsonl"
+	TaskFormatTodoTxt = "todotxt"
+)
+
+// TaskRecord is the portable form of a task used by imports and exports. Its
+// project and labels are referenced by name, and Line is the line of the
+// imported file it was read from.
+type TaskRecord struct {
+	Line        int        `json:"-"`
+	Title       string     `json:"title"`
+	Details     string     `json:"details,omitempty"`
+	Priority    int        `json:"priority"`
+	IsCompleted bool       `json:"isCompleted"`
+	StartDate   *time.Time `json:"startDate,omitempty"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+	Project     string     `json:"project,omitempty"`
+	Labels      []string   `json:"labels,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// TaskImportError reports why a line of an imported file was rejected.
+type TaskImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (e *TaskImportError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Validate checks a record against the limits of the columns it is stored in.
+func (r *TaskRecord) Validate() error {
+	switch {
+	case strings.TrimSpace(r.Title) == "":
+		return errors.New("title is required")
+	case utf8.RuneCountInString(r.Title) > 255:
+		return errors.New("title is longer than 255 characters")
+	case r.Priority < 0:
+		return errors.New("priority can not be negative")
+	case utf8.RuneCountInString(r.Project) > 128:
+		return errors.New("project name is longer than 128 characters")
+	}
+
+	for _, label := range r.Labels {
+		if strings.TrimSpace(label) == "" || utf8.RuneCountInString(label) > 64 {
+			return fmt.Errorf("label %q must have 1 to 64 characters", label)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// TimeEntry is a span of time spent by a user on a task, recorded by a timer
+// or entered by hand as given by Source.
+type TimeEntry struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"taskID" db:"task_id"`
+	OwnerID   string    `json:"ownerID" db:"owner_id"`
+	StartedAt time.Time `json:"startedAt" db:"started_at"`
+	EndedAt   time.Time `json:"endedAt" db:"ended_at"`
+	Seconds   int64     `json:"seconds"`
+	Note      string    `json:"note"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Timer is the running timer of a user. A user has at most one, and stopping
+// it turns it into a TimeEntry.
+type Timer struct {
+	TaskID    string    `json:"taskID"`
+	OwnerID   string    `json:"ownerID"`
+	StartedAt time.Time `json:"startedAt"`
+	Note      string    `json:"note"`
+}
+
+// TimeReportFilter selects the time entries of OwnerID overlapping
+// [From, To), only the overlap being counted. Days are cut in Location.
+type TimeReportFilter struct {
+	OwnerID  string
+	From     time.Time
+	To       time.Time
+	GroupBy  string
+	Location *time.Location
+}
+
+// TimeReportRow is the time tracked in a group of a report. Key is the day
+// as YYYY-MM-DD, or the id of the project or label with Name its name; both
+// are nil for the time of tasks without project or label.
+type TimeReportRow struct {
+	Key     *string `json:"key"`
+	Name    *string `json:"name,omitempty"`
+	Seconds int64   `json:"seconds"`
+}
+
+// TimeEntrySource values tell how an entry was recorded.
+const (
+	TimeEntrySourceTimer  = "timer"
+	TimeEntrySourceManual = "manual"
+)
+
+// TimeReportGroup values group the tracked time of a report.
+const (
+	TimeReportByDay     = "day"
+	TimeReportByProject = "project"
+	TimeReportByLabel   = "label"
+)
+
+const (
+	TableTimeEntries      = "time_entries"
+	ColTimeEntryTaskID    = "task_id"
+	ColTimeEntryOwnerID   = "owner_id"
+	ColTimeEntryStartedAt = "started_at"
+	ColTimeEntryEndedAt   = "ended_at"
+	ColTimeEntryNote      = "note"
+	ColTimeEntrySource    = "source"
+)
+
+var (
+	TimeEntryAllColumns = []string{
+		ColID,
+		ColTimeEntryTaskID,
+		ColTimeEntryOwnerID,
+		ColTimeEntryStartedAt,
+		ColTimeEntryEndedAt,
+		ColTimeEntryNote,
+		ColTimeEntrySource,
+		ColCreatedAt,
+		ColUpdatedAt,
+	}
+)
@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+
+	goredis "github.com/redis/go-redis/v9"
+	"gitlab.com/jodworkspace/mvp/config"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/redis"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+const (
+	// IdempotencyKeyHeader carries the key a client picks for a POST request
+	// and sends again on its retries.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from a former request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyPrefix = "idempotency:"
+	maxIdempotencyKey    = 255
+)
+
+// replayedHeaders are the headers of a stored response sent again on replay.
+// Others, such as Set-Cookie, belong to the first response only.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// unlockScript deletes a lock only while it still holds the token of its
+// holder, so that a request outliving the lock does not release the lock of
+// the next one.
+const unlockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`
+
+// idempotentResponse is the stored first response to a request, along with
+// the fingerprint of the request to tell apart a retry from a key reuse.
+type idempotentResponse struct {
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+}
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe to
+// retry. The first response for a key of the user is stored and replayed to
+// the retries, while a request reusing the key with another method, path or
+// body is refused with 422. A retry arriving while the first request is
+// still being served gets 409. Server errors are not stored, so the request
+// can be retried for real. Must run after SessionAuth.
+func Idempotency(client redis.Client, cfg *config.IdempotencyConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			userID, _ := r.Context().Value(domain.KeyUserID).(string)
+			if r.Method != http.MethodPost || key == "" || userID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKey {
+				_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "the Idempotency-Key header must be at most 255 characters",
+				})
+				return
+			}
+
+			// The body is fingerprinted, so it is read ahead of the handler.
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, cfg.MaxBodyBytes))
+			if err != nil {
+				code := http.StatusBadRequest
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					code = http.StatusRequestEntityTooLarge
+				}
+				_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+					Code:    code,
+					Message: err.Error(),
+				})
+				return
+			}
+			_ = r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			sum := sha256.New()
+			sum.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+			sum.Write(body)
+			fingerprint := hex.EncodeToString(sum.Sum(nil))
+
+			ctx := r.Context()
+			responseKey := idempotencyKeyPrefix + userID + ":" + key
+			lockKey := responseKey + ":lock"
+
+			// A request finishing between the lookup and the lock leaves its
+			// response behind, hence the second lookup under the lock.
+			replayed, err := replayIdempotent(ctx, client, w, responseKey, fingerprint)
+			if err != nil || replayed {
+				writeIdempotencyError(w, err)
+				return
+			}
+
+			token := rand.Text()
+			locked, err := client.SetNX(ctx, lockKey, token, cfg.LockTTL).Result()
+			if err != nil {
+				writeIdempotencyError(w, err)
+				return
+			}
+			if !locked {
+				w.Header().Set("Retry-After", "1")
+				_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+					Code:    http.StatusConflict,
+					Message: "a request with this Idempotency-Key is still being processed",
+				})
+				return
+			}
+			// The client may go away, the response is stored regardless.
+			ctx = context.WithoutCancel(ctx)
+			defer client.Eval(ctx, unlockScript, []string{lockKey}, token)
+
+			replayed, err = replayIdempotent(ctx, client, w, responseKey, fingerprint)
+			if err != nil || replayed {
+				writeIdempotencyError(w, err)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+
+			data, err := json.Marshal(&idempotentResponse{
+				Fingerprint: fingerprint,
+				Status:      rec.status,
+				Header:      rec.header,
+				Body:        rec.body.Bytes(),
+			})
+			if err == nil {
+				client.Set(ctx, responseKey, data, cfg.TTL)
+			}
+		})
+	}
+}
+
+// errIdempotencyKeyReused reports a key sent again with another request.
+var errIdempotencyKeyReused = errors.New("the Idempotency-Key was already used for another request")
+
+// replayIdempotent writes the response stored under key, if any, reporting
+// whether it did.
+func replayIdempotent(ctx context.Context, client redis.Client, w http.ResponseWriter, key, fingerprint string) (bool, error) {
+	data, err := client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var stored idempotentResponse
+	err = json.Unmarshal(data, &stored)
+	if err != nil {
+		return false, err
+	}
+
+	if stored.Fingerprint != fingerprint {
+		return false, errIdempotencyKeyReused
+	}
+
+	for _, name := range replayedHeaders {
+		if v := stored.Header.Values(name); len(v) > 0 {
+			w.Header()[name] = v
+		}
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(stored.Status)
+	_, _ = w.Write(stored.Body)
+	return true, nil
+}
+
+// writeIdempotencyError answers a request that could not be checked, unless
+// err is nil.
+func writeIdempotencyError(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, errIdempotencyKeyReused):
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+		})
+	default:
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code: http.StatusInternalServerError,
+		})
+	}
+}
+
+// responseRecorder passes a response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.wroteHeader = true
+		rec.status = status
+		rec.header = make(http.Header, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if v := rec.Header().Values(name); len(v) > 0 {
+				rec.header[name] = slices.Clone(v)
+			}
+		}
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
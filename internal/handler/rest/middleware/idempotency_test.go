@@ -0,0 +1,260 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"gitlab.com/jodworkspace/mvp/config"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/redis"
+)
+
+// fakeRedis keeps the keys the idempotency middleware uses in memory.
+type fakeRedis struct {
+	redis.Client
+
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: make(map[string]string)}
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) *goredis.StringCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	v, ok := f.data[key]
+	if !ok {
+		return goredis.NewStringResult("", goredis.Nil)
+	}
+	return goredis.NewStringResult(v, nil)
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key string, value any, _ time.Duration) *goredis.StatusCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.data[key] = stringValue(value)
+	return goredis.NewStatusResult("OK", nil)
+}
+
+func (f *fakeRedis) SetNX(ctx context.Context, key string, value any, _ time.Duration) *goredis.BoolCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.data[key]; ok {
+		return goredis.NewBoolResult(false, nil)
+	}
+	f.data[key] = stringValue(value)
+	return goredis.NewBoolResult(true, nil)
+}
+
+// Eval runs unlockScript, the only script of the middleware.
+func (f *fakeRedis) Eval(ctx context.Context, script string, keys []string, args ...any) *goredis.Cmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if script != unlockScript || f.data[keys[0]] != stringValue(args[0]) {
+		return goredis.NewCmdResult(int64(0), nil)
+	}
+	delete(f.data, keys[0])
+	return goredis.NewCmdResult(int64(1), nil)
+}
+
+func stringValue(value any) string {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value.(string)
+}
+
+// countingHandler answers with status and counts the requests it served.
+type countingHandler struct {
+	status int
+	calls  int
+	during func()
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	if h.during != nil {
+		h.during()
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Location", "/tasks/1")
+	w.Header().Set("Set-Cookie", "sid=secret")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(h.status)
+	_, _ = w.Write(body)
+}
+
+func idempotentRequest(userID, key, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if userID != "" {
+		r = r.WithContext(context.WithValue(r.Context(), domain.KeyUserID, userID))
+	}
+	return r
+}
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+var idempotencyConfig = &config.IdempotencyConfig{
+	TTL:          time.Hour,
+	LockTTL:      time.Minute,
+	MaxBodyBytes: 1 << 10,
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	client := newFakeRedis()
+	next := &countingHandler{status: http.StatusCreated}
+	h := Idempotency(client, idempotencyConfig)(next)
+
+	first := serve(h, idempotentRequest("user-1", "key-1", `{"title":"a"}`))
+	if first.Code != http.StatusCreated || first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("first response = %d %v, want 201 not replayed", first.Code, first.Header())
+	}
+
+	retry := serve(h, idempotentRequest("user-1", "key-1", `{"title":"a"}`))
+	if next.calls != 1 {
+		t.Errorf("handler calls = %d, want 1", next.calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != `{"title":"a"}` {
+		t.Errorf("replay = %d %q, want 201 %q", retry.Code, retry.Body.String(), `{"title":"a"}`)
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("%s = %q, want true", IdempotentReplayedHeader, retry.Header().Get(IdempotentReplayedHeader))
+	}
+	if retry.Header().Get("Location") != "/tasks/1" {
+		t.Errorf("Location = %q, want it replayed", retry.Header().Get("Location"))
+	}
+	if retry.Header().Get("Set-Cookie") != "" {
+		t.Errorf("Set-Cookie = %q, want it left out of the replay", retry.Header().Get("Set-Cookie"))
+	}
+
+	// The key belongs to the user who sent it.
+	other := serve(h, idempotentRequest("user-2", "key-1", `{"title":"a"}`))
+	if other.Code != http.StatusCreated || next.calls != 2 {
+		t.Errorf("other user = %d after %d calls, want 201 after 2", other.Code, next.calls)
+	}
+
+	if _, locked := client.data[idempotencyKeyPrefix+"user-1:key-1:lock"]; locked {
+		t.Error("lock kept after the request, want it released")
+	}
+}
+
+func TestIdempotencyKeyReused(t *testing.T) {
+	next := &countingHandler{status: http.StatusCreated}
+	h := Idempotency(newFakeRedis(), idempotencyConfig)(next)
+
+	serve(h, idempotentRequest("user-1", "key-1", `{"title":"a"}`))
+	w := serve(h, idempotentRequest("user-1", "key-1", `{"title":"b"}`))
+	if w.Code != http.StatusUnprocessableEntity || next.calls != 1 {
+		t.Errorf("reuse = %d after %d calls, want 422 after 1", w.Code, next.calls)
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	client := newFakeRedis()
+	next := &countingHandler{status: http.StatusCreated}
+	h := Idempotency(client, idempotencyConfig)(next)
+
+	client.data[idempotencyKeyPrefix+"user-1:key-1:lock"] = "other"
+	w := serve(h, idempotentRequest("user-1", "key-1", `{}`))
+	if w.Code != http.StatusConflict || w.Header().Get("Retry-After") == "" || next.calls != 0 {
+		t.Errorf("in flight = %d %v after %d calls, want 409 with Retry-After", w.Code, w.Header(), next.calls)
+	}
+}
+
+func TestIdempotencyKeepsLockOfNextHolder(t *testing.T) {
+	client := newFakeRedis()
+	lockKey := idempotencyKeyPrefix + "user-1:key-1:lock"
+	next := &countingHandler{status: http.StatusCreated}
+	// The lock expires while the request is served and another one takes it.
+	next.during = func() {
+		client.data[lockKey] = "next"
+	}
+	h := Idempotency(client, idempotencyConfig)(next)
+
+	serve(h, idempotentRequest("user-1", "key-1", `{}`))
+	if client.data[lockKey] != "next" {
+		t.Errorf("lock = %q, want the one of the next holder", client.data[lockKey])
+	}
+}
+
+func TestIdempotencyServerErrorNotStored(t *testing.T) {
+	next := &countingHandler{status: http.StatusInternalServerError}
+	h := Idempotency(newFakeRedis(), idempotencyConfig)(next)
+
+	serve(h, idempotentRequest("user-1", "key-1", `{}`))
+	w := serve(h, idempotentRequest("user-1", "key-1", `{}`))
+	if next.calls != 2 || w.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("retry after 500: %d calls, replayed %q, want 2 calls and no replay",
+			next.calls, w.Header().Get(IdempotentReplayedHeader))
+	}
+}
+
+func TestIdempotencyPassThrough(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *http.Request
+	}{
+		{name: "no key", r: idempotentRequest("user-1", "", `{}`)},
+		{name: "no user", r: idempotentRequest("", "key-1", `{}`)},
+		{name: "not a POST", r: func() *http.Request {
+			r := idempotentRequest("user-1", "key-1", `{}`)
+			r.Method = http.MethodPut
+			return r
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeRedis()
+			next := &countingHandler{status: http.StatusCreated}
+			h := Idempotency(client, idempotencyConfig)(next)
+
+			serve(h, tt.r)
+			if next.calls != 1 || len(client.data) != 0 {
+				t.Errorf("%d calls, %d keys stored, want 1 call and none stored", next.calls, len(client.data))
+			}
+		})
+	}
+}
+
+func TestIdempotencyRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *http.Request
+		code int
+	}{
+		{name: "long key", r: idempotentRequest("user-1", strings.Repeat("k", maxIdempotencyKey+1), `{}`), code: http.StatusBadRequest},
+		{name: "large body", r: idempotentRequest("user-1", "key-1", strings.Repeat("x", 2<<10)), code: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &countingHandler{status: http.StatusCreated}
+			w := serve(Idempotency(newFakeRedis(), idempotencyConfig)(next), tt.r)
+			if w.Code != tt.code || next.calls != 0 {
+				t.Errorf("status = %d after %d calls, want %d", w.Code, next.calls, tt.code)
+			}
+		})
+	}
+}
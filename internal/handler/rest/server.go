@@ -13,6 +13,7 @@ import (
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/gorilla/sessions"
+	"gitlab.com/jodworkspace/mvp/pkg/db/redis"
 	"gitlab.com/jodworkspace/mvp/pkg/otel"
 	otelhttp "gitlab.com/jodworkspace/mvp/pkg/otel/http"
 	"gitlab.com/jodworkspace/mvp/pkg/utils/cipherx"
@@ -26,22 +27,40 @@ import (
 )
 
 type Server struct {
-	cfg             *config.Config
-	aead            *cipherx.AEAD
-	sessionStore    sessions.Store
-	taskHandler     *v1.TaskHandler
-	oauthHandler    *v1.OAuthHandler
-	documentHandler *v1.DocumentHandler
-	wsHandler       *v1.WSHandler
-	logger          *logger.ZapLogger
-	monitorManager  *otel.Manager
+	cfg              *config.Config
+	aead             *cipherx.AEAD
+	sessionStore     sessions.Store
+	redisClient      redis.Client
+	taskHandler      *v1.TaskHandler
+	commentHandler   *v1.CommentHandler
+	timeHandler      *v1.TimeEntryHandler
+	focusHandler     *v1.FocusHandler
+	reminderHandler  *v1.ReminderHandler
+	analyticsHandler *v1.AnalyticsHandler
+	labelHandler     *v1.LabelHandler
+	projectHandler   *v1.ProjectHandler
+	calendarHandler  *v1.CalendarHandler
+	oauthHandler     *v1.OAuthHandler
+	documentHandler  *v1.DocumentHandler
+	wsHandler        *v1.WSHandler
+	logger           *logger.ZapLogger
+	monitorManager   *otel.Manager
 }
 
 func NewServer(
 	cfg *config.Config,
 	aead *cipherx.AEAD,
 	sessionStore sessions.Store,
+	redisClient redis.Client,
 	taskHandler *v1.TaskHandler,
+	commentHandler *v1.CommentHandler,
+	timeHandler *v1.TimeEntryHandler,
+	focusHandler *v1.FocusHandler,
+	reminderHandler *v1.ReminderHandler,
+	analyticsHandler *v1.AnalyticsHandler,
+	labelHandler *v1.LabelHandler,
+	projectHandler *v1.ProjectHandler,
+	calendarHandler *v1.CalendarHandler,
 	oauthHandler *v1.OAuthHandler,
 	documentHandler *v1.DocumentHandler,
 	wsHandler *v1.WSHandler,
@@ -49,15 +68,24 @@ func NewServer(
 	monitorManager *otel.Manager,
 ) *Server {
 	return &Server{
-		cfg:             cfg,
-		aead:            aead,
-		sessionStore:    sessionStore,
-		taskHandler:     taskHandler,
-		oauthHandler:    oauthHandler,
-		documentHandler: documentHandler,
-		wsHandler:       wsHandler,
-		logger:          logger,
-		monitorManager:  monitorManager,
+		cfg:              cfg,
+		aead:             aead,
+		sessionStore:     sessionStore,
+		redisClient:      redisClient,
+		taskHandler:      taskHandler,
+		commentHandler:   commentHandler,
+		timeHandler:      timeHandler,
+		focusHandler:     focusHandler,
+		reminderHandler:  reminderHandler,
+		analyticsHandler: analyticsHandler,
+		labelHandler:     labelHandler,
+		projectHandler:   projectHandler,
+		calendarHandler:  calendarHandler,
+		oauthHandler:     oauthHandler,
+		documentHandler:  documentHandler,
+		wsHandler:        wsHandler,
+		logger:           logger,
+		monitorManager:   monitorManager,
 	}
 }
 
@@ -107,14 +135,137 @@ func (s *Server) registerOAuthRoutes(router chi.Router, m *otelhttp.Monitor) {
 }
 
 func (s *Server) registerTaskRoutes(router chi.Router, m *otelhttp.Monitor) {
+	s.instrumentedRouter(router, m).
+		With(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName), s.idempotency()).
+		Post("/api/v1/tasks:batch", s.taskHandler.Batch)
+
 	router.Route("/api/v1/tasks", func(r chi.Router) {
 		ir := s.instrumentedRouter(r, m)
 		ir.Use(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName))
+		ir.Use(s.idempotency())
 		ir.With(middleware.Pagination).Get("/", s.taskHandler.List)
 		ir.Post("/", s.taskHandler.Create)
+		ir.With(middleware.Pagination).Get("/search", s.taskHandler.Search)
+		ir.With(middleware.Pagination).Get("/trash", s.taskHandler.Trash)
+		ir.Get("/next", s.taskHandler.Next)
+		ir.Get("/export/{format}", s.taskHandler.Export)
+		ir.Post("/import/{format}", s.taskHandler.Import)
+		ir.Post("/quick", s.taskHandler.QuickAdd)
 		ir.Get("/{id}", s.taskHandler.Get)
 		ir.Put("/{id}", s.taskHandler.Update)
+		ir.Patch("/{id}", s.taskHandler.Patch)
 		ir.Delete("/{id}", s.taskHandler.Delete)
+		ir.Get("/{id}/subtasks", s.taskHandler.Children)
+		ir.Get("/{id}/tree", s.taskHandler.Tree)
+		ir.Get("/{id}/occurrences", s.taskHandler.Occurrences)
+		ir.With(middleware.Pagination).Get("/{id}/history", s.taskHandler.History)
+		ir.Post("/{id}/dependencies", s.taskHandler.AddDependency)
+		ir.Delete("/{id}/dependencies/{blockerID}", s.taskHandler.RemoveDependency)
+		ir.With(middleware.Pagination).Get("/{id}/comments", s.commentHandler.List)
+		ir.Post("/{id}/comments", s.commentHandler.Create)
+		ir.Put("/{id}/comments/{commentID}", s.commentHandler.Update)
+		ir.Delete("/{id}/comments/{commentID}", s.commentHandler.Delete)
+		ir.Get("/{id}/comments/{commentID}/revisions", s.commentHandler.Revisions)
+		ir.Post("/{id}/timer", s.timeHandler.StartTimer)
+		ir.With(middleware.Pagination).Get("/{id}/time-entries", s.timeHandler.List)
+		ir.Post("/{id}/time-entries", s.timeHandler.Create)
+		ir.Put("/{id}/time-entries/{entryID}", s.timeHandler.Update)
+		ir.Delete("/{id}/time-entries/{entryID}", s.timeHandler.Delete)
+		ir.Post("/{id}/focus", s.focusHandler.Start)
+		ir.Get("/{id}/reminders", s.reminderHandler.List)
+		ir.Post("/{id}/reminders", s.reminderHandler.Create)
+		ir.Delete("/{id}/reminders/{reminderID}", s.reminderHandler.Delete)
+		ir.Get("/{id}/reminders/{reminderID}/deliveries", s.reminderHandler.Deliveries)
+		ir.Post("/{id}/move", s.taskHandler.Move)
+		ir.Post("/{id}/restore", s.taskHandler.Restore)
+	})
+}
+
+func (s *Server) registerTimeRoutes(router chi.Router, m *otelhttp.Monitor) {
+	router.Route("/api/v1/time", func(r chi.Router) {
+		ir := s.instrumentedRouter(r, m)
+		ir.Use(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName))
+		ir.Use(s.idempotency())
+		ir.Get("/timer", s.timeHandler.Timer)
+		ir.Post("/timer/stop", s.timeHandler.StopTimer)
+		ir.Delete("/timer", s.timeHandler.DiscardTimer)
+		ir.Get("/report", s.timeHandler.Report)
+	})
+}
+
+func (s *Server) registerAgendaRoutes(router chi.Router, m *otelhttp.Monitor) {
+	router.Route("/api/v1/agenda", func(r chi.Router) {
+		ir := s.instrumentedRouter(r, m)
+		ir.Use(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName))
+		ir.Get("/", s.taskHandler.Agenda)
+		ir.Get("/plan", s.taskHandler.DayPlan)
+		ir.Put("/plan", s.taskHandler.PlanDay)
+	})
+}
+
+func (s *Server) registerAnalyticsRoutes(router chi.Router, m *otelhttp.Monitor) {
+	router.Route("/api/v1/analytics", func(r chi.Router) {
+		ir := s.instrumentedRouter(r, m)
+		ir.Use(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName))
+		ir.Get("/", s.analyticsHandler.Dashboard)
+		ir.Get("/projects/{id}/burndown", s.analyticsHandler.Burndown)
+	})
+}
+
+func (s *Server) registerFocusRoutes(router chi.Router, m *otelhttp.Monitor) {
+	router.Route("/api/v1/focus", func(r chi.Router) {
+		ir := s.instrumentedRouter(r, m)
+		ir.Use(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName))
+		ir.Use(s.idempotency())
+		ir.Get("/current", s.focusHandler.Current)
+		ir.Post("/interrupt", s.focusHandler.Interrupt)
+		ir.Post("/stop", s.focusHandler.Stop)
+		ir.Get("/stats", s.focusHandler.Stats)
+	})
+}
+
+func (s *Server) registerLabelRoutes(router chi.Router, m *otelhttp.Monitor) {
+	router.Route("/api/v1/labels", func(r chi.Router) {
+		ir := s.instrumentedRouter(r, m)
+		ir.Use(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName))
+		ir.Use(s.idempotency())
+		ir.Get("/", s.labelHandler.List)
+		ir.Post("/", s.labelHandler.Create)
+		ir.Get("/{id}", s.labelHandler.Get)
+		ir.Put("/{id}", s.labelHandler.Update)
+		ir.Delete("/{id}", s.labelHandler.Delete)
+	})
+}
+
+func (s *Server) registerProjectRoutes(router chi.Router, m *otelhttp.Monitor) {
+	router.Route("/api/v1/projects", func(r chi.Router) {
+		ir := s.instrumentedRouter(r, m)
+		ir.Use(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName))
+		ir.Use(s.idempotency())
+		ir.Get("/", s.projectHandler.List)
+		ir.Post("/", s.projectHandler.Create)
+		ir.Get("/{id}", s.projectHandler.Get)
+		ir.Put("/{id}", s.projectHandler.Update)
+		ir.Delete("/{id}", s.projectHandler.Delete)
+		ir.With(middleware.Pagination).Get("/{id}/tasks", s.taskHandler.ListByProject)
+		ir.Get("/{id}/board", s.taskHandler.Board)
+		ir.Get("/{id}/statuses", s.projectHandler.ListStatuses)
+		ir.Post("/{id}/statuses", s.projectHandler.CreateStatus)
+		ir.Put("/{id}/statuses/{statusID}", s.projectHandler.UpdateStatus)
+		ir.Delete("/{id}/statuses/{statusID}", s.projectHandler.DeleteStatus)
+	})
+}
+
+func (s *Server) registerCalendarRoutes(router chi.Router, m *otelhttp.Monitor) {
+	router.Route("/api/v1/calendar", func(r chi.Router) {
+		ir := s.instrumentedRouter(r, m)
+		// Calendar clients authenticate with the feed token instead of a session.
+		ir.Get("/feed.ics", s.calendarHandler.Feed)
+
+		irWithAuth := ir.With(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName), s.idempotency())
+		irWithAuth.Get("/tasks.ics", s.calendarHandler.Export)
+		irWithAuth.Post("/feed", s.calendarHandler.CreateFeed)
+		irWithAuth.Delete("/feed", s.calendarHandler.RevokeFeed)
 	})
 }
 
@@ -140,10 +291,11 @@ func (s *Server) RestMux(m *otelhttp.Monitor) *chi.Mux {
 		AllowedMethods:   s.cfg.CORS.AllowedMethods,
 		AllowedHeaders:   s.cfg.CORS.AllowedHeaders,
 		AllowCredentials: s.cfg.CORS.AllowCredentials,
+		ExposedHeaders:   s.cfg.CORS.ExposedHeaders, // ETag, read back in If-Match
 		MaxAge:           300,
 	}))
 
-	r.Get("/ws", s.wsHandler.Handle)
+	r.With(middleware.SessionAuth(s.sessionStore, domain.SessionCookieName)).Get("/ws", s.wsHandler.Handle)
 	r.Handle("/metrics", s.monitorManager.PrometheusHandler())
 
 	ir := s.instrumentedRouter(r, m)
@@ -155,6 +307,13 @@ func (s *Server) RestMux(m *otelhttp.Monitor) *chi.Mux {
 
 	s.registerOAuthRoutes(r, m)
 	s.registerTaskRoutes(r, m)
+	s.registerTimeRoutes(r, m)
+	s.registerFocusRoutes(r, m)
+	s.registerAgendaRoutes(r, m)
+	s.registerAnalyticsRoutes(r, m)
+	s.registerLabelRoutes(r, m)
+	s.registerProjectRoutes(r, m)
+	s.registerCalendarRoutes(r, m)
 	s.registerDocumentRoutes(r, m)
 
 	ir.NotFound(NotFoundRoute)
@@ -166,6 +325,12 @@ func NotFoundRoute(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "not found", http.StatusNotFound)
 }
 
+// idempotency replays the responses of POST requests retried with the same
+// Idempotency-Key, to be used after SessionAuth.
+func (s *Server) idempotency() middleware.Middleware {
+	return middleware.Idempotency(s.redisClient, s.cfg.Idempotency)
+}
+
 func (s *Server) instrumentedRouter(r chi.Router, m *otelhttp.Monitor) chi.Router {
 	return r.With(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+type AnalyticsUC interface {
+	Dashboard(ctx context.Context, filter *domain.AnalyticsFilter) (*domain.Analytics, error)
+	Burndown(ctx context.Context, filter *domain.BurndownFilter) ([]*domain.BurndownPoint, error)
+}
+
+type AnalyticsHandler struct {
+	analyticsUC AnalyticsUC
+	logger      *logger.ZapLogger
+}
+
+func NewAnalyticsHandler(analyticsUC AnalyticsUC, zl *logger.ZapLogger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsUC: analyticsUC,
+		logger:      zl,
+	}
+}
+
+// Dashboard returns the analytics of the user between the from and to dates
+// of the query, bucketing completed tasks by day or week in the tz zone.
+func (h *AnalyticsHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+
+	loc, err := parseTimeZone(values.Get("tz"))
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	filter := &domain.AnalyticsFilter{
+		OwnerID:  ownerID,
+		Period:   values.Get("period"),
+		Location: loc,
+	}
+	switch filter.Period {
+	case "":
+		filter.Period = domain.AnalyticsByDay
+	case domain.AnalyticsByDay, domain.AnalyticsByWeek:
+	default:
+		err = fmt.Errorf("invalid value for period: %q", filter.Period)
+	}
+	if err == nil {
+		filter.From, filter.To, err = parseDateRange(values, loc)
+	}
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	analytics, err := h.analyticsUC.Dashboard(r.Context(), filter)
+	if err != nil {
+		writeAnalyticsError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"period":    filter.Period,
+		"analytics": analytics,
+	})
+}
+
+// Burndown returns the burndown of a project between the from and to dates
+// of the query, days being cut in the tz zone.
+func (h *AnalyticsHandler) Burndown(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	projectID := r.PathValue("id")
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+
+	if uuid.Validate(projectID) != nil {
+		writeAnalyticsError(w, errorx.ErrProjectNotFound)
+		return
+	}
+
+	loc, err := parseTimeZone(values.Get("tz"))
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	filter := &domain.BurndownFilter{
+		OwnerID:   ownerID,
+		ProjectID: projectID,
+		Location:  loc,
+	}
+	filter.From, filter.To, err = parseDateRange(values, loc)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	points, err := h.analyticsUC.Burndown(r.Context(), filter)
+	if err != nil {
+		writeAnalyticsError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"projectID": projectID,
+		"burndown":  points,
+	})
+}
+
+func writeAnalyticsError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorx.ErrProjectNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, errorx.ErrInvalidReportRange):
+		code = http.StatusUnprocessableEntity
+	}
+
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
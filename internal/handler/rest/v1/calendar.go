@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+	"go.uber.org/zap"
+)
+
+type CalendarUC interface {
+	Tasks(ctx context.Context, ownerID string) ([]*domain.Task, error)
+	CreateFeed(ctx context.Context, ownerID string) (*domain.CalendarFeed, error)
+	RevokeFeed(ctx context.Context, ownerID string) error
+	FeedOwner(ctx context.Context, token string) (string, error)
+}
+
+type CalendarHandler struct {
+	calendarUC CalendarUC
+	logger     *logger.ZapLogger
+}
+
+func NewCalendarHandler(calendarUC CalendarUC, zl *logger.ZapLogger) *CalendarHandler {
+	return &CalendarHandler{
+		calendarUC: calendarUC,
+		logger:     zl,
+	}
+}
+
+// Export downloads the dated tasks of the session user as an .ics file.
+func (h *CalendarHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	h.serveICS(w, r, ownerID, "attachment")
+}
+
+// Feed serves the dated tasks of the owner of the token in the query string,
+// for calendar clients that can not hold a session.
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := h.calendarUC.FeedOwner(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	h.serveICS(w, r, ownerID, "inline")
+}
+
+// CreateFeed issues a subscription URL, revoking the previous one.
+func (h *CalendarHandler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	feed, err := h.calendarUC.CreateFeed(r.Context(), ownerID)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	feed.URL = fmt.Sprintf("%s://%s/api/v1/calendar/feed.ics?token=%s", scheme, r.Host, feed.Token)
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"feed": feed,
+	})
+}
+
+// RevokeFeed revokes the subscription URL of the session user.
+func (h *CalendarHandler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err := h.calendarUC.RevokeFeed(r.Context(), ownerID)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
+
+// serveICS writes the calendar of ownerID, made of VEVENT components unless
+// the type query parameter asks for VTODO.
+func (h *CalendarHandler) serveICS(w http.ResponseWriter, r *http.Request, ownerID, disposition string) {
+	kind := r.URL.Query().Get("type")
+	switch kind {
+	case "":
+		kind = domain.CalendarEvent
+	case domain.CalendarEvent, domain.CalendarTodo:
+	default:
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid value for type: %q", kind),
+		})
+		return
+	}
+
+	tasks, err := h.calendarUC.Tasks(r.Context(), ownerID)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", icsContentType)
+	w.Header().Set("Content-Disposition", disposition+`; filename="tasks.ics"`)
+	err = writeTasksICS(w, tasks, kind)
+	if err != nil {
+		h.logger.Error(
+			"CalendarHandler - writeTasksICS",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+	}
+}
+
+func writeCalendarError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorx.ErrInvalidFeedToken):
+		code = http.StatusUnauthorized
+	}
+
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
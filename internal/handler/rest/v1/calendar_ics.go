@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/icalx"
+)
+
+const (
+	icsContentType = "text/calendar; charset=utf-8"
+	icsProductID   = "-//jodworkspace//mvp//EN"
+
+	// icsRefreshInterval is how often calendar clients are asked to poll.
+	icsRefreshInterval = "PT1H"
+)
+
+// writeTasksICS writes the tasks as an iCalendar object of VTODO or VEVENT
+// components, depending on kind.
+func writeTasksICS(w io.Writer, tasks []*domain.Task, kind string) error {
+	cal := icalx.NewComponent("VCALENDAR")
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", icsProductID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	cal.Add("METHOD", "PUBLISH")
+	cal.AddText("NAME", "Tasks")
+	cal.AddText("X-WR-CALNAME", "Tasks")
+	cal.Add("REFRESH-INTERVAL", icsRefreshInterval, "VALUE=DURATION")
+	cal.Add("X-PUBLISHED-TTL", icsRefreshInterval)
+
+	for _, task := range tasks {
+		if kind == domain.CalendarTodo {
+			cal.AddComponent(taskTodo(task))
+		} else {
+			cal.AddComponent(taskEvent(task))
+		}
+	}
+
+	return cal.Encode(w)
+}
+
+// taskTodo maps a task to a VTODO. A start date after the due date, which
+// RFC 5545 forbids, is left out.
+func taskTodo(task *domain.Task) *icalx.Component {
+	todo := icalx.NewComponent("VTODO")
+	addTaskProperties(todo, task)
+
+	if task.StartDate != nil && (task.DueDate == nil || !task.DueDate.Before(*task.StartDate)) {
+		todo.AddTime("DTSTART", *task.StartDate)
+	}
+	if task.DueDate != nil {
+		todo.AddTime("DUE", *task.DueDate)
+	}
+
+	if task.IsCompleted {
+		todo.Add("STATUS", "COMPLETED")
+		todo.AddTime("COMPLETED", task.UpdatedAt)
+		todo.Add("PERCENT-COMPLETE", "100")
+	} else {
+		todo.Add("STATUS", "NEEDS-ACTION")
+	}
+
+	return todo
+}
+
+// taskEvent maps a task to a VEVENT for calendars without task support. It
+// spans from the start to the due date, or is a point in time when the task
+// has only one of them. Completed tasks are marked in their summary.
+func taskEvent(task *domain.Task) *icalx.Component {
+	event := icalx.NewComponent("VEVENT")
+	addTaskProperties(event, task)
+
+	start, end := task.StartDate, task.DueDate
+	if start == nil || (end != nil && !end.After(*start)) {
+		start, end = end, nil
+	}
+	event.AddTime("DTSTART", *start)
+	if end != nil {
+		event.AddTime("DTEND", *end)
+	}
+	event.Add("TRANSP", "TRANSPARENT")
+
+	return event
+}
+
+// addTaskProperties adds the properties shared by VTODO and VEVENT.
+func addTaskProperties(c *icalx.Component, task *domain.Task) {
+	c.Add("UID", task.ID)
+	c.AddTime("DTSTAMP", task.UpdatedAt)
+	c.AddTime("CREATED", task.CreatedAt)
+	c.AddTime("LAST-MODIFIED", task.UpdatedAt)
+
+	summary := task.Title
+	if task.IsCompleted && c.Name == "VEVENT" {
+		summary = "✓ " + summary
+	}
+	c.AddText("SUMMARY", summary)
+
+	if task.Details != "" {
+		c.AddText("DESCRIPTION", task.Details)
+	}
+	if priority := icalPriority(task.Priority); priority != 0 {
+		c.Add("PRIORITY", strconv.Itoa(priority))
+	}
+	if task.ParentID != nil {
+		c.Add("RELATED-TO", *task.ParentID)
+	}
+
+	if len(task.Labels) > 0 {
+		categories := make([]string, 0, len(task.Labels))
+		for _, label := range task.Labels {
+			categories = append(categories, icalx.EscapeText(label.Name))
+		}
+		c.Add("CATEGORIES", strings.Join(categories, ","))
+	}
+}
+
+// icalPriority maps task priorities, where 5 and above is the most important
+// and 1 the least, to iCalendar priorities from 1 (highest) to 9 (lowest).
+// Tasks without a priority get 0, undefined.
+func icalPriority(priority int) int {
+	switch {
+	case priority <= 0:
+		return 0
+	case priority >= 5:
+		return 1
+	}
+
+	return 11 - 2*priority
+}
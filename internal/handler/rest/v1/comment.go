@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+type CommentUC interface {
+	List(ctx context.Context, taskID, ownerID string, page, pageSize uint64) ([]*domain.Comment, int64, error)
+	Create(ctx context.Context, ownerID string, comment *domain.Comment) error
+	Update(ctx context.Context, ownerID string, comment *domain.Comment) error
+	Delete(ctx context.Context, id, taskID, ownerID string) error
+	Revisions(ctx context.Context, id, taskID, ownerID string) ([]*domain.CommentRevision, error)
+}
+
+type CommentHandler struct {
+	commentUC CommentUC
+	logger    *logger.ZapLogger
+}
+
+func NewCommentHandler(commentUC CommentUC, zl *logger.ZapLogger) *CommentHandler {
+	return &CommentHandler{
+		commentUC: commentUC,
+		logger:    zl,
+	}
+}
+
+// List returns a page of the comment threads of a task.
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	p, ok := r.Context().Value(domain.KeyPagination).(*domain.Pagination)
+	if !ok {
+		p = &domain.Pagination{
+			Page:     1,
+			PageSize: 10,
+		}
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	comments, total, err := h.commentUC.List(r.Context(), r.PathValue("id"), ownerID, p.Page, p.PageSize)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"page":     p.Page,
+		"pageSize": p.PageSize,
+		"total":    total,
+		"comments": comments,
+	})
+}
+
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Body     string  `json:"body" validate:"required,max=10000"`
+		ParentID *string `json:"parentID" validate:"omitnil,uuid"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	comment := &domain.Comment{
+		TaskID:   r.PathValue("id"),
+		ParentID: input.ParentID,
+		Body:     input.Body,
+	}
+
+	err := h.commentUC.Create(r.Context(), ownerID, comment)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"comment": comment,
+	})
+}
+
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Body string `json:"body" validate:"required,max=10000"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	comment := &domain.Comment{
+		ID:     r.PathValue("commentID"),
+		TaskID: r.PathValue("id"),
+		Body:   input.Body,
+	}
+
+	err := h.commentUC.Update(r.Context(), ownerID, comment)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"comment": comment,
+	})
+}
+
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+
+	err := h.commentUC.Delete(r.Context(), r.PathValue("commentID"), r.PathValue("id"), ownerID)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
+
+// Revisions returns the edit history of a comment, newest first.
+func (h *CommentHandler) Revisions(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+
+	revisions, err := h.commentUC.Revisions(r.Context(), r.PathValue("commentID"), r.PathValue("id"), ownerID)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"revisions": revisions,
+	})
+}
+
+func writeCommentError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorx.ErrTaskNotFound),
+		errors.Is(err, errorx.ErrCommentNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, errorx.ErrParentCommentNotFound):
+		code = http.StatusUnprocessableEntity
+	case errors.Is(err, errorx.ErrNotCommentAuthor):
+		code = http.StatusForbidden
+	}
+
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,204 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+type FocusUC interface {
+	Current(ownerID string) (*domain.FocusSession, error)
+	Start(ctx context.Context, taskID, ownerID string, work, brk *time.Duration) (*domain.FocusSession, error)
+	Interrupt(ownerID string) (*domain.FocusSession, error)
+	Stop(ctx context.Context, ownerID string) (*domain.FocusSession, error)
+	Stats(ctx context.Context, filter *domain.FocusStatsFilter) ([]*domain.FocusStats, error)
+}
+
+// FocusHandler serves the pomodoro sessions. Their progress is pushed over
+// the WebSocket as focus.tick, focus.phase and focus.interrupted messages.
+type FocusHandler struct {
+	focusUC FocusUC
+	logger  *logger.ZapLogger
+}
+
+func NewFocusHandler(focusUC FocusUC, zl *logger.ZapLogger) *FocusHandler {
+	return &FocusHandler{
+		focusUC: focusUC,
+		logger:  zl,
+	}
+}
+
+// Current returns the running session of the user.
+func (h *FocusHandler) Current(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	session, err := h.focusUC.Current(ownerID)
+	if err != nil {
+		writeFocusError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"session": session,
+	})
+}
+
+// Start starts a session on the task in the path. The work and break lengths
+// default to the configured ones, and breakMinutes=0 skips the break.
+func (h *FocusHandler) Start(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		WorkMinutes  *int `json:"workMinutes" validate:"omitnil,min=1,max=180"`
+		BreakMinutes *int `json:"breakMinutes" validate:"omitnil,min=0,max=60"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	session, err := h.focusUC.Start(r.Context(), r.PathValue("id"), ownerID,
+		minutes(input.WorkMinutes),
+		minutes(input.BreakMinutes),
+	)
+	if err != nil {
+		writeFocusError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"session": session,
+	})
+}
+
+// Interrupt counts an interruption of the running session.
+func (h *FocusHandler) Interrupt(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	session, err := h.focusUC.Interrupt(ownerID)
+	if err != nil {
+		writeFocusError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"session": session,
+	})
+}
+
+// Stop ends the running session early.
+func (h *FocusHandler) Stop(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	session, err := h.focusUC.Stop(r.Context(), ownerID)
+	if err != nil {
+		writeFocusError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"session": session,
+	})
+}
+
+// Stats sums up the sessions of the user per task and period=day|week
+// (default day) between the from and to dates, both included and given as
+// YYYY-MM-DD in the tz time zone (default UTC). task=<id> narrows them down
+// to a single task.
+func (h *FocusHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	filter, err := parseFocusStatsFilter(r.URL.Query(), ownerID)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	stats, err := h.focusUC.Stats(r.Context(), filter)
+	if err != nil {
+		writeFocusError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"period": filter.Period,
+		"stats":  stats,
+	})
+}
+
+func parseFocusStatsFilter(values url.Values, ownerID string) (*domain.FocusStatsFilter, error) {
+	loc, err := parseTimeZone(values.Get("tz"))
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &domain.FocusStatsFilter{
+		OwnerID:  ownerID,
+		Period:   values.Get("period"),
+		Location: loc,
+	}
+
+	switch filter.Period {
+	case "":
+		filter.Period = domain.FocusPeriodDay
+	case domain.FocusPeriodDay, domain.FocusPeriodWeek:
+	default:
+		return nil, fmt.Errorf("invalid value for period: %q", filter.Period)
+	}
+
+	if task := values.Get("task"); task != "" {
+		if uuid.Validate(task) != nil {
+			return nil, fmt.Errorf("invalid value for task: %q", task)
+		}
+		filter.TaskID = &task
+	}
+
+	filter.From, filter.To, err = parseDateRange(values, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// minutes converts an optional number of minutes to a duration.
+func minutes(n *int) *time.Duration {
+	if n == nil {
+		return nil
+	}
+
+	d := time.Duration(*n) * time.Minute
+	return &d
+}
+
+func writeFocusError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorx.ErrTaskNotFound),
+		errors.Is(err, errorx.ErrNoFocusSession):
+		code = http.StatusNotFound
+	case errors.Is(err, errorx.ErrFocusSessionRunning):
+		code = http.StatusConflict
+	case errors.Is(err, errorx.ErrInvalidReportRange):
+		code = http.StatusUnprocessableEntity
+	}
+
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+type LabelUC interface {
+	List(ctx context.Context, ownerID string) ([]*domain.Label, error)
+	Get(ctx context.Context, id, ownerID string) (*domain.Label, error)
+	Create(ctx context.Context, label *domain.Label) error
+	Update(ctx context.Context, label *domain.Label) error
+	Delete(ctx context.Context, id, ownerID string) error
+}
+
+type LabelHandler struct {
+	labelUC LabelUC
+	logger  *logger.ZapLogger
+}
+
+func NewLabelHandler(labelUC LabelUC, zl *logger.ZapLogger) *LabelHandler {
+	return &LabelHandler{
+		labelUC: labelUC,
+		logger:  zl,
+	}
+}
+
+func (h *LabelHandler) List(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+
+	labels, err := h.labelUC.List(r.Context(), ownerID)
+	if err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"labels": labels,
+	})
+}
+
+func (h *LabelHandler) Get(w http.ResponseWriter, r *http.Request) {
+	labelID := r.PathValue("id")
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+
+	label, err := h.labelUC.Get(r.Context(), labelID, ownerID)
+	if err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"label": label,
+	})
+}
+
+func (h *LabelHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name  string `json:"name" validate:"required,max=64"`
+		Color string `json:"color" validate:"omitempty,hexcolor"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	label := &domain.Label{
+		Name:    input.Name,
+		Color:   input.Color,
+		OwnerID: ownerID,
+	}
+
+	err := h.labelUC.Create(r.Context(), label)
+	if err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"label": label,
+	})
+}
+
+func (h *LabelHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name  string `json:"name" validate:"required,max=64"`
+		Color string `json:"color" validate:"omitempty,hexcolor"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	label := &domain.Label{
+		ID:      r.PathValue("id"),
+		Name:    input.Name,
+		Color:   input.Color,
+		OwnerID: ownerID,
+	}
+
+	err := h.labelUC.Update(r.Context(), label)
+	if err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"label": label,
+	})
+}
+
+func (h *LabelHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	labelID := r.PathValue("id")
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+
+	err := h.labelUC.Delete(r.Context(), labelID, ownerID)
+	if err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
+
+func writeLabelError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorx.ErrLabelNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, errorx.ErrLabelExists):
+		code = http.StatusConflict
+	}
+
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+type ProjectUC interface {
+	List(ctx context.Context, ownerID string, includeArchived bool) ([]*domain.Project, error)
+	Get(ctx context.Context, id, ownerID string) (*domain.Project, error)
+	Create(ctx context.Context, project *domain.Project) error
+	Update(ctx context.Context, project *domain.Project) error
+	Delete(ctx context.Context, id, ownerID string) error
+	ListStatuses(ctx context.Context, projectID, ownerID string) ([]*domain.ProjectStatus, error)
+	CreateStatus(ctx context.Context, status *domain.ProjectStatus, placement *domain.Placement) error
+	UpdateStatus(ctx context.Context, status *domain.ProjectStatus, placement *domain.Placement) error
+	DeleteStatus(ctx context.Context, id, projectID, ownerID string) error
+}
+
+type ProjectHandler struct {
+	projectUC ProjectUC
+	logger    *logger.ZapLogger
+}
+
+func NewProjectHandler(projectUC ProjectUC, zl *logger.ZapLogger) *ProjectHandler {
+	return &ProjectHandler{
+		projectUC: projectUC,
+		logger:    zl,
+	}
+}
+
+// List returns the user's projects with their task counts. Archived projects
+// are only included with archived=true.
+func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
+	var includeArchived bool
+	if v := r.URL.Query().Get("archived"); v != "" {
+		var err error
+		includeArchived, err = strconv.ParseBool(v)
+		if err != nil {
+			_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("invalid value for archived: %q", v),
+			})
+			return
+		}
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	projects, err := h.projectUC.List(r.Context(), ownerID, includeArchived)
+	if err != nil {
+		writeProjectError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"projects": projects,
+	})
+}
+
+func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+
+	project, err := h.projectUC.Get(r.Context(), projectID, ownerID)
+	if err != nil {
+		writeProjectError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"project": project,
+	})
+}
+
+func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name        string `json:"name" validate:"required,max=128"`
+		Description string `json:"description"`
+		Color       string `json:"color" validate:"omitempty,hexcolor"`
+		SortOrder   int    `json:"sortOrder"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	project := &domain.Project{
+		Name:        input.Name,
+		Description: input.Description,
+		Color:       input.Color,
+		SortOrder:   input.SortOrder,
+		OwnerID:     ownerID,
+	}
+
+	err := h.projectUC.Create(r.Context(), project)
+	if err != nil {
+		writeProjectError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"project": project,
+	})
+}
+
+// Update replaces a project. Setting isArchived hides the project's tasks from
+// the default task listings.
+func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name        string `json:"name" validate:"required,max=128"`
+		Description string `json:"description"`
+		Color       string `json:"color" validate:"omitempty,hexcolor"`
+		IsArchived  bool   `json:"isArchived"`
+		SortOrder   int    `json:"sortOrder"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	project := &domain.Project{
+		ID:          r.PathValue("id"),
+		Name:        input.Name,
+		Description: input.Description,
+		Color:       input.Color,
+		IsArchived:  input.IsArchived,
+		SortOrder:   input.SortOrder,
+		OwnerID:     ownerID,
+	}
+
+	err := h.projectUC.Update(r.Context(), project)
+	if err != nil {
+		writeProjectError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"project": project,
+	})
+}
+
+// Delete removes a project, keeping its tasks outside of any project.
+func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+
+	err := h.projectUC.Delete(r.Context(), projectID, ownerID)
+	if err != nil {
+		writeProjectError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
+
+func writeProjectError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorx.ErrProjectNotFound),
+		errors.Is(err, errorx.ErrStatusNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, errorx.ErrStatusExists):
+		code = http.StatusConflict
+	case errors.Is(err, errorx.ErrInvalidPlacement):
+		code = http.StatusUnprocessableEntity
+	}
+
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"net/http"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+type statusInput struct {
+	Name     string  `json:"name" validate:"required,max=64"`
+	Color    string  `json:"color" validate:"omitempty,hexcolor"`
+	IsDone   bool    `json:"isDone"`
+	AfterID  *string `json:"afterID" validate:"omitempty,uuid"`
+	BeforeID *string `json:"beforeID" validate:"omitempty,uuid"`
+}
+
+// ListStatuses returns the board columns of the project in the path in order.
+func (h *ProjectHandler) ListStatuses(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	statuses, err := h.projectUC.ListStatuses(r.Context(), r.PathValue("id"), ownerID)
+	if err != nil {
+		writeProjectError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"statuses": statuses,
+	})
+}
+
+// CreateStatus adds a board column to the project in the path, after afterID
+// and/or before beforeID, or last.
+func (h *ProjectHandler) CreateStatus(w http.ResponseWriter, r *http.Request) {
+	var input statusInput
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	status := &domain.ProjectStatus{
+		ProjectID: r.PathValue("id"),
+		OwnerID:   ownerID,
+		Name:      input.Name,
+		Color:     input.Color,
+		IsDone:    input.IsDone,
+	}
+
+	err := h.projectUC.CreateStatus(r.Context(), status, &domain.Placement{
+		AfterID:  input.AfterID,
+		BeforeID: input.BeforeID,
+	})
+	if err != nil {
+		writeProjectError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"status": status,
+	})
+}
+
+// UpdateStatus replaces a board column, moving it only when afterID or
+// beforeID is given.
+func (h *ProjectHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	var input statusInput
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	status := &domain.ProjectStatus{
+		ID:        r.PathValue("statusID"),
+		ProjectID: r.PathValue("id"),
+		OwnerID:   ownerID,
+		Name:      input.Name,
+		Color:     input.Color,
+		IsDone:    input.IsDone,
+	}
+
+	var placement *domain.Placement
+	if input.AfterID != nil || input.BeforeID != nil {
+		placement = &domain.Placement{
+			AfterID:  input.AfterID,
+			BeforeID: input.BeforeID,
+		}
+	}
+
+	err := h.projectUC.UpdateStatus(r.Context(), status, placement)
+	if err != nil {
+		writeProjectError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"status": status,
+	})
+}
+
+// DeleteStatus removes a board column, leaving its tasks without status.
+func (h *ProjectHandler) DeleteStatus(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err := h.projectUC.DeleteStatus(r.Context(), r.PathValue("statusID"), r.PathValue("id"), ownerID)
+	if err != nil {
+		writeProjectError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
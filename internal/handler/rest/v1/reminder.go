@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+type ReminderUC interface {
+	List(ctx context.Context, taskID, ownerID string) ([]*domain.Reminder, error)
+	Create(ctx context.Context, reminder *domain.Reminder) error
+	Delete(ctx context.Context, id, taskID, ownerID string) error
+	Deliveries(ctx context.Context, id, taskID, ownerID string) ([]*domain.ReminderDelivery, error)
+}
+
+type ReminderHandler struct {
+	reminderUC ReminderUC
+	logger     *logger.ZapLogger
+}
+
+func NewReminderHandler(reminderUC ReminderUC, zl *logger.ZapLogger) *ReminderHandler {
+	return &ReminderHandler{
+		reminderUC: reminderUC,
+		logger:     zl,
+	}
+}
+
+// List returns the reminders of the task in the path.
+func (h *ReminderHandler) List(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	reminders, err := h.reminderUC.List(r.Context(), r.PathValue("id"), ownerID)
+	if err != nil {
+		writeReminderError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"reminders": reminders,
+	})
+}
+
+// Create adds a reminder to the task in the path, at remindAt or
+// offsetMinutes before the due date of the task.
+func (h *ReminderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RemindAt      *time.Time `json:"remindAt"`
+		OffsetMinutes *int       `json:"offsetMinutes" validate:"omitnil,min=0,max=43200"`
+		Channels      []string   `json:"channels" validate:"required,min=1,dive,oneof=ws email webhook"`
+		WebhookURL    string     `json:"webhookURL" validate:"omitempty,max=2048,url,startswith=https://"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	reminder := &domain.Reminder{
+		TaskID:        r.PathValue("id"),
+		OwnerID:       ownerID,
+		RemindAt:      input.RemindAt,
+		OffsetMinutes: input.OffsetMinutes,
+		Channels:      input.Channels,
+		WebhookURL:    input.WebhookURL,
+	}
+
+	err := h.reminderUC.Create(r.Context(), reminder)
+	if err != nil {
+		writeReminderError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"reminder": reminder,
+	})
+}
+
+func (h *ReminderHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err := h.reminderUC.Delete(r.Context(), r.PathValue("reminderID"), r.PathValue("id"), ownerID)
+	if err != nil {
+		writeReminderError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
+
+// Deliveries returns the delivery log of a reminder.
+func (h *ReminderHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	deliveries, err := h.reminderUC.Deliveries(r.Context(), r.PathValue("reminderID"), r.PathValue("id"), ownerID)
+	if err != nil {
+		writeReminderError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"deliveries": deliveries,
+	})
+}
+
+func writeReminderError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorx.ErrTaskNotFound),
+		errors.Is(err, errorx.ErrReminderNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, errorx.ErrInvalidReminder),
+		errors.Is(err, errorx.ErrReminderWithoutDueDate),
+		errors.Is(err, errorx.ErrUnsupportedChannel),
+		errors.Is(err, errorx.ErrWebhookURLRequired),
+		errors.Is(err, errorx.ErrWebhookURLNotAllowed):
+		code = http.StatusUnprocessableEntity
+	}
+
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
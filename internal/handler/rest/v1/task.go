@@ -2,36 +2,95 @@ package v1
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"gitlab.com/jodworkspace/mvp/internal/domain"
 	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
 	"gitlab.com/jodworkspace/mvp/pkg/utils/helper"
 	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/rrulex"
 )
 
 type TaskUC interface {
-	Count(ctx context.Context, ownerID string) (int64, error)
-	List(ctx context.Context, page, pageSize uint64, ownerID string) ([]*domain.Task, error)
+	Count(ctx context.Context, filter *domain.TaskFilter) (int64, error)
+	List(ctx context.Context, p *domain.Pagination, filter *domain.TaskFilter) ([]*domain.Task, *domain.PageTokens, error)
+	Search(ctx context.Context, q, ownerID string, page, pageSize uint64) ([]*domain.TaskSearchResult, error)
 	Create(ctx context.Context, task *domain.Task) error
-	Get(ctx context.Context, id string) (*domain.Task, error)
-	Update(ctx context.Context, task *domain.Task) error
-	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id, ownerID string) (*domain.Task, error)
+	Update(ctx context.Context, task *domain.Task, version *int64, scope string) error
+	Patch(ctx context.Context, id, ownerID string, version *int64, patch *domain.TaskPatch, scope string) (*domain.Task, error)
+	Delete(ctx context.Context, id, ownerID string, version *int64) error
+	Restore(ctx context.Context, id, ownerID string) error
+	Children(ctx context.Context, id, ownerID string) ([]*domain.Task, error)
+	Tree(ctx context.Context, id, ownerID string) (*domain.TaskNode, error)
+	Occurrences(ctx context.Context, id, ownerID string, n int) ([]*domain.TaskOccurrence, error)
+	Batch(ctx context.Context, ownerID, mode string, ops []*domain.TaskBatchOperation) ([]*domain.TaskBatchResult, error)
+	Export(ctx context.Context, ownerID string, fn func(*domain.TaskRecord) error) error
+	Import(ctx context.Context, ownerID string, records []*domain.TaskRecord) error
+	QuickAdd(ctx context.Context, task *domain.Task, labels []string) error
+	Agenda(ctx context.Context, ownerID string, from, to time.Time, loc *time.Location) ([]*domain.AgendaDay, error)
+	DayPlan(ctx context.Context, ownerID, day string) ([]*domain.Task, error)
+	PlanDay(ctx context.Context, ownerID, day string, taskIDs []string) ([]*domain.Task, error)
+	History(ctx context.Context, id, ownerID string, page, pageSize uint64) ([]*domain.TaskEvent, int64, error)
+	AddDependency(ctx context.Context, id, blockerID, ownerID string) error
+	RemoveDependency(ctx context.Context, id, blockerID, ownerID string) error
+	Next(ctx context.Context, ownerID string, limit uint64) ([]*domain.NextTask, error)
+	Board(ctx context.Context, projectID, ownerID string, filter *domain.TaskFilter) ([]*domain.BoardColumn, error)
+	Move(ctx context.Context, id, ownerID string, version *int64, statusID *string, placement *domain.Placement) (*domain.Task, error)
 }
 
+// TaskHandler serves the tasks. Reads carry an ETag, and writes to a single
+// task take it back in If-Match so that concurrent edits fail with 412
+// instead of overwriting each other. Without requireIfMatch, writes missing
+// the header go through unconditionally.
 type TaskHandler struct {
-	taskUC TaskUC
-	logger *logger.ZapLogger
+	taskUC         TaskUC
+	requireIfMatch bool
+	logger         *logger.ZapLogger
 }
 
-func NewTaskHandler(taskUC TaskUC, zl *logger.ZapLogger) *TaskHandler {
+func NewTaskHandler(taskUC TaskUC, requireIfMatch bool, zl *logger.ZapLogger) *TaskHandler {
 	return &TaskHandler{
-		taskUC: taskUC,
-		logger: zl,
+		taskUC:         taskUC,
+		requireIfMatch: requireIfMatch,
+		logger:         zl,
 	}
 }
 
 func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, nil)
+}
+
+// ListByProject lists the tasks of the project in the path, archived or not,
+// with the same query parameters as List.
+func (h *TaskHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+	h.list(w, r, func(filter *domain.TaskFilter) {
+		filter.ProjectID, filter.NoProject = &projectID, false
+	})
+}
+
+// Trash lists the trashed tasks, most recently trashed first unless sorted
+// otherwise, with the same query parameters as List.
+func (h *TaskHandler) Trash(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, func(filter *domain.TaskFilter) {
+		filter.Trashed, filter.IncludeArchived = true, true
+		if len(filter.Sort) == 0 {
+			filter.Sort = []domain.SortField{{Column: domain.ColTaskDeletedAt, Desc: true}}
+		}
+	})
+}
+
+// list serves a task listing, with scope narrowing the filter read from the
+// query string when given.
+func (h *TaskHandler) list(w http.ResponseWriter, r *http.Request, scope func(*domain.TaskFilter)) {
 	p, ok := r.Context().Value(domain.KeyPagination).(*domain.Pagination)
 	if !ok {
 		p = &domain.Pagination{
@@ -48,15 +107,30 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tasks, err := h.taskUC.List(r.Context(), p.Page, p.PageSize, ownerID)
+	filter, err := parseTaskFilter(r.URL.Query(), ownerID)
 	if err != nil {
 		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
-			Code: http.StatusInternalServerError,
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
 		})
 		return
 	}
 
-	total, err := h.taskUC.Count(r.Context(), ownerID)
+	if scope != nil {
+		scope(filter)
+	}
+
+	tasks, tokens, err := h.taskUC.List(r.Context(), p, filter)
+	if errors.Is(err, errorx.ErrProjectNotFound) {
+		writeProjectError(w, err)
+		return
+	}
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	total, err := h.taskUC.Count(r.Context(), filter)
 	if err != nil {
 		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
 			Code: http.StatusInternalServerError,
@@ -64,19 +138,76 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Page tokens are sealed with a fresh nonce on every read, so the tag
+	// only records whether there are pages around this one.
+	etag := listETag(tasks, strconv.FormatUint(p.Page, 10), strconv.FormatUint(p.PageSize, 10),
+		strconv.FormatBool(p.PageToken == ""), strconv.FormatBool(tokens.Next != ""),
+		strconv.FormatBool(tokens.Prev != ""), strconv.FormatInt(total, 10))
+	if notModified(w, r, etag) {
+		return
+	}
+
+	resp := httpx.JSON{
+		"pageSize":      p.PageSize,
+		"nextPageToken": tokens.Next,
+		"prevPageToken": tokens.Prev,
+		"total":         total,
+		"tasks":         tasks,
+	}
+	// A page reached through a token has no page number.
+	if p.PageToken == "" {
+		resp["page"] = p.Page
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, resp)
+}
+
+// Search ranks the user's tasks against the full-text query in q.
+func (h *TaskHandler) Search(w http.ResponseWriter, r *http.Request) {
+	p, ok := r.Context().Value(domain.KeyPagination).(*domain.Pagination)
+	if !ok {
+		p = &domain.Pagination{
+			Page:     1,
+			PageSize: 10,
+		}
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "query can not be empty",
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	results, err := h.taskUC.Search(r.Context(), q, ownerID, p.Page, p.PageSize)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
 	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
-		"page":  p.Page,
-		"total": total,
-		"tasks": tasks,
+		"page":     p.Page,
+		"pageSize": p.PageSize,
+		"results":  results,
 	})
 }
 
 func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Title     string `json:"title" validate:"required"`
-		Priority  int    `json:"priority" validate:"required"`
-		StartDate string `json:"startDate"`
-		DueDate   string `json:"dueDate" `
+		Title     string   `json:"title" validate:"required,max=255"`
+		Details   string   `json:"details"`
+		Priority  int      `json:"priority" validate:"required"`
+		StartDate string   `json:"startDate"`
+		DueDate   string   `json:"dueDate" `
+		ParentID  *string  `json:"parentID" validate:"omitnil,uuid"`
+		ProjectID *string  `json:"projectID" validate:"omitnil,uuid"`
+		LabelIDs  []string `json:"labelIDs" validate:"omitempty,dive,uuid"`
+		RRule     string   `json:"rrule"`
+		TimeZone  string   `json:"timezone"`
+		Estimate  *int     `json:"estimateMinutes" validate:"omitnil,min=1"`
 	}
 
 	if err, details := BindWithValidation(r, &input); err != nil {
@@ -105,34 +236,293 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 			Code:    http.StatusBadRequest,
 			Message: "invalid due date format",
 		})
+		return
+	}
+
+	_, err = parseTimeZone(input.TimeZone)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
 	}
 
 	ownerID, _ := r.Context().Value("user_id").(string)
 	task := &domain.Task{
-		Title:     input.Title,
-		Priority:  input.Priority,
-		StartDate: startDate,
-		DueDate:   dueDate,
-		OwnerID:   ownerID,
+		Title:           input.Title,
+		Details:         input.Details,
+		Priority:        input.Priority,
+		StartDate:       startDate,
+		DueDate:         dueDate,
+		OwnerID:         ownerID,
+		ParentID:        input.ParentID,
+		ProjectID:       input.ProjectID,
+		Labels:          domain.LabelRefs(input.LabelIDs),
+		RRule:           input.RRule,
+		TimeZone:        input.TimeZone,
+		EstimateMinutes: input.Estimate,
 	}
 
 	err = h.taskUC.Create(r.Context(), task)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"task": task,
+	})
+}
+
+func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "task id can not be empty",
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	task, err := h.taskUC.Get(r.Context(), taskID, ownerID)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	if notModified(w, r, taskETag(task)) {
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"task": task,
+	})
+}
+
+// Update replaces a task, so leaving out rrule stops a recurrence. For a
+// recurring task, scope=future applies the edit to the next occurrences as
+// well; the default scope=this only to this one.
+func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "task id can not be empty",
+		})
+		return
+	}
+
+	scope, err := parseScope(r.URL.Query().Get("scope"))
 	if err != nil {
 		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
-			Code:    http.StatusInternalServerError,
+			Code:    http.StatusBadRequest,
 			Message: err.Error(),
 		})
 		return
 	}
 
-	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+	version, err := parseIfMatch(r, h.requireIfMatch)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	var input struct {
+		Title       string   `json:"title" validate:"required,max=255"`
+		Details     string   `json:"details"`
+		Priority    int      `json:"priority" validate:"required"`
+		IsCompleted bool     `json:"isCompleted"`
+		StartDate   string   `json:"startDate"`
+		DueDate     string   `json:"dueDate"`
+		ParentID    *string  `json:"parentID" validate:"omitnil,uuid"`
+		ProjectID   *string  `json:"projectID" validate:"omitnil,uuid"`
+		LabelIDs    []string `json:"labelIDs" validate:"omitempty,dive,uuid"`
+		RRule       string   `json:"rrule"`
+		TimeZone    string   `json:"timezone"`
+		Estimate    *int     `json:"estimateMinutes" validate:"omitnil,min=1"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	startDate, err := helper.ParseISO8601Date(input.StartDate)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid start date format",
+		})
+		return
+	}
+
+	dueDate, err := helper.ParseISO8601Date(input.DueDate)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid due date format",
+		})
+		return
+	}
+
+	_, err = parseTimeZone(input.TimeZone)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	task := &domain.Task{
+		ID:              taskID,
+		Title:           input.Title,
+		Details:         input.Details,
+		Priority:        input.Priority,
+		IsCompleted:     input.IsCompleted,
+		StartDate:       startDate,
+		DueDate:         dueDate,
+		OwnerID:         ownerID,
+		ParentID:        input.ParentID,
+		ProjectID:       input.ProjectID,
+		Labels:          domain.LabelRefs(input.LabelIDs),
+		RRule:           input.RRule,
+		TimeZone:        input.TimeZone,
+		EstimateMinutes: input.Estimate,
+	}
+
+	err = h.taskUC.Update(r.Context(), task, version, scope)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
 		"task": task,
 	})
 }
 
-func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {}
+// Patch partially updates a task from an RFC 7396 JSON merge patch document.
+// The merged task is validated before anything is written. The scope query
+// parameter works as in Update.
+func (h *TaskHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "task id can not be empty",
+		})
+		return
+	}
 
-func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {}
+	scope, err := parseScope(r.URL.Query().Get("scope"))
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	version, err := parseIfMatch(r, h.requireIfMatch)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != httpx.MediaTypeMergePatch && mediaType != httpx.MediaTypeJSON {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusUnsupportedMediaType,
+			Message: "content type must be " + httpx.MediaTypeMergePatch,
+		})
+		return
+	}
+
+	var patch domain.TaskPatch
+	err = httpx.ReadJSON(r, &patch)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if patch.Title.Null || patch.Priority.Null || patch.IsCompleted.Null {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "title, priority and isCompleted can not be null",
+		})
+		return
+	}
+
+	_, err = parseTimeZone(patch.TimeZone.Value)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	task, err := h.taskUC.Get(r.Context(), taskID, ownerID)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	patch.Apply(task)
+	errs := validateStruct(struct {
+		Title     string   `validate:"required,max=255"`
+		Priority  int      `validate:"required"`
+		ParentID  *string  `validate:"omitnil,uuid"`
+		ProjectID *string  `validate:"omitnil,uuid"`
+		LabelIDs  []string `validate:"omitempty,dive,uuid"`
+		Estimate  *int     `validate:"omitnil,min=1"`
+	}{
+		Title:     task.Title,
+		Priority:  task.Priority,
+		ParentID:  task.ParentID,
+		ProjectID: task.ProjectID,
+		LabelIDs:  task.LabelIDs(),
+		Estimate:  task.EstimateMinutes,
+	})
+	if len(errs) > 0 {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code: http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf(
+				"invalid value for %s: expected %s, got %v",
+				errs[0].Field,
+				errs[0].Tag,
+				errs[0].Value,
+			),
+		})
+		return
+	}
+
+	task, err = h.taskUC.Patch(r.Context(), taskID, ownerID, version, &patch, scope)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"task": task,
+	})
+}
 
 func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	taskID := r.PathValue("id")
@@ -144,14 +534,222 @@ func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.taskUC.Delete(r.Context(), taskID)
+	version, err := parseIfMatch(r, h.requireIfMatch)
 	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err = h.taskUC.Delete(r.Context(), taskID, ownerID, version)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
+
+// Restore takes a task out of the trash, with the subtasks trashed along with it.
+func (h *TaskHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
 		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+			Message: "task id can not be empty",
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err := h.taskUC.Restore(r.Context(), taskID, ownerID)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	task, err := h.taskUC.Get(r.Context(), taskID, ownerID)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"task": task,
+	})
+}
+
+// Children lists the direct subtasks of a task.
+func (h *TaskHandler) Children(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "task id can not be empty",
 		})
 		return
 	}
 
-	_ = httpx.WriteJSON(w, http.StatusNoContent, nil)
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	tasks, err := h.taskUC.Children(r.Context(), taskID, ownerID)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"tasks": tasks,
+	})
+}
+
+// Tree returns a task with its whole hierarchy of subtasks and their progress.
+func (h *TaskHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "task id can not be empty",
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	tree, err := h.taskUC.Tree(r.Context(), taskID, ownerID)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"task": tree,
+	})
+}
+
+// Occurrences previews the next occurrences of a recurring task, 5 by default
+// and at most 100.
+func (h *TaskHandler) Occurrences(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "task id can not be empty",
+		})
+		return
+	}
+
+	count, err := parseIntParam(r.URL.Query(), "count")
+	if err != nil || (count != nil && (*count < 1 || *count > maxOccurrences)) {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("count must be between 1 and %d", maxOccurrences),
+		})
+		return
+	}
+	n := defaultOccurrences
+	if count != nil {
+		n = *count
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	occurrences, err := h.taskUC.Occurrences(r.Context(), taskID, ownerID, n)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"occurrences": occurrences,
+	})
+}
+
+// History lists the recorded changes of a task, newest first.
+func (h *TaskHandler) History(w http.ResponseWriter, r *http.Request) {
+	p, ok := r.Context().Value(domain.KeyPagination).(*domain.Pagination)
+	if !ok {
+		p = &domain.Pagination{
+			Page:     1,
+			PageSize: 10,
+		}
+	}
+
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "task id can not be empty",
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	events, total, err := h.taskUC.History(r.Context(), taskID, ownerID, p.Page, p.PageSize)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"page":     p.Page,
+		"pageSize": p.PageSize,
+		"total":    total,
+		"events":   events,
+	})
+}
+
+const (
+	defaultOccurrences = 5
+	maxOccurrences     = 100
+)
+
+// parseScope reads the scope of an edit to a recurring task.
+func parseScope(scope string) (string, error) {
+	switch scope {
+	case "":
+		return domain.TaskScopeThis, nil
+	case domain.TaskScopeThis, domain.TaskScopeFuture:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("invalid value for scope: %q", scope)
+	}
+}
+
+// writeTaskError maps task use case errors to their HTTP status codes.
+func writeTaskError(w http.ResponseWriter, err error) {
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code:    taskErrorStatus(err),
+		Message: err.Error(),
+	})
+}
+
+func taskErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errorx.ErrTaskNotFound),
+		errors.Is(err, errorx.ErrDependencyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errorx.ErrInvalidPageToken),
+		errors.Is(err, errorx.ErrInvalidIfMatch):
+		return http.StatusBadRequest
+	case errors.Is(err, errorx.ErrTaskModified):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, errorx.ErrIfMatchRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, errorx.ErrParentTaskTrashed),
+		errors.Is(err, errorx.ErrTaskBlocked):
+		return http.StatusConflict
+	case errors.Is(err, errorx.ErrParentTaskNotFound),
+		errors.Is(err, errorx.ErrTaskCycle),
+		errors.Is(err, errorx.ErrDependencyCycle),
+		errors.Is(err, errorx.ErrLabelNotFound),
+		errors.Is(err, errorx.ErrProjectNotFound),
+		errors.Is(err, errorx.ErrTaskNotRecurring),
+		errors.Is(err, errorx.ErrTaskNotInProject),
+		errors.Is(err, errorx.ErrStatusNotFound),
+		errors.Is(err, errorx.ErrInvalidPlacement),
+		errors.Is(err, errorx.ErrRecurrenceWithoutDate),
+		errors.Is(err, errorx.ErrInvalidAgendaRange),
+		errors.Is(err, rrulex.ErrInvalidRule):
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusInternalServerError
 }
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+// agendaDays is the number of days of an agenda without a range.
+const agendaDays = 7
+
+// Agenda lists, day by day, the tasks planned, starting or due and the
+// scheduled occurrences of recurring tasks between the from and to dates,
+// both included, in the time zone tz. Without a range it covers the week
+// from today.
+func (h *TaskHandler) Agenda(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	loc, err := parseTimeZone(values.Get("tz"))
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var from, to time.Time
+	if values.Get("from") == "" && values.Get("to") == "" {
+		from = today(loc)
+		to = from.AddDate(0, 0, agendaDays)
+	} else {
+		from, to, err = parseDateRange(values, loc)
+		if err != nil {
+			_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	days, err := h.taskUC.Agenda(r.Context(), ownerID, from, to, loc)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"timezone": loc.String(),
+		"days":     days,
+	})
+}
+
+// DayPlan returns the tasks planned on the date in the query, today in the
+// time zone tz by default.
+func (h *TaskHandler) DayPlan(w http.ResponseWriter, r *http.Request) {
+	day, err := parsePlanDay(r.URL.Query())
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	tasks, err := h.taskUC.DayPlan(r.Context(), ownerID, day)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"date":  day,
+		"tasks": tasks,
+	})
+}
+
+// PlanDay replaces the plan of the date in the query, today in the time zone
+// tz by default, with the given tasks in order.
+func (h *TaskHandler) PlanDay(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		TaskIDs []string `json:"taskIDs" validate:"max=100,unique,dive,uuid"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	day, err := parsePlanDay(r.URL.Query())
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	tasks, err := h.taskUC.PlanDay(r.Context(), ownerID, day, input.TaskIDs)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"date":  day,
+		"tasks": tasks,
+	})
+}
+
+// parsePlanDay reads the date of a day plan as YYYY-MM-DD, today in the time
+// zone tz when missing.
+func parsePlanDay(values url.Values) (string, error) {
+	date := values.Get("date")
+	if date != "" {
+		_, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			return "", fmt.Errorf("invalid value for date: expected YYYY-MM-DD")
+		}
+		return date, nil
+	}
+
+	loc, err := parseTimeZone(values.Get("tz"))
+	if err != nil {
+		return "", err
+	}
+
+	return today(loc).Format(time.DateOnly), nil
+}
+
+// today returns the last midnight in loc.
+func today(loc *time.Location) time.Time {
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+// maxBatchItems bounds the number of (operation, task) pairs of a batch.
+const maxBatchItems = 500
+
+type taskBatchResult struct {
+	Operation int                  `json:"operation"`
+	ID        string               `json:"id"`
+	Status    string               `json:"status"`
+	Error     *httpx.ErrorResponse `json:"error,omitempty"`
+}
+
+// Batch applies a list of operations to many tasks in one transaction. An
+// atomic batch, the default, is rolled back as a whole when any item fails and
+// answers 422, or 412 when an item failed on its version; a best-effort batch
+// keeps the items that succeeded. Both report the outcome of every item. The
+// version of each task goes in versions, keyed by task ID, and is required
+// whenever If-Match is.
+func (h *TaskHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Mode       string `json:"mode" validate:"omitempty,oneof=atomic bestEffort"`
+		Operations []struct {
+			Op             string           `json:"op" validate:"required,oneof=complete reopen prioritise relabel move delete"`
+			IDs            []string         `json:"ids" validate:"required,min=1,dive,uuid"`
+			Versions       map[string]int64 `json:"versions" validate:"omitempty,dive,keys,uuid,endkeys,min=0"`
+			Priority       *int             `json:"priority" validate:"required_if=Op prioritise"`
+			AddLabelIDs    []string         `json:"addLabelIDs" validate:"omitempty,dive,uuid"`
+			RemoveLabelIDs []string         `json:"removeLabelIDs" validate:"omitempty,dive,uuid"`
+			ProjectID      *string          `json:"projectID" validate:"omitnil,uuid"`
+		} `json:"operations" validate:"required,min=1,dive"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	mode := input.Mode
+	if mode == "" {
+		mode = domain.TaskBatchAtomic
+	}
+
+	items := 0
+	ops := make([]*domain.TaskBatchOperation, 0, len(input.Operations))
+	for i, in := range input.Operations {
+		if in.Op == domain.TaskOpRelabel && len(in.AddLabelIDs) == 0 && len(in.RemoveLabelIDs) == 0 {
+			_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("operation %d: relabel needs addLabelIDs or removeLabelIDs", i),
+			})
+			return
+		}
+
+		if h.requireIfMatch {
+			for _, id := range in.IDs {
+				if _, ok := in.Versions[id]; !ok {
+					_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+						Code:    http.StatusPreconditionRequired,
+						Message: fmt.Sprintf("operation %d: %s", i, errorx.ErrVersionRequired),
+					})
+					return
+				}
+			}
+		}
+
+		op := &domain.TaskBatchOperation{
+			Op:             in.Op,
+			IDs:            in.IDs,
+			Versions:       in.Versions,
+			AddLabelIDs:    in.AddLabelIDs,
+			RemoveLabelIDs: in.RemoveLabelIDs,
+			ProjectID:      in.ProjectID,
+		}
+		if in.Priority != nil {
+			op.Priority = *in.Priority
+		}
+
+		items += len(in.IDs)
+		ops = append(ops, op)
+	}
+
+	if items > maxBatchItems {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("a batch can not exceed %d items", maxBatchItems),
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	results, err := h.taskUC.Batch(r.Context(), ownerID, mode, ops)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	var failed int
+	var firstErr error
+	out := make([]*taskBatchResult, 0, len(results))
+	for _, result := range results {
+		item := &taskBatchResult{
+			Operation: result.Operation,
+			ID:        result.ID,
+			Status:    result.Status,
+		}
+		if result.Err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = result.Err
+			}
+			item.Error = &httpx.ErrorResponse{
+				Code:    taskErrorStatus(result.Err),
+				Message: result.Err.Error(),
+			}
+		}
+		out = append(out, item)
+	}
+
+	if mode == domain.TaskBatchAtomic && firstErr != nil {
+		// A failed operation is answered as a whole with 422, unless it hit a
+		// stale version or a server fault, which keep their own status.
+		code := taskErrorStatus(firstErr)
+		if code < http.StatusInternalServerError && code != http.StatusPreconditionFailed {
+			code = http.StatusUnprocessableEntity
+		}
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    code,
+			Message: "batch rolled back: " + firstErr.Error(),
+			Details: httpx.JSON{
+				"results": out,
+			},
+		})
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"mode":      mode,
+		"succeeded": len(out) - failed,
+		"failed":    failed,
+		"results":   out,
+	})
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+// Board returns the tasks of the project in the path grouped by status
+// column, with the same filters as List.
+func (h *TaskHandler) Board(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	filter, err := parseTaskFilter(r.URL.Query(), ownerID)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	columns, err := h.taskUC.Board(r.Context(), r.PathValue("id"), ownerID, filter)
+	if err != nil {
+		if errors.Is(err, errorx.ErrProjectNotFound) {
+			writeProjectError(w, err)
+			return
+		}
+
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"columns": columns,
+	})
+}
+
+// Move puts the task in the path into the status column statusID, or the
+// column of tasks without status when null, after afterID and/or before
+// beforeID, or last. It is conditioned on If-Match like the other writes.
+func (h *TaskHandler) Move(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		StatusID *string `json:"statusID" validate:"omitempty,uuid"`
+		AfterID  *string `json:"afterID" validate:"omitempty,uuid"`
+		BeforeID *string `json:"beforeID" validate:"omitempty,uuid"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	version, err := parseIfMatch(r, h.requireIfMatch)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	task, err := h.taskUC.Move(r.Context(), r.PathValue("id"), ownerID, version, input.StatusID, &domain.Placement{
+		AfterID:  input.AfterID,
+		BeforeID: input.BeforeID,
+	})
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"task": task,
+	})
+}
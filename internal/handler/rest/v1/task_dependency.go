@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+const (
+	defaultNextTasks = 20
+	maxNextTasks     = 100
+)
+
+// AddDependency makes the task in the body block the task of the path and
+// returns the latter.
+func (h *TaskHandler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		BlockerID string `json:"blockerID" validate:"required,uuid"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	taskID := r.PathValue("id")
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err := h.taskUC.AddDependency(r.Context(), taskID, input.BlockerID, ownerID)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	task, err := h.taskUC.Get(r.Context(), taskID, ownerID)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"task": task,
+	})
+}
+
+func (h *TaskHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err := h.taskUC.RemoveDependency(r.Context(), r.PathValue("id"), r.PathValue("blockerID"), ownerID)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
+
+// Next lists the open tasks in the order they can be done, the ones blocked
+// by nothing open first.
+func (h *TaskHandler) Next(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntParam(r.URL.Query(), "limit")
+	if err != nil || (limit != nil && (*limit < 1 || *limit > maxNextTasks)) {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("limit must be between 1 and %d", maxNextTasks),
+		})
+		return
+	}
+	n := defaultNextTasks
+	if limit != nil {
+		n = *limit
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	tasks, err := h.taskUC.Next(r.Context(), ownerID, uint64(n))
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"tasks": tasks,
+	})
+}
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+)
+
+// taskETag is the strong entity tag of a task, its version. It follows the
+// columns of the task, not what is loaded along with it such as comment
+// counts.
+func taskETag(task *domain.Task) string {
+	return `"` + strconv.FormatInt(task.Version, 10) + `"`
+}
+
+// listETag is a weak entity tag for a page of tasks, changing whenever a task
+// of the page does or the page itself shifts.
+func listETag(tasks []*domain.Task, page ...string) string {
+	h := sha256.New()
+	for _, part := range page {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	for _, task := range tasks {
+		h.Write([]byte(task.ID + ":" + strconv.FormatInt(task.Version, 10)))
+		h.Write([]byte{0})
+	}
+
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// notModified sets the ETag header of a read and, when the If-None-Match
+// header of the request matches it, answers 304 and reports true.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	// If-None-Match uses the weak comparison, ignoring the W/ prefix.
+	match := header == "*"
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == strings.TrimPrefix(etag, "W/") {
+			match = true
+		}
+	}
+	if !match {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// parseIfMatch reads the version a write is conditioned on from the If-Match
+// header. Nil means any version, either from * or, unless required, from a
+// missing header.
+func parseIfMatch(r *http.Request, required bool) (*int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	switch header {
+	case "":
+		if required {
+			return nil, errorx.ErrIfMatchRequired
+		}
+		return nil, nil
+	case "*":
+		return nil, nil
+	}
+
+	if strings.Contains(header, ",") {
+		return nil, errorx.ErrInvalidIfMatch
+	}
+
+	// If-Match uses the strong comparison, so a weak tag never matches.
+	if strings.HasPrefix(header, "W/") {
+		return nil, errorx.ErrTaskModified
+	}
+
+	tag, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		tag, ok = strings.CutSuffix(tag, `"`)
+	}
+	if !ok {
+		return nil, errorx.ErrInvalidIfMatch
+	}
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil {
+		// Not a tag this server hands out, so it matches no version.
+		return nil, errorx.ErrTaskModified
+	}
+
+	return &version, nil
+}
@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header   string
+		required bool
+		version  int64 // -1 for any version
+		err      error
+	}{
+		{header: "", version: -1},
+		{header: "", required: true, err: errorx.ErrIfMatchRequired},
+		{header: "*", required: true, version: -1},
+		{header: `"7"`, version: 7},
+		{header: ` "7" `, required: true, version: 7},
+		{header: `W/"7"`, err: errorx.ErrTaskModified},
+		{header: `"abc"`, err: errorx.ErrTaskModified},
+		{header: `"7", "8"`, err: errorx.ErrInvalidIfMatch},
+		{header: `7`, err: errorx.ErrInvalidIfMatch},
+		{header: `"7`, err: errorx.ErrInvalidIfMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/tasks/1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+
+			version, err := parseIfMatch(r, tt.required)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("parseIfMatch() error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			switch {
+			case tt.version < 0 && version != nil:
+				t.Errorf("parseIfMatch() = %d, want any version", *version)
+			case tt.version >= 0 && (version == nil || *version != tt.version):
+				t.Errorf("parseIfMatch() = %v, want %d", version, tt.version)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	etag := taskETag(&domain.Task{Version: 3})
+
+	tests := []struct {
+		header      string
+		notModified bool
+	}{
+		{header: ""},
+		{header: `"2"`},
+		{header: `"3"`, notModified: true},
+		{header: `W/"3"`, notModified: true},
+		{header: `"1", "3"`, notModified: true},
+		{header: "*", notModified: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-None-Match", tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			if got := notModified(w, r, etag); got != tt.notModified {
+				t.Errorf("notModified() = %v, want %v", got, tt.notModified)
+			}
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+
+			code := http.StatusOK
+			if tt.notModified {
+				code = http.StatusNotModified
+			}
+			if w.Code != code {
+				t.Errorf("status = %d, want %d", w.Code, code)
+			}
+		})
+	}
+}
+
+func TestListETag(t *testing.T) {
+	tasks := []*domain.Task{{ID: "a", Version: 1}, {ID: "b", Version: 4}}
+
+	etag := listETag(tasks, "1", "10")
+	if etag != listETag([]*domain.Task{{ID: "a", Version: 1}, {ID: "b", Version: 4}}, "1", "10") {
+		t.Error("listETag() differs for the same page")
+	}
+
+	for name, other := range map[string]string{
+		"version": listETag([]*domain.Task{{ID: "a", Version: 1}, {ID: "b", Version: 5}}, "1", "10"),
+		"order":   listETag([]*domain.Task{tasks[1], tasks[0]}, "1", "10"),
+		"page":    listETag(tasks, "2", "10"),
+		"parts":   listETag(tasks, "11", "0"),
+	} {
+		if other == etag {
+			t.Errorf("listETag() unchanged by %s", name)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package v1
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/helper"
+)
+
+// parseTaskFilter reads the task listing query parameters:
+//
+//	completed=true|false
+//	priorityMin=1&priorityMax=3
+//	startFrom, startTo, dueFrom, dueTo (RFC 3339)
+//	due=overdue|today|week, evaluated in the tz time zone (default UTC)
+//	q=free text matched against title and details
+//	labels=id1,id2&labelMatch=any|all (default any)
+//	project=<id>|none, archived=true to include tasks of archived projects
+//	sort=-priority,dueDate (a leading "-" sorts descending)
+func parseTaskFilter(values url.Values, ownerID string) (*domain.TaskFilter, error) {
+	filter := &domain.TaskFilter{
+		OwnerID: ownerID,
+		Search:  strings.TrimSpace(values.Get("q")),
+	}
+
+	if v := values.Get("completed"); v != "" {
+		completed, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for completed: %q", v)
+		}
+		filter.IsCompleted = &completed
+	}
+
+	var err error
+	if filter.MinPriority, err = parseIntParam(values, "priorityMin"); err != nil {
+		return nil, err
+	}
+	if filter.MaxPriority, err = parseIntParam(values, "priorityMax"); err != nil {
+		return nil, err
+	}
+	if filter.StartFrom, err = parseDateParam(values, "startFrom"); err != nil {
+		return nil, err
+	}
+	if filter.StartTo, err = parseDateParam(values, "startTo"); err != nil {
+		return nil, err
+	}
+	if filter.DueFrom, err = parseDateParam(values, "dueFrom"); err != nil {
+		return nil, err
+	}
+	if filter.DueTo, err = parseDateParam(values, "dueTo"); err != nil {
+		return nil, err
+	}
+
+	if due := values.Get("due"); due != "" {
+		loc, err := parseTimeZone(values.Get("tz"))
+		if err != nil {
+			return nil, err
+		}
+
+		err = applyDueWindow(filter, due, time.Now().In(loc))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch project := values.Get("project"); project {
+	case "":
+	case domain.ProjectFilterNone:
+		filter.NoProject = true
+	default:
+		if uuid.Validate(project) != nil {
+			return nil, fmt.Errorf("invalid value for project: %q", project)
+		}
+		filter.ProjectID = &project
+	}
+
+	if v := values.Get("archived"); v != "" {
+		filter.IncludeArchived, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for archived: %q", v)
+		}
+	}
+
+	filter.LabelIDs, filter.LabelMatch, err = parseLabelParams(values)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Sort, err = parseSort(values.Get("sort"), domain.TaskSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// applyDueWindow narrows the due date range to one of the domain.TaskDue* presets,
+// intersecting it with dueFrom and dueTo when they are given too.
+func applyDueWindow(filter *domain.TaskFilter, window string, now time.Time) error {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch window {
+	case domain.TaskDueOverdue:
+		filter.Overdue = true
+	case domain.TaskDueToday:
+		narrowDue(filter, startOfDay.UTC(), startOfDay.AddDate(0, 0, 1).UTC())
+	case domain.TaskDueThisWeek:
+		// ISO weeks start on Monday.
+		offset := (int(startOfDay.Weekday()) + 6) % 7
+		monday := startOfDay.AddDate(0, 0, -offset)
+		narrowDue(filter, monday.UTC(), monday.AddDate(0, 0, 7).UTC())
+	default:
+		return fmt.Errorf("invalid value for due: %q", window)
+	}
+
+	return nil
+}
+
+// narrowDue intersects the due date range of filter with [from, to).
+func narrowDue(filter *domain.TaskFilter, from, to time.Time) {
+	if filter.DueFrom == nil || filter.DueFrom.Before(from) {
+		filter.DueFrom = &from
+	}
+	if filter.DueTo == nil || filter.DueTo.After(to) {
+		filter.DueTo = &to
+	}
+}
+
+// parseLabelParams reads the comma separated label ids and how they must match.
+func parseLabelParams(values url.Values) ([]string, string, error) {
+	match := values.Get("labelMatch")
+	switch match {
+	case "":
+		match = domain.LabelMatchAny
+	case domain.LabelMatchAny, domain.LabelMatchAll:
+	default:
+		return nil, "", fmt.Errorf("invalid value for labelMatch: %q", match)
+	}
+
+	raw := values.Get("labels")
+	if raw == "" {
+		return nil, match, nil
+	}
+
+	ids := make([]string, 0)
+	for _, id := range strings.Split(raw, ",") {
+		id = strings.TrimSpace(id)
+		if err := uuid.Validate(id); err != nil {
+			return nil, "", fmt.Errorf("invalid label id: %q", id)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, match, nil
+}
+
+// parseSort turns "-priority,dueDate" into sort fields, rejecting keys missing from columns.
+func parseSort(raw string, columns map[string]string) ([]domain.SortField, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	fields := make([]domain.SortField, 0)
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.TrimSpace(key)
+		desc := strings.HasPrefix(key, "-")
+		key = strings.TrimPrefix(strings.TrimPrefix(key, "-"), "+")
+
+		column, ok := columns[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid sort key: %q", key)
+		}
+		if seen[column] {
+			continue
+		}
+		seen[column] = true
+
+		fields = append(fields, domain.SortField{Column: column, Desc: desc})
+	}
+
+	return fields, nil
+}
+
+func parseIntParam(values url.Values, key string) (*int, error) {
+	v := values.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %q", key, v)
+	}
+
+	return &i, nil
+}
+
+func parseDateParam(values url.Values, key string) (*time.Time, error) {
+	t, err := helper.ParseISO8601Date(values.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: expected RFC 3339 date", key)
+	}
+
+	return t, nil
+}
+
+// parseTimeZone loads an IANA time zone, UTC by default. The server's local
+// zone is not a zone clients can name.
+func parseTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("invalid time zone: %q", name)
+	}
+
+	return loc, nil
+}
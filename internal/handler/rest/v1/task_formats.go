@@ -0,0 +1,476 @@
+package v1
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/todotxt"
+)
+
+// defaultImportPriority is given to imported tasks without a priority.
+const defaultImportPriority = 1
+
+// maxImportLineBytes bounds a line of a JSON Lines or todo.txt import.
+const maxImportLineBytes = 1 << 20
+
+// taskFormat reads and writes task records in one file format. read returns
+// the valid records and the errors of the invalid lines; its error is only
+// set when the input can not be read any further.
+type taskFormat struct {
+	contentType string
+	filename    string
+	newWriter   func(w io.Writer) taskRecordWriter
+	read        func(r io.Reader) ([]*domain.TaskRecord, []*domain.TaskImportError, error)
+}
+
+type taskRecordWriter interface {
+	Write(record *domain.TaskRecord) error
+	Flush() error
+}
+
+var taskFormats = map[string]*taskFormat{
+	domain.TaskFormatCSV: {
+		contentType: "text/csv; charset=utf-8",
+		filename:    "tasks.csv",
+		newWriter:   newCSVTaskWriter,
+		read:        readCSVTasks,
+	},
+	domain.TaskFormatJSONL: {
+		contentType: "application/jsonl; charset=utf-8",
+		filename:    "tasks.jsonl",
+		newWriter:   newJSONLTaskWriter,
+		read:        readJSONLTasks,
+	},
+	domain.TaskFormatTodoTxt: {
+		contentType: "text/plain; charset=utf-8",
+		filename:    "todo.txt",
+		newWriter:   newTodoTxtTaskWriter,
+		read:        readTodoTxtTasks,
+	},
+}
+
+// CSV
+
+var csvTaskColumns = []string{
+	"title",
+	"details",
+	"priority",
+	"isCompleted",
+	"startDate",
+	"dueDate",
+	"project",
+	"labels",
+	"createdAt",
+	"updatedAt",
+}
+
+// csvLabelSeparator separates the label names of a CSV cell.
+const csvLabelSeparator = ";"
+
+type csvTaskWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVTaskWriter(w io.Writer) taskRecordWriter {
+	return &csvTaskWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvTaskWriter) Write(record *domain.TaskRecord) error {
+	err := c.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	return c.w.Write([]string{
+		record.Title,
+		record.Details,
+		strconv.Itoa(record.Priority),
+		strconv.FormatBool(record.IsCompleted),
+		formatRecordTime(record.StartDate),
+		formatRecordTime(record.DueDate),
+		record.Project,
+		strings.Join(record.Labels, csvLabelSeparator),
+		formatRecordTime(record.CreatedAt),
+		formatRecordTime(record.UpdatedAt),
+	})
+}
+
+func (c *csvTaskWriter) Flush() error {
+	err := c.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvTaskWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+
+	c.header = true
+	return c.w.Write(csvTaskColumns)
+}
+
+// readCSVTasks reads a CSV file whose header names the columns, in any order.
+// Only the title column is required.
+func readCSVTasks(r io.Reader) ([]*domain.TaskRecord, []*domain.TaskImportError, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if !slices.Contains(csvTaskColumns, name) {
+			return nil, nil, fmt.Errorf("line 1: unknown column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, nil, errors.New("line 1: missing title column")
+	}
+
+	records := make([]*domain.TaskRecord, 0)
+	lineErrs := make([]*domain.TaskImportError, 0)
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			lineErrs = append(lineErrs, &domain.TaskImportError{Line: parseErr.Line, Message: parseErr.Err.Error()})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		cell := func(name string) string {
+			i, ok := columns[name]
+			if !ok {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		record, err := csvTaskRecord(cell)
+		if err == nil {
+			err = record.Validate()
+		}
+		if err != nil {
+			lineErrs = append(lineErrs, &domain.TaskImportError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		record.Line = line
+		records = append(records, record)
+	}
+
+	return records, lineErrs, nil
+}
+
+func csvTaskRecord(cell func(name string) string) (*domain.TaskRecord, error) {
+	record := &domain.TaskRecord{
+		Title:    cell("title"),
+		Details:  cell("details"),
+		Priority: defaultImportPriority,
+		Project:  cell("project"),
+	}
+
+	var err error
+	if value := cell("priority"); value != "" {
+		record.Priority, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority %q", value)
+		}
+	}
+
+	if value := cell("isCompleted"); value != "" {
+		record.IsCompleted, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid isCompleted %q", value)
+		}
+	}
+
+	for _, name := range []string{"startDate", "dueDate", "createdAt", "updatedAt"} {
+		t, err := parseRecordTime(cell(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", name, cell(name))
+		}
+
+		switch name {
+		case "startDate":
+			record.StartDate = t
+		case "dueDate":
+			record.DueDate = t
+		case "createdAt":
+			record.CreatedAt = t
+		case "updatedAt":
+			record.UpdatedAt = t
+		}
+	}
+
+	for _, label := range strings.Split(cell("labels"), csvLabelSeparator) {
+		if label = strings.TrimSpace(label); label != "" {
+			record.Labels = append(record.Labels, label)
+		}
+	}
+
+	return record, nil
+}
+
+// JSON Lines
+
+type jsonlTaskWriter struct {
+	encoder *json.Encoder
+}
+
+func newJSONLTaskWriter(w io.Writer) taskRecordWriter {
+	return &jsonlTaskWriter{encoder: json.NewEncoder(w)}
+}
+
+func (j *jsonlTaskWriter) Write(record *domain.TaskRecord) error {
+	return j.encoder.Encode(record)
+}
+
+func (j *jsonlTaskWriter) Flush() error {
+	return nil
+}
+
+// readJSONLTasks reads one JSON object per line, skipping blank lines.
+func readJSONLTasks(r io.Reader) ([]*domain.TaskRecord, []*domain.TaskImportError, error) {
+	return readTaskLines(r, func(line string) (*domain.TaskRecord, error) {
+		record := &domain.TaskRecord{Priority: defaultImportPriority}
+
+		decoder := json.NewDecoder(strings.NewReader(line))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(record)
+		if err != nil {
+			return nil, err
+		}
+		if decoder.More() {
+			return nil, errors.New("a line must hold a single JSON object")
+		}
+
+		return record, nil
+	})
+}
+
+// todo.txt
+
+// todo.txt priorities run from (A), the most important, down to (D). Tasks
+// of priority 1 or less carry none.
+const todoTxtMaxPriority = 5
+
+// todoTxtTags are the key:value tags read into task fields.
+var todoTxtTags = []string{"due", "t", "pri"}
+
+type todoTxtTaskWriter struct {
+	w io.Writer
+}
+
+func newTodoTxtTaskWriter(w io.Writer) taskRecordWriter {
+	return &todoTxtTaskWriter{w: w}
+}
+
+// Write formats the record as a todo.txt line. The due and start dates become
+// due: and t: tags, the project a +project and the labels @contexts. The
+// priority of a completed task moves to a pri: tag, as the format suggests.
+// Details have no place in the format and are left out.
+func (t *todoTxtTaskWriter) Write(record *domain.TaskRecord) error {
+	task := &todotxt.Task{
+		Done:         record.IsCompleted,
+		CreationDate: record.CreatedAt,
+		Text:         record.Title,
+		Contexts:     record.Labels,
+	}
+	if record.IsCompleted {
+		task.CompletionDate = record.UpdatedAt
+	}
+
+	if priority := todoTxtPriority(record.Priority); priority != 0 && record.IsCompleted {
+		task.Tags = append(task.Tags, todotxt.Tag{Key: "pri", Value: string(priority)})
+	} else {
+		task.Priority = priority
+	}
+
+	if record.Project != "" {
+		task.Projects = []string{record.Project}
+	}
+	if record.DueDate != nil {
+		task.Tags = append(task.Tags, todotxt.Tag{Key: "due", Value: todotxt.Date(*record.DueDate)})
+	}
+	if record.StartDate != nil {
+		task.Tags = append(task.Tags, todotxt.Tag{Key: "t", Value: todotxt.Date(*record.StartDate)})
+	}
+
+	_, err := io.WriteString(t.w, task.String()+"\n")
+	return err
+}
+
+func (t *todoTxtTaskWriter) Flush() error {
+	return nil
+}
+
+// readTodoTxtTasks reads one task per line, skipping blank lines. Tags other
+// than due:, t: and pri: are kept in the title.
+func readTodoTxtTasks(r io.Reader) ([]*domain.TaskRecord, []*domain.TaskImportError, error) {
+	return readTaskLines(r, func(line string) (*domain.TaskRecord, error) {
+		task, err := todotxt.Parse(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(task.Projects) > 1 {
+			return nil, errors.New("a task can belong to a single +project")
+		}
+
+		record := &domain.TaskRecord{
+			Title:       task.Text,
+			Priority:    priorityFromTodoTxt(task.Priority),
+			IsCompleted: task.Done,
+			Labels:      slices.Compact(slices.Sorted(slices.Values(task.Contexts))),
+			CreatedAt:   task.CreationDate,
+			UpdatedAt:   task.CompletionDate,
+		}
+		if len(task.Projects) == 1 {
+			record.Project = task.Projects[0]
+		}
+
+		for _, tag := range task.Tags {
+			if !slices.Contains(todoTxtTags, tag.Key) {
+				record.Title += " " + tag.Key + ":" + tag.Value
+			}
+		}
+
+		if value, ok := task.Tag("pri"); ok && task.Priority == 0 {
+			if len(value) != 1 || value[0] < 'A' || value[0] > 'Z' {
+				return nil, fmt.Errorf("invalid pri %q", value)
+			}
+			record.Priority = priorityFromTodoTxt(value[0])
+		}
+
+		for key, date := range map[string]**time.Time{"due": &record.DueDate, "t": &record.StartDate} {
+			value, ok := task.Tag(key)
+			if !ok {
+				continue
+			}
+
+			t, err := todotxt.ParseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", key, value)
+			}
+			*date = &t
+		}
+
+		return record, nil
+	})
+}
+
+func todoTxtPriority(priority int) byte {
+	switch {
+	case priority >= todoTxtMaxPriority:
+		return 'A'
+	case priority <= 1:
+		return 0
+	}
+
+	return byte('A' + todoTxtMaxPriority - priority)
+}
+
+func priorityFromTodoTxt(letter byte) int {
+	switch {
+	case letter == 0:
+		return 1
+	case letter >= 'D':
+		return 2
+	}
+
+	return todoTxtMaxPriority - int(letter-'A')
+}
+
+// readTaskLines reads one record per non-blank line with parse, validating
+// every record and collecting the errors by line.
+func readTaskLines(r io.Reader, parse func(line string) (*domain.TaskRecord, error)) ([]*domain.TaskRecord, []*domain.TaskImportError, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineBytes)
+
+	records := make([]*domain.TaskRecord, 0)
+	lineErrs := make([]*domain.TaskImportError, 0)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" {
+			continue
+		}
+
+		record, err := parse(text)
+		if err == nil {
+			err = record.Validate()
+		}
+		if err != nil {
+			lineErrs = append(lineErrs, &domain.TaskImportError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		record.Line = line
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return records, lineErrs, nil
+}
+
+func formatRecordTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseRecordTime reads an RFC 3339 timestamp or a date, taken as a UTC
+// midnight. An empty string is no time.
+func parseRecordTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, s)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
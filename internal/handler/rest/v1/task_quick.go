@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/quickadd"
+)
+
+// maxLabelNameLength matches the validation of label names.
+const maxLabelNameLength = 64
+
+// QuickAdd creates a task from a line such as "Pay rent tomorrow 9am !!high
+// #finance every month", read in the time zone of the request, and returns
+// what was understood of it beside the task.
+func (h *TaskHandler) QuickAdd(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Text     string `json:"text" validate:"required,max=1000"`
+		TimeZone string `json:"timezone"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	loc, err := parseTimeZone(input.TimeZone)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	parsed, err := quickadd.Parse(input.Text, time.Now().In(loc))
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, quickadd.ErrEmptyTitle) {
+			code = http.StatusUnprocessableEntity
+		}
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    code,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	for _, name := range parsed.Labels {
+		if len(name) > maxLabelNameLength {
+			_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("label names are at most %d characters long", maxLabelNameLength),
+			})
+			return
+		}
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	task := &domain.Task{
+		Title:    parsed.Title,
+		Priority: max(parsed.Priority, 1),
+		OwnerID:  ownerID,
+		RRule:    parsed.RRule,
+		TimeZone: loc.String(),
+	}
+	if parsed.Due != nil {
+		due := parsed.Due.UTC()
+		task.DueDate = &due
+	}
+
+	err = h.taskUC.QuickAdd(r.Context(), task, parsed.Labels)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"task":   task,
+		"parsed": parsed,
+	})
+}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxImportBytes bounds the size of an imported file.
+	maxImportBytes = 10 << 20
+
+	// maxImportRecords bounds the number of tasks of an import.
+	maxImportRecords = 10000
+)
+
+// Export streams every task of the user as a file in the format of the path:
+// csv, jsonl or todotxt.
+func (h *TaskHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format, ok := taskFormats[r.PathValue("format")]
+	if !ok {
+		writeUnsupportedFormat(w, r.PathValue("format"))
+		return
+	}
+
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+format.filename+`"`)
+
+	out := &trackingWriter{ResponseWriter: w}
+	writer := format.newWriter(out)
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err := h.taskUC.Export(r.Context(), ownerID, writer.Write)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		return
+	}
+
+	if !out.written {
+		w.Header().Del("Content-Disposition")
+		writeTaskError(w, err)
+		return
+	}
+
+	// The response is under way, the client sees a truncated file.
+	h.logger.Error(
+		"TaskHandler - Export",
+		zap.String("owner_id", ownerID),
+		zap.Error(err),
+	)
+}
+
+// Import creates tasks from a file in the format of the path. Every line is
+// validated first, and the file is rejected with the errors of its invalid
+// lines unless skipInvalid is set, in which case the valid lines are imported
+// and the others reported.
+func (h *TaskHandler) Import(w http.ResponseWriter, r *http.Request) {
+	format, ok := taskFormats[r.PathValue("format")]
+	if !ok {
+		writeUnsupportedFormat(w, r.PathValue("format"))
+		return
+	}
+
+	var skipInvalid bool
+	if value := r.URL.Query().Get("skipInvalid"); value != "" {
+		var err error
+		skipInvalid, err = strconv.ParseBool(value)
+		if err != nil {
+			_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("invalid value for skipInvalid: %q", value),
+			})
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	defer body.Close()
+
+	records, lineErrs, err := format.read(body)
+	if err != nil {
+		code := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			code = http.StatusRequestEntityTooLarge
+		}
+
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    code,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if len(records)+len(lineErrs) > maxImportRecords {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("an import can not exceed %d tasks", maxImportRecords),
+		})
+		return
+	}
+
+	if len(lineErrs) > 0 && !skipInvalid {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("%d invalid lines, nothing was imported", len(lineErrs)),
+			Details: httpx.JSON{
+				"errors": lineErrs,
+			},
+		})
+		return
+	}
+
+	if len(records) > 0 {
+		ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+		err = h.taskUC.Import(r.Context(), ownerID, records)
+		if err != nil {
+			writeTaskError(w, err)
+			return
+		}
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"imported": len(records),
+		"skipped":  len(lineErrs),
+		"errors":   lineErrs,
+	})
+}
+
+func writeUnsupportedFormat(w http.ResponseWriter, format string) {
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code: http.StatusNotFound,
+		Message: fmt.Sprintf(
+			"unsupported format %q, expected %s, %s or %s",
+			format, domain.TaskFormatCSV, domain.TaskFormatJSONL, domain.TaskFormatTodoTxt,
+		),
+	})
+}
+
+// trackingWriter records whether anything was written to the response.
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (t *trackingWriter) Write(b []byte) (int, error) {
+	t.written = true
+	return t.ResponseWriter.Write(b)
+}
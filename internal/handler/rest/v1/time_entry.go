@@ -0,0 +1,308 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+type TimeEntryUC interface {
+	Timer(ctx context.Context, ownerID string) (*domain.Timer, error)
+	StartTimer(ctx context.Context, taskID, ownerID, note string) (*domain.Timer, error)
+	StopTimer(ctx context.Context, ownerID string) (*domain.TimeEntry, error)
+	DiscardTimer(ctx context.Context, ownerID string) error
+	List(ctx context.Context, taskID, ownerID string, page, pageSize uint64) ([]*domain.TimeEntry, int64, error)
+	Create(ctx context.Context, entry *domain.TimeEntry) error
+	Update(ctx context.Context, entry *domain.TimeEntry) error
+	Delete(ctx context.Context, id, taskID, ownerID string) error
+	Report(ctx context.Context, filter *domain.TimeReportFilter) ([]*domain.TimeReportRow, error)
+}
+
+type TimeEntryHandler struct {
+	timeEntryUC TimeEntryUC
+	logger      *logger.ZapLogger
+}
+
+func NewTimeEntryHandler(timeEntryUC TimeEntryUC, zl *logger.ZapLogger) *TimeEntryHandler {
+	return &TimeEntryHandler{
+		timeEntryUC: timeEntryUC,
+		logger:      zl,
+	}
+}
+
+type timeEntryInput struct {
+	StartedAt time.Time `json:"startedAt" validate:"required"`
+	EndedAt   time.Time `json:"endedAt" validate:"required"`
+	Note      string    `json:"note" validate:"max=1000"`
+}
+
+// Timer returns the running timer of the user.
+func (h *TimeEntryHandler) Timer(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	timer, err := h.timeEntryUC.Timer(r.Context(), ownerID)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"timer": timer,
+	})
+}
+
+// StartTimer starts a timer on the task in the path.
+func (h *TimeEntryHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Note string `json:"note" validate:"max=1000"`
+	}
+
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	timer, err := h.timeEntryUC.StartTimer(r.Context(), r.PathValue("id"), ownerID, input.Note)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"timer": timer,
+	})
+}
+
+// StopTimer stops the running timer of the user and returns the time entry
+// it was saved as.
+func (h *TimeEntryHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	entry, err := h.timeEntryUC.StopTimer(r.Context(), ownerID)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"entry": entry,
+	})
+}
+
+// DiscardTimer stops the running timer of the user without saving it.
+func (h *TimeEntryHandler) DiscardTimer(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err := h.timeEntryUC.DiscardTimer(r.Context(), ownerID)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
+
+// List returns a page of the time entries of a task, latest first.
+func (h *TimeEntryHandler) List(w http.ResponseWriter, r *http.Request) {
+	p, ok := r.Context().Value(domain.KeyPagination).(*domain.Pagination)
+	if !ok {
+		p = &domain.Pagination{
+			Page:     1,
+			PageSize: 10,
+		}
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	entries, total, err := h.timeEntryUC.List(r.Context(), r.PathValue("id"), ownerID, p.Page, p.PageSize)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"page":     p.Page,
+		"pageSize": p.PageSize,
+		"total":    total,
+		"entries":  entries,
+	})
+}
+
+// Create records time spent on the task in the path by hand.
+func (h *TimeEntryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input timeEntryInput
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	entry := &domain.TimeEntry{
+		TaskID:    r.PathValue("id"),
+		OwnerID:   ownerID,
+		StartedAt: input.StartedAt,
+		EndedAt:   input.EndedAt,
+		Note:      input.Note,
+	}
+
+	err := h.timeEntryUC.Create(r.Context(), entry)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusCreated, httpx.JSON{
+		"entry": entry,
+	})
+}
+
+func (h *TimeEntryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input timeEntryInput
+	if err, details := BindWithValidation(r, &input); err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Details: httpx.JSON{
+				"errors": details,
+			},
+		})
+		return
+	}
+
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	entry := &domain.TimeEntry{
+		ID:        r.PathValue("entryID"),
+		TaskID:    r.PathValue("id"),
+		OwnerID:   ownerID,
+		StartedAt: input.StartedAt,
+		EndedAt:   input.EndedAt,
+		Note:      input.Note,
+	}
+
+	err := h.timeEntryUC.Update(r.Context(), entry)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"entry": entry,
+	})
+}
+
+func (h *TimeEntryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	err := h.timeEntryUC.Delete(r.Context(), r.PathValue("entryID"), r.PathValue("id"), ownerID)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+
+	_ = httpx.NoContent(w)
+}
+
+// Report sums the time tracked by the user between the from and to dates,
+// both included and given as YYYY-MM-DD in the tz time zone (default UTC),
+// grouped by groupBy=day|project|label (default day).
+func (h *TimeEntryHandler) Report(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value(domain.KeyUserID).(string)
+	filter, err := parseTimeReportFilter(r.URL.Query(), ownerID)
+	if err != nil {
+		_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	report, err := h.timeEntryUC.Report(r.Context(), filter)
+	if err != nil {
+		writeTimeEntryError(w, err)
+		return
+	}
+
+	_ = httpx.SuccessJSON(w, http.StatusOK, httpx.JSON{
+		"groupBy": filter.GroupBy,
+		"rows":    report,
+	})
+}
+
+func parseTimeReportFilter(values url.Values, ownerID string) (*domain.TimeReportFilter, error) {
+	loc, err := parseTimeZone(values.Get("tz"))
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &domain.TimeReportFilter{
+		OwnerID:  ownerID,
+		GroupBy:  values.Get("groupBy"),
+		Location: loc,
+	}
+
+	switch filter.GroupBy {
+	case "":
+		filter.GroupBy = domain.TimeReportByDay
+	case domain.TimeReportByDay, domain.TimeReportByProject, domain.TimeReportByLabel:
+	default:
+		return nil, fmt.Errorf("invalid value for groupBy: %q", filter.GroupBy)
+	}
+
+	filter.From, filter.To, err = parseDateRange(values, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// parseDateRange reads the from and to dates, both included and given as
+// YYYY-MM-DD in loc, as a half-open range of UTC times.
+func parseDateRange(values url.Values, loc *time.Location) (time.Time, time.Time, error) {
+	from, err := time.ParseInLocation(time.DateOnly, values.Get("from"), loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid value for from: expected YYYY-MM-DD")
+	}
+	to, err := time.ParseInLocation(time.DateOnly, values.Get("to"), loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid value for to: expected YYYY-MM-DD")
+	}
+
+	return from.UTC(), to.AddDate(0, 0, 1).UTC(), nil
+}
+
+func writeTimeEntryError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorx.ErrTaskNotFound),
+		errors.Is(err, errorx.ErrTimeEntryNotFound),
+		errors.Is(err, errorx.ErrNoRunningTimer):
+		code = http.StatusNotFound
+	case errors.Is(err, errorx.ErrTimerRunning):
+		code = http.StatusConflict
+	case errors.Is(err, errorx.ErrInvalidTimeSpan),
+		errors.Is(err, errorx.ErrInvalidReportRange):
+		code = http.StatusUnprocessableEntity
+	}
+
+	_ = httpx.ErrorJSON(w, httpx.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
@@ -3,31 +3,43 @@ package v1
 import (
 	"encoding/json"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
 	"gitlab.com/jodworkspace/mvp/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// wsWriteTimeout bounds the time spent writing a message to a connection.
+const wsWriteTimeout = 10 * time.Second
+
 type DocumentSyncer interface {
 }
 
 type WSHandler struct {
 	upgrader       websocket.Upgrader
 	documentSyncer DocumentSyncer
+	hub            *WSHub
 	logger         *logger.ZapLogger
 }
 
-func NewWSHandler(documentSyncer DocumentSyncer, logger *logger.ZapLogger) *WSHandler {
+// NewWSHandler accepts connections from the allowed origins, any origin when
+// they include "*". The connections of signed in users receive the events
+// published on hub.
+func NewWSHandler(documentSyncer DocumentSyncer, hub *WSHub, allowedOrigins []string, logger *logger.ZapLogger) *WSHandler {
 	upgrader := websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool {
-			return true
+			origin := r.Header.Get("Origin")
+			return origin == "" || slices.Contains(allowedOrigins, "*") || slices.Contains(allowedOrigins, origin)
 		},
 	}
 
 	return &WSHandler{
 		upgrader:       upgrader,
 		documentSyncer: documentSyncer,
+		hub:            hub,
 		logger:         logger,
 	}
 }
@@ -50,6 +62,12 @@ func (h *WSHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 	defer conn.Close()
 
+	userID, _ := r.Context().Value(domain.KeyUserID).(string)
+	client := &wsClient{conn: conn, send: make(chan []byte, wsSendBuffer)}
+	h.hub.register(userID, client)
+	defer h.hub.unregister(userID, client)
+	go h.write(client)
+
 	for {
 		_, msgBytes, err := conn.ReadMessage()
 		if err != nil {
@@ -67,3 +85,16 @@ func (h *WSHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		h.logger.Info("ws message", zap.Any("message", msg))
 	}
 }
+
+// write sends the queued messages of a client until its queue is closed.
+func (h *WSHandler) write(client *wsClient) {
+	for msg := range client.send {
+		_ = client.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		err := client.conn.WriteMessage(websocket.TextMessage, msg)
+		if err != nil {
+			h.logger.Error("conn.WriteMessage", zap.Error(err))
+			_ = client.conn.Close()
+			return
+		}
+	}
+}
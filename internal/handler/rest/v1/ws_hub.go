@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// wsSendBuffer is the number of messages queued for a connection before new
+// ones are dropped.
+const wsSendBuffer = 64
+
+// WSMessage is the envelope of the messages pushed over the WebSocket.
+type WSMessage struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// WSHub tracks the WebSocket connections of each user so that events can be
+// pushed to all of them.
+type WSHub struct {
+	mu      sync.RWMutex
+	clients map[string]map[*wsClient]struct{}
+	logger  *logger.ZapLogger
+}
+
+// wsClient is a connection whose messages are written by a single goroutine
+// draining send.
+type wsClient struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+func NewWSHub(zl *logger.ZapLogger) *WSHub {
+	return &WSHub{
+		clients: make(map[string]map[*wsClient]struct{}),
+		logger:  zl,
+	}
+}
+
+// Publish pushes an event to the connections of userID. A connection too slow
+// to keep up misses the event.
+func (h *WSHub) Publish(userID, event string, data any) {
+	msg, err := json.Marshal(WSMessage{Type: event, Data: data})
+	if err != nil {
+		h.logger.Error("wsHub - json.Marshal", zap.String("event", event), zap.Error(err))
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients[userID] {
+		select {
+		case client.send <- msg:
+		default:
+			h.logger.Warn("wsHub - dropped message", zap.String("user_id", userID), zap.String("event", event))
+		}
+	}
+}
+
+func (h *WSHub) register(userID string, client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*wsClient]struct{})
+	}
+	h.clients[userID][client] = struct{}{}
+}
+
+// unregister removes a connection and closes its queue, ending its writer.
+func (h *WSHub) unregister(userID string, client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.clients[userID], client)
+	if len(h.clients[userID]) == 0 {
+		delete(h.clients, userID)
+	}
+	close(client.send)
+}
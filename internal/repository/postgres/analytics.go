@@ -0,0 +1,292 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+)
+
+// leadSeconds is the time in seconds from creation to completion of a task.
+const leadSeconds = "EXTRACT(EPOCH FROM " + domain.ColTaskCompletedAt + " - " + domain.ColCreatedAt + ")::float8"
+
+// completedInRange matches the tasks completed in a range, given as two
+// arguments.
+const completedInRange = domain.ColTaskCompletedAt + " >= ? AND " + domain.ColTaskCompletedAt + " < ?"
+
+// AnalyticsRepository aggregates the tasks of a user. It only reads tasks,
+// through their completed_at column kept by a trigger.
+type AnalyticsRepository struct {
+	client postgres.DB
+}
+
+func NewAnalyticsRepository(pgc postgres.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		client: pgc,
+	}
+}
+
+// Completed counts the tasks completed in each day or week of the filter,
+// including empty ones, with a running total over the range.
+func (r *AnalyticsRepository) Completed(ctx context.Context, filter *domain.AnalyticsFilter) ([]*domain.CompletionBucket, error) {
+	tz := filter.Location.String()
+	done, doneArgs, err := squirrel.
+		Select().
+		Column(squirrel.Expr("date_trunc(?, "+localTime(domain.ColTaskCompletedAt)+") AS bucket", filter.Period, tz)).
+		Column("count(*) AS n").
+		From(domain.TableTask).
+		Where(squirrel.Eq{
+			domain.ColTaskOwnerID:   filter.OwnerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		Where(completedInRange, filter.From, filter.To).
+		GroupBy("bucket").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	// The buckets run from the one holding From to the one holding the last
+	// instant before To, in local time.
+	buckets := "SELECT generate_series(" +
+		"date_trunc(?, " + localTime("?::timestamp") + "), " +
+		localTime("?::timestamp") + " - interval '1 microsecond', " +
+		"('1 ' || ?::text)::interval) AS bucket"
+	bucketsArgs := []any{filter.Period, filter.From, tz, filter.To, tz, filter.Period}
+
+	query, args, err := r.client.QueryBuilder().
+		Select(
+			"to_char(b.bucket, 'YYYY-MM-DD')",
+			"COALESCE(d.n, 0)",
+			"SUM(COALESCE(d.n, 0)) OVER (ORDER BY b.bucket)::bigint",
+		).
+		From("buckets b").
+		LeftJoin("done d ON d.bucket = b.bucket").
+		OrderBy("b.bucket ASC").
+		Prefix("WITH done AS ("+done+"), buckets AS ("+buckets+")", append(doneArgs, bucketsArgs...)...).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	completed := make([]*domain.CompletionBucket, 0)
+	for rows.Next() {
+		var bucket domain.CompletionBucket
+		err = rows.Scan(&bucket.Date, &bucket.Count, &bucket.Cumulative)
+		if err != nil {
+			return nil, err
+		}
+		completed = append(completed, &bucket)
+	}
+
+	return completed, rows.Err()
+}
+
+// LeadTime returns the lead time of the tasks completed in the range of the
+// filter, and how many of them were completed after their due date.
+func (r *AnalyticsRepository) LeadTime(ctx context.Context, filter *domain.AnalyticsFilter) (*domain.LeadTime, int64, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(
+			"count(*)",
+			"COALESCE(AVG("+leadSeconds+"), 0)",
+			"COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY "+leadSeconds+"), 0)",
+			"count(*) FILTER (WHERE "+domain.ColTaskCompletedAt+" > "+domain.ColTaskDueDate+")",
+		).
+		From(domain.TableTask).
+		Where(squirrel.Eq{
+			domain.ColTaskOwnerID:   filter.OwnerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		Where(completedInRange, filter.From, filter.To).
+		ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		lead domain.LeadTime
+		late int64
+	)
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&lead.Tasks, &lead.AverageSeconds, &lead.MedianSeconds, &late)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &lead, late, nil
+}
+
+// Priorities returns the open tasks of ownerID by priority, highest first,
+// with their share of all open tasks, and how many of them are overdue at now.
+func (r *AnalyticsRepository) Priorities(ctx context.Context, ownerID string, now time.Time) ([]*domain.PriorityShare, int64, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(
+			"COALESCE("+domain.ColTaskPriority+", 0) AS priority",
+			"count(*)",
+			"count(*)::float8 / SUM(count(*)) OVER ()",
+		).
+		Column(squirrel.Expr("count(*) FILTER (WHERE "+domain.ColTaskDueDate+" < ?)", now)).
+		From(domain.TableTask).
+		Where(openTask(ownerID)).
+		GroupBy("1").
+		OrderBy("1 DESC").
+		ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		priorities = make([]*domain.PriorityShare, 0)
+		overdue    int64
+	)
+	for rows.Next() {
+		var (
+			share domain.PriorityShare
+			late  int64
+		)
+		err = rows.Scan(&share.Priority, &share.Count, &share.Share, &late)
+		if err != nil {
+			return nil, 0, err
+		}
+		priorities = append(priorities, &share)
+		overdue += late
+	}
+
+	return priorities, overdue, rows.Err()
+}
+
+// Streaks returns the runs of local days on which ownerID completed tasks,
+// today being the given local date.
+func (r *AnalyticsRepository) Streaks(ctx context.Context, ownerID string, today time.Time, loc *time.Location) (*domain.Streaks, error) {
+	// Consecutive days minus their rank are the same date, which identifies
+	// the run they belong to.
+	days, daysArgs, err := squirrel.
+		Select().
+		Distinct().
+		Column(squirrel.Expr(localTime(domain.ColTaskCompletedAt)+"::date AS day", loc.String())).
+		From(domain.TableTask).
+		Where(squirrel.Eq{
+			domain.ColTaskOwnerID:   ownerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		Where(squirrel.NotEq{domain.ColTaskCompletedAt: nil}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	runs := "SELECT max(day) AS last_day, count(*) AS length FROM (" +
+		"SELECT day, day - (ROW_NUMBER() OVER (ORDER BY day))::int AS run FROM days" +
+		") d GROUP BY run"
+
+	query, args, err := r.client.QueryBuilder().
+		Select("COALESCE(max(length), 0)").
+		Column(squirrel.Expr("COALESCE(max(length) FILTER (WHERE last_day >= ?::date - 1), 0)", today.Format(time.DateOnly))).
+		From("runs").
+		Prefix("WITH days AS ("+days+"), runs AS ("+runs+")", daysArgs...).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var streaks domain.Streaks
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&streaks.Longest, &streaks.Current)
+	if err != nil {
+		return nil, err
+	}
+
+	return &streaks, nil
+}
+
+// Burndown returns the days of the burndown of a project. Tasks created
+// before the range count towards the remaining ones.
+func (r *AnalyticsRepository) Burndown(ctx context.Context, filter *domain.BurndownFilter) ([]*domain.BurndownPoint, error) {
+	tz := filter.Location.String()
+	perDay := func(col string) (string, []any, error) {
+		return squirrel.
+			Select().
+			Column(squirrel.Expr(localTime(col)+"::date AS day", tz)).
+			Column("count(*) AS n").
+			From(domain.TableTask).
+			Where(squirrel.Eq{
+				domain.ColTaskOwnerID:   filter.OwnerID,
+				domain.ColTaskProjectID: filter.ProjectID,
+				domain.ColTaskDeletedAt: nil,
+			}).
+			Where(squirrel.NotEq{col: nil}).
+			GroupBy("day").
+			ToSql()
+	}
+
+	created, createdArgs, err := perDay(domain.ColCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	completed, completedArgs, err := perDay(domain.ColTaskCompletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The running sum starts with the first task of the project so that the
+	// days of the range carry what was left open before it.
+	from := localTime("?::timestamp") + "::date"
+	days := "SELECT generate_series(LEAST(" + from + ", (SELECT min(day) FROM created)), " +
+		"(" + localTime("?::timestamp") + " - interval '1 microsecond')::date, interval '1 day')::date AS day"
+	burndown := "SELECT d.day, COALESCE(c.n, 0) AS created, COALESCE(x.n, 0) AS completed, " +
+		"SUM(COALESCE(c.n, 0) - COALESCE(x.n, 0)) OVER (ORDER BY d.day)::bigint AS remaining " +
+		"FROM days d LEFT JOIN created c ON c.day = d.day LEFT JOIN completed x ON x.day = d.day"
+
+	prefixArgs := append(createdArgs, completedArgs...)
+	prefixArgs = append(prefixArgs, filter.From, tz, filter.To, tz)
+
+	query, args, err := r.client.QueryBuilder().
+		Select("to_char(day, 'YYYY-MM-DD')", "created", "completed", "remaining").
+		From("burndown").
+		Where(squirrel.Expr("day >= "+from, filter.From, tz)).
+		OrderBy("day ASC").
+		Prefix(
+			"WITH created AS ("+created+"), completed AS ("+completed+"), days AS ("+days+"), burndown AS ("+burndown+")",
+			prefixArgs...,
+		).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := make([]*domain.BurndownPoint, 0)
+	for rows.Next() {
+		var point domain.BurndownPoint
+		err = rows.Scan(&point.Date, &point.Created, &point.Completed, &point.Remaining)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, &point)
+	}
+
+	return points, rows.Err()
+}
+
+// localTime converts expr, a UTC timestamp, to the local time of the zone
+// given as the next argument.
+func localTime(expr string) string {
+	return "((" + expr + " AT TIME ZONE 'UTC') AT TIME ZONE ?)"
+}
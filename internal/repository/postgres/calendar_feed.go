@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+)
+
+type CalendarFeedRepository struct {
+	client postgres.DB
+}
+
+func NewCalendarFeedRepository(pgc postgres.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{
+		client: pgc,
+	}
+}
+
+func (r *CalendarFeedRepository) Create(ctx context.Context, feed *domain.CalendarFeed) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableCalendarFeeds).
+		Columns(domain.CalendarFeedAllColumns...).
+		Values(
+			feed.ID,
+			feed.OwnerID,
+			feed.CreatedAt,
+			feed.ExpiresAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// Active reports whether the feed of ownerID exists and has not expired at now.
+func (r *CalendarFeedRepository) Active(ctx context.Context, id, ownerID string, now time.Time) (bool, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("1").
+		Prefix("SELECT EXISTS(").
+		From(domain.TableCalendarFeeds).
+		Where(squirrel.Eq{
+			domain.ColID:                  id,
+			domain.ColCalendarFeedOwnerID: ownerID,
+		}).
+		Where(squirrel.Gt{domain.ColCalendarExpiresAt: now}).
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var active bool
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&active)
+	if err != nil {
+		return false, err
+	}
+
+	return active, nil
+}
+
+// DeleteByOwner revokes every feed of ownerID.
+func (r *CalendarFeedRepository) DeleteByOwner(ctx context.Context, ownerID string) error {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableCalendarFeeds).
+		Where(squirrel.Eq{domain.ColCalendarFeedOwnerID: ownerID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
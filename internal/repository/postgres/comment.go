@@ -0,0 +1,285 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+)
+
+type CommentRepository struct {
+	client postgres.DB
+}
+
+func NewCommentRepository(pgc postgres.DB) *CommentRepository {
+	return &CommentRepository{
+		client: pgc,
+	}
+}
+
+// ListThreads returns a page of the threads of a task, oldest first, as the
+// comments of each thread in the order they were written.
+func (r *CommentRepository) ListThreads(ctx context.Context, taskID string, page, pageSize uint64) ([]*domain.Comment, error) {
+	roots := squirrel.
+		Select(domain.ColID).
+		From(domain.TableComments).
+		Where(squirrel.Eq{
+			domain.ColCommentTaskID:   taskID,
+			domain.ColCommentParentID: nil,
+		}).
+		OrderBy(domain.ColCreatedAt+" ASC", domain.ColID+" ASC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize)
+
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.CommentAllColumns...).
+		From(domain.TableComments).
+		Where(squirrel.Expr(domain.ColCommentThreadID+" IN (?)", roots)).
+		OrderBy(domain.ColCreatedAt+" ASC", domain.ColID+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]*domain.Comment, 0)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// CountThreads counts the top-level comments of a task.
+func (r *CommentRepository) CountThreads(ctx context.Context, taskID string) (int64, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("count(*)").
+		From(domain.TableComments).
+		Where(squirrel.Eq{
+			domain.ColCommentTaskID:   taskID,
+			domain.ColCommentParentID: nil,
+		}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountByTasks counts the comments of each of the given tasks. Tasks without
+// comments are left out.
+func (r *CommentRepository) CountByTasks(ctx context.Context, taskIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(taskIDs) == 0 {
+		return counts, nil
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.ColCommentTaskID, "count(*)").
+		From(domain.TableComments).
+		Where(squirrel.Eq{domain.ColCommentTaskID: taskIDs}).
+		GroupBy(domain.ColCommentTaskID).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID string
+			count  int
+		)
+		err = rows.Scan(&taskID, &count)
+		if err != nil {
+			return nil, err
+		}
+		counts[taskID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *CommentRepository) Get(ctx context.Context, id, taskID string) (*domain.Comment, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.CommentAllColumns...).
+		From(domain.TableComments).
+		Where(squirrel.Eq{
+			domain.ColID:            id,
+			domain.ColCommentTaskID: taskID,
+		}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanComment(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+func (r *CommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableComments).
+		Columns(domain.CommentAllColumns...).
+		Values(
+			comment.ID,
+			comment.TaskID,
+			comment.ThreadID,
+			comment.ParentID,
+			comment.AuthorID,
+			comment.Body,
+			comment.CreatedAt,
+			comment.UpdatedAt,
+			comment.EditedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// UpdateBody replaces the body of a comment and marks it as edited.
+func (r *CommentRepository) UpdateBody(ctx context.Context, comment *domain.Comment) (*domain.Comment, error) {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableComments).
+		Set(domain.ColCommentBody, comment.Body).
+		Set(domain.ColUpdatedAt, comment.UpdatedAt).
+		Set(domain.ColCommentEditedAt, comment.UpdatedAt).
+		Where(squirrel.Eq{
+			domain.ColID:            comment.ID,
+			domain.ColCommentTaskID: comment.TaskID,
+		}).
+		Suffix("RETURNING " + joinColumns(domain.CommentAllColumns)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanComment(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// Delete removes a comment along with its replies and revisions.
+func (r *CommentRepository) Delete(ctx context.Context, id, taskID string) error {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableComments).
+		Where(squirrel.Eq{
+			domain.ColID:            id,
+			domain.ColCommentTaskID: taskID,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *CommentRepository) CreateRevision(ctx context.Context, revision *domain.CommentRevision) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableCommentRevisions).
+		Columns(domain.CommentRevisionAllColumns...).
+		Values(
+			revision.ID,
+			revision.CommentID,
+			revision.Body,
+			revision.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// ListRevisions returns the former bodies of a comment, newest first.
+func (r *CommentRepository) ListRevisions(ctx context.Context, commentID string) ([]*domain.CommentRevision, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.CommentRevisionAllColumns...).
+		From(domain.TableCommentRevisions).
+		Where(squirrel.Eq{domain.ColCommentRevisionComment: commentID}).
+		OrderBy(domain.ColCreatedAt+" DESC", domain.ColID+" DESC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]*domain.CommentRevision, 0)
+	for rows.Next() {
+		var revision domain.CommentRevision
+		err = rows.Scan(
+			&revision.ID,
+			&revision.CommentID,
+			&revision.Body,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// scanComment reads a row selected with domain.CommentAllColumns.
+func scanComment(row pgx.Row) (*domain.Comment, error) {
+	var comment domain.Comment
+	err := row.Scan(
+		&comment.ID,
+		&comment.TaskID,
+		&comment.ThreadID,
+		&comment.ParentID,
+		&comment.AuthorID,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.EditedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
@@ -2,11 +2,23 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
 )
 
+// SQLSTATEs of the constraint violations mapped to domain errors.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// likeEscaper escapes the LIKE wildcards in user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func exists(db postgres.DB, ctx context.Context, table string, col string, val any) (bool, error) {
 	query, args, err := db.QueryBuilder().
 		Select("1").
@@ -27,3 +39,37 @@ func exists(db postgres.DB, ctx context.Context, table string, col string, val a
 
 	return found, nil
 }
+
+func joinColumns(cols []string) string {
+	return strings.Join(cols, ", ")
+}
+
+// qualifyColumns prefixes every column with a table name or alias.
+func qualifyColumns(table string, cols []string) []string {
+	qualified := make([]string, len(cols))
+	for i, col := range cols {
+		qualified[i] = table + "." + col
+	}
+
+	return qualified
+}
+
+// uniqueViolation replaces a unique constraint violation with target.
+func uniqueViolation(err error, target error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return target
+	}
+
+	return err
+}
+
+// foreignKeyViolation replaces a foreign key constraint violation with target.
+func foreignKeyViolation(err error, target error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+		return target
+	}
+
+	return err
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+)
+
+type FocusSessionRepository struct {
+	client postgres.DB
+}
+
+func NewFocusSessionRepository(pgc postgres.DB) *FocusSessionRepository {
+	return &FocusSessionRepository{
+		client: pgc,
+	}
+}
+
+func (r *FocusSessionRepository) Create(ctx context.Context, session *domain.FocusSession) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableFocusSessions).
+		Columns(domain.FocusSessionAllColumns...).
+		Values(
+			session.ID,
+			session.TaskID,
+			session.OwnerID,
+			session.WorkSeconds,
+			session.BreakSeconds,
+			session.FocusedSeconds,
+			session.Interruptions,
+			session.Completed,
+			session.StartedAt,
+			session.EndedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// Stats sums up the sessions selected by filter per period and task, by
+// period and then most focused task first. Sessions count in the period
+// they started in.
+func (r *FocusSessionRepository) Stats(ctx context.Context, filter *domain.FocusStatsFilter) ([]*domain.FocusStats, error) {
+	conds := squirrel.And{
+		squirrel.Eq{"s." + domain.ColFocusOwnerID: filter.OwnerID},
+		squirrel.GtOrEq{"s." + domain.ColFocusStartedAt: filter.From},
+		squirrel.Lt{"s." + domain.ColFocusStartedAt: filter.To},
+	}
+	if filter.TaskID != nil {
+		conds = append(conds, squirrel.Eq{"s." + domain.ColFocusTaskID: *filter.TaskID})
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select().
+		Column(squirrel.Expr(
+			"to_char(date_trunc(?, (s."+domain.ColFocusStartedAt+" AT TIME ZONE 'UTC') AT TIME ZONE ?), 'YYYY-MM-DD')",
+			filter.Period, filter.Location.String(),
+		)).
+		Columns(
+			"s."+domain.ColFocusTaskID,
+			"t."+domain.ColTaskTitle,
+			"count(*)",
+			"count(*) FILTER (WHERE s."+domain.ColFocusCompleted+")",
+			"SUM(s."+domain.ColFocusFocusedSeconds+")",
+			"SUM(s."+domain.ColFocusInterruptions+")",
+		).
+		From(domain.TableFocusSessions+" s").
+		Join(domain.TableTask+" t ON t."+domain.ColID+" = s."+domain.ColFocusTaskID).
+		Where(conds).
+		GroupBy("1", "s."+domain.ColFocusTaskID, "t."+domain.ColTaskTitle).
+		OrderBy("1 ASC", "6 DESC", "s."+domain.ColFocusTaskID+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*domain.FocusStats, 0)
+	for rows.Next() {
+		var s domain.FocusStats
+		err = rows.Scan(
+			&s.Period,
+			&s.TaskID,
+			&s.TaskTitle,
+			&s.Sessions,
+			&s.Completed,
+			&s.FocusedSeconds,
+			&s.Interruptions,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, &s)
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+)
+
+type LabelRepository struct {
+	client postgres.DB
+}
+
+func NewLabelRepository(pgc postgres.DB) *LabelRepository {
+	return &LabelRepository{
+		client: pgc,
+	}
+}
+
+func (r *LabelRepository) List(ctx context.Context, ownerID string) ([]*domain.Label, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.LabelAllColumns...).
+		From(domain.TableLabels).
+		Where(squirrel.Eq{domain.ColLabelOwnerID: ownerID}).
+		OrderBy(domain.ColLabelName + " ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := make([]*domain.Label, 0)
+	for rows.Next() {
+		label, err := scanLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+
+	return labels, rows.Err()
+}
+
+func (r *LabelRepository) Get(ctx context.Context, id, ownerID string) (*domain.Label, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.LabelAllColumns...).
+		From(domain.TableLabels).
+		Where(squirrel.Eq{
+			domain.ColID:           id,
+			domain.ColLabelOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanLabel(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// Create inserts a label, returning errorx.ErrLabelExists when the owner
+// already has a label with the same name.
+func (r *LabelRepository) Create(ctx context.Context, label *domain.Label) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableLabels).
+		Columns(domain.LabelAllColumns...).
+		Values(
+			label.ID,
+			label.Name,
+			label.Color,
+			label.OwnerID,
+			label.CreatedAt,
+			label.UpdatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return uniqueViolation(err, errorx.ErrLabelExists)
+}
+
+// Update renames or recolors a label. pgx.ErrNoRows is returned when the
+// owner has no such label.
+func (r *LabelRepository) Update(ctx context.Context, label *domain.Label) (*domain.Label, error) {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableLabels).
+		Set(domain.ColLabelName, label.Name).
+		Set(domain.ColLabelColor, label.Color).
+		Set(domain.ColUpdatedAt, label.UpdatedAt).
+		Where(squirrel.Eq{
+			domain.ColID:           label.ID,
+			domain.ColLabelOwnerID: label.OwnerID,
+		}).
+		Suffix("RETURNING " + joinColumns(domain.LabelAllColumns)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := scanLabel(r.client.Pool().QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, uniqueViolation(err, errorx.ErrLabelExists)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a label and detaches it from every task.
+func (r *LabelRepository) Delete(ctx context.Context, id, ownerID string) error {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableLabels).
+		Where(squirrel.Eq{
+			domain.ColID:           id,
+			domain.ColLabelOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// CountOwned counts how many of the given labels belong to ownerID.
+func (r *LabelRepository) CountOwned(ctx context.Context, ids []string, ownerID string) (int, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("count(*)").
+		From(domain.TableLabels).
+		Where(squirrel.Eq{
+			domain.ColID:           ids,
+			domain.ColLabelOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// SetTaskLabels replaces the labels of a task in a single statement. Labels
+// not owned by ownerID are ignored.
+func (r *LabelRepository) SetTaskLabels(ctx context.Context, taskID, ownerID string, labelIDs []string) error {
+	if labelIDs == nil {
+		labelIDs = []string{}
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableTaskLabels).
+		Prefix(
+			"WITH detached AS (DELETE FROM "+domain.TableTaskLabels+
+				" WHERE "+domain.ColTaskID+" = ? AND NOT ("+domain.ColLabelID+" = ANY(?::uuid[])))",
+			taskID, labelIDs,
+		).
+		Columns(domain.ColTaskID, domain.ColLabelID).
+		Select(r.client.QueryBuilder().
+			Select().
+			Column(squirrel.Expr("?::uuid", taskID)).
+			Column(domain.ColID).
+			From(domain.TableLabels).
+			Where(squirrel.Eq{
+				domain.ColID:           labelIDs,
+				domain.ColLabelOwnerID: ownerID,
+			}),
+		).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// AttachTaskLabels adds labels to many tasks with a single statement, keyed by
+// task id. The labels are expected to belong to the owner of the tasks.
+func (r *LabelRepository) AttachTaskLabels(ctx context.Context, taskLabels map[string][]string) error {
+	builder := r.client.QueryBuilder().
+		Insert(domain.TableTaskLabels).
+		Columns(domain.ColTaskID, domain.ColLabelID).
+		Suffix("ON CONFLICT DO NOTHING")
+
+	var pairs int
+	for taskID, labelIDs := range taskLabels {
+		for _, labelID := range labelIDs {
+			builder = builder.Values(taskID, labelID)
+			pairs++
+		}
+	}
+
+	if pairs == 0 {
+		return nil
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// ListByTasks loads the labels of many tasks in one query, keyed by task id.
+func (r *LabelRepository) ListByTasks(ctx context.Context, taskIDs []string) (map[string][]*domain.Label, error) {
+	labels := make(map[string][]*domain.Label)
+	if len(taskIDs) == 0 {
+		return labels, nil
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select("tl." + domain.ColTaskID).
+		Columns(qualifyColumns("l", domain.LabelAllColumns)...).
+		From(domain.TableTaskLabels + " tl").
+		Join(domain.TableLabels + " l ON l." + domain.ColID + " = tl." + domain.ColLabelID).
+		Where(squirrel.Eq{"tl." + domain.ColTaskID: taskIDs}).
+		OrderBy("l." + domain.ColLabelName + " ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		label, err := scanLabel(rows, &taskID)
+		if err != nil {
+			return nil, err
+		}
+		labels[taskID] = append(labels[taskID], label)
+	}
+
+	return labels, rows.Err()
+}
+
+// scanLabel reads a row selected with domain.LabelAllColumns, preceded by any
+// leading columns scanned into prefix.
+func scanLabel(row pgx.Row, prefix ...any) (*domain.Label, error) {
+	var label domain.Label
+	err := row.Scan(append(prefix,
+		&label.ID,
+		&label.Name,
+		&label.Color,
+		&label.OwnerID,
+		&label.CreatedAt,
+		&label.UpdatedAt,
+	)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &label, nil
+}
@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+)
+
+type ProjectRepository struct {
+	client postgres.DB
+}
+
+func NewProjectRepository(pgc postgres.DB) *ProjectRepository {
+	return &ProjectRepository{
+		client: pgc,
+	}
+}
+
+// List returns the projects of ownerID with their task counts in sort order.
+// Archived projects are only included when includeArchived is set.
+func (r *ProjectRepository) List(ctx context.Context, ownerID string, includeArchived bool) ([]*domain.Project, error) {
+	builder := r.selectWithCounts(ownerID)
+	if !includeArchived {
+		builder = builder.Where(squirrel.Eq{"p." + domain.ColProjectIsArchived: false})
+	}
+
+	query, args, err := builder.
+		OrderBy(
+			"p."+domain.ColProjectSortOrder+" ASC",
+			"p."+domain.ColCreatedAt+" ASC",
+			"p."+domain.ColID+" ASC",
+		).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := make([]*domain.Project, 0)
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+// Get returns a project of ownerID with its task counts, or pgx.ErrNoRows.
+func (r *ProjectRepository) Get(ctx context.Context, id, ownerID string) (*domain.Project, error) {
+	query, args, err := r.selectWithCounts(ownerID).
+		Where(squirrel.Eq{"p." + domain.ColID: id}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanProject(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// Exists reports whether ownerID has a project with the given id.
+func (r *ProjectRepository) Exists(ctx context.Context, id, ownerID string) (bool, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("1").
+		Prefix("SELECT EXISTS(").
+		From(domain.TableProjects).
+		Where(squirrel.Eq{
+			domain.ColID:             id,
+			domain.ColProjectOwnerID: ownerID,
+		}).
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var found bool
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&found)
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+func (r *ProjectRepository) Create(ctx context.Context, project *domain.Project) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableProjects).
+		Columns(domain.ProjectAllColumns...).
+		Values(
+			project.ID,
+			project.Name,
+			project.Description,
+			project.Color,
+			project.IsArchived,
+			project.SortOrder,
+			project.OwnerID,
+			project.CreatedAt,
+			project.UpdatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// Update replaces the mutable columns of a project owned by project.OwnerID.
+// pgx.ErrNoRows is returned when no such project exists.
+func (r *ProjectRepository) Update(ctx context.Context, project *domain.Project) error {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableProjects).
+		Set(domain.ColProjectName, project.Name).
+		Set(domain.ColProjectDescription, project.Description).
+		Set(domain.ColProjectColor, project.Color).
+		Set(domain.ColProjectIsArchived, project.IsArchived).
+		Set(domain.ColProjectSortOrder, project.SortOrder).
+		Set(domain.ColUpdatedAt, project.UpdatedAt).
+		Where(squirrel.Eq{
+			domain.ColID:             project.ID,
+			domain.ColProjectOwnerID: project.OwnerID,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Delete removes a project. Its tasks are kept and moved out of the project.
+func (r *ProjectRepository) Delete(ctx context.Context, id, ownerID string) error {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableProjects).
+		Where(squirrel.Eq{
+			domain.ColID:             id,
+			domain.ColProjectOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// selectWithCounts selects the projects of ownerID joined with the number of
+// tasks, and of open tasks, in each of them.
+func (r *ProjectRepository) selectWithCounts(ownerID string) squirrel.SelectBuilder {
+	return r.client.QueryBuilder().
+		Select(qualifyColumns("p", domain.ProjectAllColumns)...).
+		Column("coalesce(c.total, 0)").
+		Column("coalesce(c.open, 0)").
+		From(domain.TableProjects+" p").
+		LeftJoin(
+			"(SELECT "+domain.ColTaskProjectID+", count(*) AS total, "+
+				"count(*) FILTER (WHERE NOT "+domain.ColTaskIsCompleted+") AS open"+
+				" FROM "+domain.TableTask+
+				" WHERE "+domain.ColTaskOwnerID+" = ? AND "+domain.ColTaskProjectID+" IS NOT NULL"+
+				" AND "+domain.ColTaskDeletedAt+" IS NULL"+
+				" GROUP BY "+domain.ColTaskProjectID+") c ON c."+domain.ColTaskProjectID+" = p."+domain.ColID,
+			ownerID,
+		).
+		Where(squirrel.Eq{"p." + domain.ColProjectOwnerID: ownerID})
+}
+
+// scanProject reads a row selected by selectWithCounts.
+func scanProject(row pgx.Row) (*domain.Project, error) {
+	var project domain.Project
+	err := row.Scan(
+		&project.ID,
+		&project.Name,
+		&project.Description,
+		&project.Color,
+		&project.IsArchived,
+		&project.SortOrder,
+		&project.OwnerID,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+		&project.TaskCount,
+		&project.OpenTaskCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+)
+
+// ListStatuses returns the statuses of a project of ownerID in board order.
+func (r *ProjectRepository) ListStatuses(ctx context.Context, projectID, ownerID string) ([]*domain.ProjectStatus, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.ProjectStatusAllColumns...).
+		From(domain.TableProjectStatuses).
+		Where(squirrel.Eq{
+			domain.ColStatusProjectID: projectID,
+			domain.ColStatusOwnerID:   ownerID,
+		}).
+		OrderBy(domain.ColStatusPosition+" ASC", domain.ColID+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := make([]*domain.ProjectStatus, 0)
+	for rows.Next() {
+		status, err := scanProjectStatus(rows)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, rows.Err()
+}
+
+func (r *ProjectRepository) GetStatus(ctx context.Context, id, projectID, ownerID string) (*domain.ProjectStatus, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.ProjectStatusAllColumns...).
+		From(domain.TableProjectStatuses).
+		Where(squirrel.Eq{
+			domain.ColID:              id,
+			domain.ColStatusProjectID: projectID,
+			domain.ColStatusOwnerID:   ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanProjectStatus(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+func (r *ProjectRepository) CreateStatus(ctx context.Context, status *domain.ProjectStatus) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableProjectStatuses).
+		Columns(domain.ProjectStatusAllColumns...).
+		Values(
+			status.ID,
+			status.ProjectID,
+			status.OwnerID,
+			status.Name,
+			status.Color,
+			status.IsDone,
+			status.Position,
+			status.CreatedAt,
+			status.UpdatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return uniqueViolation(err, errorx.ErrStatusExists)
+}
+
+func (r *ProjectRepository) UpdateStatus(ctx context.Context, status *domain.ProjectStatus) (*domain.ProjectStatus, error) {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableProjectStatuses).
+		Set(domain.ColStatusName, status.Name).
+		Set(domain.ColStatusColor, status.Color).
+		Set(domain.ColStatusIsDone, status.IsDone).
+		Set(domain.ColStatusPosition, status.Position).
+		Set(domain.ColUpdatedAt, status.UpdatedAt).
+		Where(squirrel.Eq{
+			domain.ColID:              status.ID,
+			domain.ColStatusProjectID: status.ProjectID,
+			domain.ColStatusOwnerID:   status.OwnerID,
+		}).
+		Suffix("RETURNING " + joinColumns(domain.ProjectStatusAllColumns)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := scanProjectStatus(r.client.Pool().QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, uniqueViolation(err, errorx.ErrStatusExists)
+	}
+
+	return updated, nil
+}
+
+// DeleteStatus removes a status. Its tasks are left without status.
+func (r *ProjectRepository) DeleteStatus(ctx context.Context, id, projectID, ownerID string) error {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableProjectStatuses).
+		Where(squirrel.Eq{
+			domain.ColID:              id,
+			domain.ColStatusProjectID: projectID,
+			domain.ColStatusOwnerID:   ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// scanProjectStatus reads a row selected with domain.ProjectStatusAllColumns.
+func scanProjectStatus(row pgx.Row) (*domain.ProjectStatus, error) {
+	var status domain.ProjectStatus
+	err := row.Scan(
+		&status.ID,
+		&status.ProjectID,
+		&status.OwnerID,
+		&status.Name,
+		&status.Color,
+		&status.IsDone,
+		&status.Position,
+		&status.CreatedAt,
+		&status.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
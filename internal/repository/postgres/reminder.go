@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+)
+
+// reminderFireAt is when a reminder r of task t is due, NULL for an offset
+// reminder of a task without a due date.
+const reminderFireAt = "COALESCE(r." + domain.ColReminderRemindAt + ", t." + domain.ColTaskDueDate +
+	" - r." + domain.ColReminderOffsetMinutes + " * INTERVAL '1 minute')"
+
+type ReminderRepository struct {
+	client postgres.DB
+}
+
+func NewReminderRepository(pgc postgres.DB) *ReminderRepository {
+	return &ReminderRepository{
+		client: pgc,
+	}
+}
+
+// List returns the reminders of a task of ownerID, oldest first.
+func (r *ReminderRepository) List(ctx context.Context, taskID, ownerID string) ([]*domain.Reminder, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.ReminderAllColumns...).
+		From(domain.TableReminders).
+		Where(squirrel.Eq{
+			domain.ColReminderTaskID:  taskID,
+			domain.ColReminderOwnerID: ownerID,
+		}).
+		OrderBy(domain.ColCreatedAt+" ASC", domain.ColID+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := make([]*domain.Reminder, 0)
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+func (r *ReminderRepository) Get(ctx context.Context, id, taskID, ownerID string) (*domain.Reminder, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.ReminderAllColumns...).
+		From(domain.TableReminders).
+		Where(squirrel.Eq{
+			domain.ColID:              id,
+			domain.ColReminderTaskID:  taskID,
+			domain.ColReminderOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReminder(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// Create inserts a reminder. errorx.ErrTaskNotFound is returned when its task
+// no longer exists.
+func (r *ReminderRepository) Create(ctx context.Context, reminder *domain.Reminder) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableReminders).
+		Columns(domain.ReminderAllColumns...).
+		Values(
+			reminder.ID,
+			reminder.TaskID,
+			reminder.OwnerID,
+			reminder.RemindAt,
+			reminder.OffsetMinutes,
+			reminder.Channels,
+			reminder.WebhookURL,
+			reminder.Status,
+			reminder.Attempts,
+			reminder.NextAttemptAt,
+			reminder.SentAt,
+			reminder.CreatedAt,
+			reminder.UpdatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return foreignKeyViolation(err, errorx.ErrTaskNotFound)
+}
+
+func (r *ReminderRepository) Delete(ctx context.Context, id, taskID, ownerID string) error {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableReminders).
+		Where(squirrel.Eq{
+			domain.ColID:              id,
+			domain.ColReminderTaskID:  taskID,
+			domain.ColReminderOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// ClaimDue locks the pending reminder that has been due the longest at now,
+// skipping the reminders locked by other instances, so it must run in a
+// transaction that lasts until the reminder has been delivered. Reminders of
+// completed or trashed tasks are left alone. pgx.ErrNoRows is returned when
+// nothing is due.
+func (r *ReminderRepository) ClaimDue(ctx context.Context, now time.Time) (*domain.Reminder, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(qualifyColumns("r", domain.ReminderAllColumns)...).
+		From(domain.TableReminders + " r").
+		Join(domain.TableTask + " t ON t." + domain.ColID + " = r." + domain.ColReminderTaskID).
+		Where(squirrel.Eq{
+			"r." + domain.ColReminderStatus:  domain.ReminderStatusPending,
+			"t." + domain.ColTaskIsCompleted: false,
+			"t." + domain.ColTaskDeletedAt:   nil,
+		}).
+		Where(squirrel.Expr(reminderFireAt+" <= ?", now)).
+		Where(squirrel.Or{
+			squirrel.Eq{"r." + domain.ColReminderNextAttemptAt: nil},
+			squirrel.LtOrEq{"r." + domain.ColReminderNextAttemptAt: now},
+		}).
+		OrderBy(reminderFireAt + " ASC").
+		Limit(1).
+		Suffix("FOR UPDATE OF r SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReminder(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// UpdateDelivery saves the delivery state of a reminder.
+func (r *ReminderRepository) UpdateDelivery(ctx context.Context, reminder *domain.Reminder) error {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableReminders).
+		Set(domain.ColReminderStatus, reminder.Status).
+		Set(domain.ColReminderAttempts, reminder.Attempts).
+		Set(domain.ColReminderNextAttemptAt, reminder.NextAttemptAt).
+		Set(domain.ColReminderSentAt, reminder.SentAt).
+		Set(domain.ColUpdatedAt, reminder.UpdatedAt).
+		Where(squirrel.Eq{domain.ColID: reminder.ID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+func (r *ReminderRepository) CreateDelivery(ctx context.Context, delivery *domain.ReminderDelivery) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableReminderDeliveries).
+		Columns(domain.ReminderDeliveryAllColumns...).
+		Values(
+			delivery.ID,
+			delivery.ReminderID,
+			delivery.Channel,
+			delivery.Attempt,
+			delivery.Status,
+			delivery.Error,
+			delivery.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// ListDeliveries returns the delivery log of a reminder, oldest first.
+func (r *ReminderRepository) ListDeliveries(ctx context.Context, reminderID string) ([]*domain.ReminderDelivery, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.ReminderDeliveryAllColumns...).
+		From(domain.TableReminderDeliveries).
+		Where(squirrel.Eq{domain.ColDeliveryReminderID: reminderID}).
+		OrderBy(domain.ColCreatedAt+" ASC", domain.ColDeliveryChannel+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*domain.ReminderDelivery, 0)
+	for rows.Next() {
+		var delivery domain.ReminderDelivery
+		err = rows.Scan(
+			&delivery.ID,
+			&delivery.ReminderID,
+			&delivery.Channel,
+			&delivery.Attempt,
+			&delivery.Status,
+			&delivery.Error,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// SentChannels returns the channels a reminder has been delivered through.
+func (r *ReminderRepository) SentChannels(ctx context.Context, reminderID string) ([]string, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("DISTINCT " + domain.ColDeliveryChannel).
+		From(domain.TableReminderDeliveries).
+		Where(squirrel.Eq{
+			domain.ColDeliveryReminderID: reminderID,
+			domain.ColDeliveryStatus:     domain.ReminderStatusSent,
+		}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func scanReminder(row pgx.Row) (*domain.Reminder, error) {
+	var reminder domain.Reminder
+	err := row.Scan(
+		&reminder.ID,
+		&reminder.TaskID,
+		&reminder.OwnerID,
+		&reminder.RemindAt,
+		&reminder.OffsetMinutes,
+		&reminder.Channels,
+		&reminder.WebhookURL,
+		&reminder.Status,
+		&reminder.Attempts,
+		&reminder.NextAttemptAt,
+		&reminder.SentAt,
+		&reminder.CreatedAt,
+		&reminder.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reminder, nil
+}
@@ -3,8 +3,12 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
 	"gitlab.com/jodworkspace/mvp/internal/domain"
 	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
 )
@@ -19,11 +23,11 @@ func NewTaskRepository(pgc postgres.DB) *TaskRepository {
 	}
 }
 
-func (r *TaskRepository) Count(ctx context.Context, ownerID string) (int64, error) {
+func (r *TaskRepository) Count(ctx context.Context, filter *domain.TaskFilter) (int64, error) {
 	query, args, err := r.client.QueryBuilder().
 		Select("count(*)").
 		From(domain.TableTask).
-		Where(squirrel.Eq{domain.ColTaskOwnerID: ownerID}).
+		Where(taskConditions(filter)).
 		ToSql()
 	if err != nil {
 		return 0, err
@@ -38,14 +42,79 @@ func (r *TaskRepository) Count(ctx context.Context, ownerID string) (int64, erro
 	return count, nil
 }
 
-func (r *TaskRepository) List(ctx context.Context, page, pageSize uint64, ownerID string) ([]*domain.Task, error) {
+func (r *TaskRepository) List(ctx context.Context, page, pageSize uint64, filter *domain.TaskFilter) ([]*domain.Task, error) {
 	query, args, err := r.client.QueryBuilder().
 		Select(domain.TaskAllColumns...).
 		From(domain.TableTask).
+		Where(taskConditions(filter)).
+		OrderBy(taskOrderBy(filter.Sort, false)...).
 		Limit(pageSize).
 		Offset((page - 1) * pageSize).
-		Where(squirrel.Eq{domain.ColTaskOwnerID: ownerID}).
-		OrderBy(fmt.Sprintf("%s DESC", domain.ColCreatedAt)).ToSql()
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryTasks(ctx, query, args...)
+}
+
+// ListAfter returns up to limit tasks following the cursor in the filter's sort
+// order, or preceding it when the cursor is backward. Tasks are always returned
+// in display order.
+func (r *TaskRepository) ListAfter(ctx context.Context, cursor *domain.TaskCursor, limit uint64, filter *domain.TaskFilter) ([]*domain.Task, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskAllColumns...).
+		From(domain.TableTask).
+		Where(taskConditions(filter)).
+		Where(keysetCondition(filter.Sort, cursor)).
+		OrderBy(taskOrderBy(filter.Sort, cursor.Backward)...).
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := r.queryTasks(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor.Backward {
+		slices.Reverse(tasks)
+	}
+
+	return tasks, nil
+}
+
+// Search runs a full-text query over title and details using the text search
+// configuration of the owner's preferred language, best matches first.
+// Highlights are left raw, with matches enclosed in domain.HighlightStart and
+// domain.HighlightStop.
+func (r *TaskRepository) Search(ctx context.Context, search *domain.TaskSearch, page, pageSize uint64) ([]*domain.TaskSearchResult, error) {
+	tsQuery, tsArgs := tsQueryExpr(search)
+	headline := fmt.Sprintf("StartSel=%s, StopSel=%s", domain.HighlightStart, domain.HighlightStop)
+
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskAllColumns...).
+		Prefix(
+			"WITH q AS (SELECT c.cfg, "+tsQuery+" AS query FROM "+
+				"(SELECT task_search_config(preferred_language) AS cfg FROM users WHERE id = ?) c)",
+			append(tsArgs, search.OwnerID)...,
+		).
+		Column("ts_rank_cd("+domain.ColTaskSearch+", q.query) AS rank").
+		Column(squirrel.Expr("ts_headline(q.cfg, "+domain.ColTaskTitle+", q.query, ?)", headline+", HighlightAll=true")).
+		Column(squirrel.Expr("ts_headline(q.cfg, coalesce("+domain.ColTaskDetails+", ''), q.query, ?)", headline+", MaxFragments=2, MaxWords=20, MinWords=5")).
+		From(domain.TableTask+" CROSS JOIN q").
+		Where(squirrel.Eq{
+			domain.ColTaskOwnerID:   search.OwnerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		Where(notInArchivedProject).
+		Where(domain.ColTaskSearch+" @@ q.query").
+		OrderBy("rank DESC", domain.ColID+" ASC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		ToSql()
 	if err != nil {
 		return nil, err
 	}
@@ -54,29 +123,23 @@ func (r *TaskRepository) List(ctx context.Context, page, pageSize uint64, ownerI
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	tasks := make([]*domain.Task, 0)
+	results := make([]*domain.TaskSearchResult, 0)
 	for rows.Next() {
-		var task domain.Task
-		err = rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Details,
-			&task.Priority,
-			&task.IsCompleted,
-			&task.StartDate,
-			&task.DueDate,
-			&task.OwnerID,
-			&task.CreatedAt,
-			&task.UpdatedAt,
+		var result domain.TaskSearchResult
+		result.Task, err = scanTask(rows,
+			&result.Rank,
+			&result.TitleHighlight,
+			&result.DetailsHighlight,
 		)
 		if err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, &task)
+		results = append(results, &result)
 	}
 
-	return tasks, nil
+	return results, rows.Err()
 }
 
 func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) (*domain.Task, error) {
@@ -92,8 +155,17 @@ func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) (*domain
 			task.StartDate,
 			task.DueDate,
 			task.OwnerID,
+			task.ParentID,
+			task.ProjectID,
+			task.SeriesID,
+			task.Occurrence,
+			task.StatusID,
+			task.Position,
+			task.EstimateMinutes,
 			task.CreatedAt,
 			task.UpdatedAt,
+			task.DeletedAt,
+			task.Version,
 		).
 		ToSql()
 	if err != nil {
@@ -108,53 +180,376 @@ func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) (*domain
 	return task, nil
 }
 
-func (r *TaskRepository) Get(ctx context.Context, id string) (*domain.Task, error) {
+// CreateBatch inserts many tasks with a single statement.
+func (r *TaskRepository) CreateBatch(ctx context.Context, tasks []*domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	builder := r.client.QueryBuilder().
+		Insert(domain.TableTask).
+		Columns(domain.TaskAllColumns...)
+	for _, task := range tasks {
+		builder = builder.Values(
+			task.ID,
+			task.Title,
+			task.Details,
+			task.Priority,
+			task.IsCompleted,
+			task.StartDate,
+			task.DueDate,
+			task.OwnerID,
+			task.ParentID,
+			task.ProjectID,
+			task.SeriesID,
+			task.Occurrence,
+			task.StatusID,
+			task.Position,
+			task.EstimateMinutes,
+			task.CreatedAt,
+			task.UpdatedAt,
+			task.DeletedAt,
+			task.Version,
+		)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// Get returns the task identified by id only if it belongs to ownerID and is
+// not in the trash, otherwise pgx.ErrNoRows is returned.
+func (r *TaskRepository) Get(ctx context.Context, id, ownerID string) (*domain.Task, error) {
 	query, args, err := r.client.QueryBuilder().
 		Select(domain.TaskAllColumns...).
 		From(domain.TableTask).
-		Where(squirrel.Eq{domain.ColID: id}).
+		Where(squirrel.Eq{
+			domain.ColID:            id,
+			domain.ColTaskOwnerID:   ownerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
 		ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	var task domain.Task
-	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Details,
-		&task.Priority,
-		&task.IsCompleted,
-		&task.StartDate,
-		&task.DueDate,
-		&task.OwnerID,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	return scanTask(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// LockVersion locks the task identified by id until the end of the
+// transaction and returns its version. pgx.ErrNoRows is returned when ownerID
+// has no such task outside of the trash.
+func (r *TaskRepository) LockVersion(ctx context.Context, id, ownerID string) (int64, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.ColTaskVersion).
+		From(domain.TableTask).
+		Where(squirrel.Eq{
+			domain.ColID:            id,
+			domain.ColTaskOwnerID:   ownerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		Suffix("FOR UPDATE").
+		ToSql()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return &task, nil
+	var version int64
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&version)
+	return version, err
 }
 
+// Update replaces every mutable column of the task owned by task.OwnerID.
+// pgx.ErrNoRows is returned when no such task exists.
 func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) (*domain.Task, error) {
-	return task, nil
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableTask).
+		Set(domain.ColTaskTitle, task.Title).
+		Set(domain.ColTaskDetails, task.Details).
+		Set(domain.ColTaskPriority, task.Priority).
+		Set(domain.ColTaskIsCompleted, task.IsCompleted).
+		Set(domain.ColTaskStartDate, task.StartDate).
+		Set(domain.ColTaskDueDate, task.DueDate).
+		Set(domain.ColTaskParentID, task.ParentID).
+		Set(domain.ColTaskProjectID, task.ProjectID).
+		Set(domain.ColTaskStatusID, statusInProject(task.ProjectID)).
+		Set(domain.ColTaskEstimate, task.EstimateMinutes).
+		Set(domain.ColUpdatedAt, task.UpdatedAt).
+		Where(squirrel.Eq{
+			domain.ColID:            task.ID,
+			domain.ColTaskOwnerID:   task.OwnerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		Suffix("RETURNING " + strings.Join(domain.TaskAllColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTask(r.client.Pool().QueryRow(ctx, query, args...))
 }
 
-func (r *TaskRepository) Delete(ctx context.Context, id string) error {
+// Patch updates only the given columns of the task owned by ownerID.
+// pgx.ErrNoRows is returned when no such task exists.
+func (r *TaskRepository) Patch(ctx context.Context, id, ownerID string, changes map[string]any) (*domain.Task, error) {
+	if projectID, ok := changes[domain.ColTaskProjectID]; ok {
+		changes[domain.ColTaskStatusID] = statusInProject(projectID)
+	}
+
 	query, args, err := r.client.QueryBuilder().
-		Delete(domain.TableTask).
-		Where(squirrel.Eq{domain.ColID: id}).
+		Update(domain.TableTask).
+		SetMap(changes).
+		Where(squirrel.Eq{
+			domain.ColID:            id,
+			domain.ColTaskOwnerID:   ownerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		Suffix("RETURNING " + strings.Join(domain.TaskAllColumns, ", ")).
 		ToSql()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return scanTask(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// statusInProject keeps the status of a task while it stays in the same
+// project, and clears it when the task moves to another one.
+func statusInProject(projectID any) squirrel.Sqlizer {
+	return squirrel.Expr(
+		"CASE WHEN "+domain.ColTaskProjectID+" IS NOT DISTINCT FROM ? THEN "+domain.ColTaskStatusID+" END",
+		projectID,
+	)
+}
+
+// taskConditions translates a filter into a WHERE clause shared by List and Count.
+func taskConditions(filter *domain.TaskFilter) squirrel.And {
+	conds := squirrel.And{
+		squirrel.Eq{domain.ColTaskOwnerID: filter.OwnerID},
+	}
+
+	if filter.Trashed {
+		conds = append(conds, squirrel.NotEq{domain.ColTaskDeletedAt: nil})
+	} else {
+		conds = append(conds, squirrel.Eq{domain.ColTaskDeletedAt: nil})
+	}
+
+	switch {
+	case filter.ProjectID != nil:
+		conds = append(conds, squirrel.Eq{domain.ColTaskProjectID: *filter.ProjectID})
+	case filter.NoProject:
+		conds = append(conds, squirrel.Eq{domain.ColTaskProjectID: nil})
+	case !filter.IncludeArchived:
+		conds = append(conds, notInArchivedProject)
+	}
+
+	if filter.IsCompleted != nil {
+		conds = append(conds, squirrel.Eq{domain.ColTaskIsCompleted: *filter.IsCompleted})
+	}
+	if filter.MinPriority != nil {
+		conds = append(conds, squirrel.GtOrEq{domain.ColTaskPriority: *filter.MinPriority})
+	}
+	if filter.MaxPriority != nil {
+		conds = append(conds, squirrel.LtOrEq{domain.ColTaskPriority: *filter.MaxPriority})
+	}
+	if filter.StartFrom != nil {
+		conds = append(conds, squirrel.GtOrEq{domain.ColTaskStartDate: *filter.StartFrom})
+	}
+	if filter.StartTo != nil {
+		conds = append(conds, squirrel.Lt{domain.ColTaskStartDate: *filter.StartTo})
+	}
+	if filter.DueFrom != nil {
+		conds = append(conds, squirrel.GtOrEq{domain.ColTaskDueDate: *filter.DueFrom})
+	}
+	if filter.DueTo != nil {
+		conds = append(conds, squirrel.Lt{domain.ColTaskDueDate: *filter.DueTo})
+	}
+	if filter.Overdue {
+		conds = append(conds,
+			squirrel.Lt{domain.ColTaskDueDate: time.Now().UTC()},
+			squirrel.Eq{domain.ColTaskIsCompleted: false},
+		)
+	}
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		conds = append(conds, squirrel.Or{
+			squirrel.ILike{domain.ColTaskTitle: pattern},
+			squirrel.ILike{domain.ColTaskDetails: pattern},
+		})
+	}
+
+	if len(filter.LabelIDs) > 0 {
+		conds = append(conds, labelCondition(filter.LabelIDs, filter.LabelMatch))
+	}
+
+	return conds
+}
+
+// notInArchivedProject matches tasks outside of any archived project.
+var notInArchivedProject = squirrel.Expr("NOT EXISTS (SELECT 1 FROM " + domain.TableProjects +
+	" WHERE " + domain.TableProjects + "." + domain.ColID + " = " + domain.TableTask + "." + domain.ColTaskProjectID +
+	" AND " + domain.TableProjects + "." + domain.ColProjectIsArchived + ")")
+
+// labelCondition matches tasks carrying any, or all, of the given labels.
+func labelCondition(labelIDs []string, match string) squirrel.Sqlizer {
+	taskLabels := "SELECT %s FROM " + domain.TableTaskLabels +
+		" WHERE " + domain.ColTaskID + " = " + domain.TableTask + "." + domain.ColID +
+		" AND " + domain.ColLabelID + " = ANY(?::uuid[])"
+
+	if match == domain.LabelMatchAll {
+		labelIDs = slices.Compact(slices.Sorted(slices.Values(labelIDs)))
+		return squirrel.Expr(
+			"("+fmt.Sprintf(taskLabels, "count(DISTINCT "+domain.ColLabelID+")")+") = ?",
+			labelIDs, len(labelIDs),
+		)
+	}
+
+	return squirrel.Expr("EXISTS ("+fmt.Sprintf(taskLabels, "1")+")", labelIDs)
+}
+
+// taskOrderBy builds ORDER BY terms from whitelisted sort fields, defaulting to
+// the newest tasks first. The id is always appended to keep the order stable.
+// NULLs sort last, or first when the order is reversed for backward paging.
+func taskOrderBy(sort []domain.SortField, reverse bool) []string {
+	sort = withTieBreaker(sort)
+
+	terms := make([]string, 0, len(sort))
+	for _, field := range sort {
+		desc := field.Desc != reverse
+		direction, nulls := "ASC", "LAST"
+		if desc {
+			direction = "DESC"
+		}
+		if reverse {
+			nulls = "FIRST"
+		}
+		terms = append(terms, fmt.Sprintf("%s %s NULLS %s", field.Column, direction, nulls))
+	}
+
+	return terms
+}
+
+// keysetCondition selects the rows strictly after (or before, for a backward
+// cursor) the cursor row in the order produced by taskOrderBy. For sort keys
+// (c1, c2, ..., id) it expands to
+//
+//	c1 after v1 OR (c1 = v1 AND c2 after v2) OR ... OR (c1 = v1 AND ... AND id after last_id)
+//
+// where equality is NULL-safe and NULLs sort after every other value.
+func keysetCondition(sort []domain.SortField, cursor *domain.TaskCursor) squirrel.Or {
+	sort = withTieBreaker(sort)
+
+	cond := squirrel.Or{}
+	equal := squirrel.And{}
+	for _, field := range sort {
+		value := cursor.Last.ColumnValue(field.Column)
+
+		if term := keysetTerm(field, value, cursor.Backward); term != nil {
+			cond = append(cond, append(slices.Clone(equal), term))
+		}
+
+		if value == nil {
+			equal = append(equal, squirrel.Eq{field.Column: nil})
+		} else {
+			equal = append(equal, squirrel.Eq{field.Column: value})
+		}
+	}
+
+	if len(cond) == 0 {
+		cond = append(cond, squirrel.Expr("FALSE"))
+	}
+
+	return cond
+}
+
+// keysetTerm returns the condition for rows strictly after value in a single
+// column, or nil when no row can be.
+func keysetTerm(field domain.SortField, value any, backward bool) squirrel.Sqlizer {
+	if value == nil {
+		if backward {
+			return squirrel.NotEq{field.Column: nil}
+		}
+		return nil
+	}
+
+	greater := field.Desc == backward
+	switch {
+	case greater && !backward:
+		return squirrel.Or{squirrel.Gt{field.Column: value}, squirrel.Eq{field.Column: nil}}
+	case !greater && !backward:
+		return squirrel.Or{squirrel.Lt{field.Column: value}, squirrel.Eq{field.Column: nil}}
+	case greater:
+		return squirrel.Gt{field.Column: value}
+	default:
+		return squirrel.Lt{field.Column: value}
+	}
+}
+
+// withTieBreaker applies the default sort and appends the id so the order is total.
+func withTieBreaker(sort []domain.SortField) []domain.SortField {
+	if len(sort) == 0 {
+		sort = []domain.SortField{{Column: domain.ColCreatedAt, Desc: true}}
+	}
+
+	return append(slices.Clip(sort), domain.SortField{Column: domain.ColID})
+}
+
+// tsQueryExpr combines the search terms into a single tsquery expression over
+// the text search configuration c.cfg.
+func tsQueryExpr(search *domain.TaskSearch) (string, []any) {
+	parts := make([]string, 0)
+	args := make([]any, 0)
+
+	for _, word := range search.Words {
+		parts = append(parts, "plainto_tsquery(c.cfg, ?)")
+		args = append(args, word)
+	}
+	for _, phrase := range search.Phrases {
+		parts = append(parts, "phraseto_tsquery(c.cfg, ?)")
+		args = append(args, phrase)
+	}
+	for _, prefix := range search.Prefixes {
+		// prefixes only hold letters and digits, so quoting is enough
+		parts = append(parts, "to_tsquery(c.cfg, ?)")
+		args = append(args, "'"+prefix+"':*")
+	}
+
+	return "(" + strings.Join(parts, " && ") + ")", args
+}
+
+// scanTask reads a row selected with domain.TaskAllColumns, followed by any
+// extra columns scanned into dest.
+func scanTask(row pgx.Row, dest ...any) (*domain.Task, error) {
+	var task domain.Task
+	err := row.Scan(append([]any{
+		&task.ID,
+		&task.Title,
+		&task.Details,
+		&task.Priority,
+		&task.IsCompleted,
+		&task.StartDate,
+		&task.DueDate,
+		&task.OwnerID,
+		&task.ParentID,
+		&task.ProjectID,
+		&task.SeriesID,
+		&task.Occurrence,
+		&task.StatusID,
+		&task.Position,
+		&task.EstimateMinutes,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.DeletedAt,
+		&task.Version,
+	}, dest...)...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &task, nil
 }
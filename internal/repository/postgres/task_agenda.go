@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+// openTask matches the open tasks of ownerID outside the trash and of
+// archived projects.
+func openTask(ownerID string) squirrel.And {
+	return squirrel.And{
+		squirrel.Eq{
+			domain.TableTask + "." + domain.ColTaskOwnerID:     ownerID,
+			domain.TableTask + "." + domain.ColTaskIsCompleted: false,
+			domain.TableTask + "." + domain.ColTaskDeletedAt:   nil,
+		},
+		notInArchivedProject,
+	}
+}
+
+// ListScheduled returns the open tasks of ownerID starting or due in
+// [from, to), ordered by due date, or start date when they have none.
+func (r *TaskRepository) ListScheduled(ctx context.Context, ownerID string, from, to time.Time) ([]*domain.Task, error) {
+	date := "COALESCE(" + domain.ColTaskDueDate + ", " + domain.ColTaskStartDate + ")"
+
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskAllColumns...).
+		From(domain.TableTask).
+		Where(openTask(ownerID)).
+		Where(squirrel.Or{
+			squirrel.And{
+				squirrel.GtOrEq{domain.ColTaskStartDate: from},
+				squirrel.Lt{domain.ColTaskStartDate: to},
+			},
+			squirrel.And{
+				squirrel.GtOrEq{domain.ColTaskDueDate: from},
+				squirrel.Lt{domain.ColTaskDueDate: to},
+			},
+		}).
+		OrderBy(date+" ASC", domain.ColID+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryTasks(ctx, query, args...)
+}
+
+// ListOverdue returns the open tasks of ownerID due before the given time,
+// the most overdue first.
+func (r *TaskRepository) ListOverdue(ctx context.Context, ownerID string, before time.Time) ([]*domain.Task, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskAllColumns...).
+		From(domain.TableTask).
+		Where(openTask(ownerID)).
+		Where(squirrel.Lt{domain.ColTaskDueDate: before}).
+		OrderBy(domain.ColTaskDueDate+" ASC", domain.ColID+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryTasks(ctx, query, args...)
+}
+
+// ListSeriesHeads returns the latest task of every series of ownerID whose
+// latest task is outside the trash and of archived projects.
+func (r *TaskRepository) ListSeriesHeads(ctx context.Context, ownerID string) ([]*domain.Task, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskAllColumns...).
+		Prefix("SELECT * FROM (").
+		Options("DISTINCT ON ("+domain.ColTaskSeriesID+")").
+		From(domain.TableTask).
+		Where(squirrel.Eq{domain.ColTaskOwnerID: ownerID}).
+		Where(squirrel.NotEq{domain.ColTaskSeriesID: nil}).
+		OrderBy(domain.ColTaskSeriesID, domain.ColTaskOccurrence+" DESC").
+		Suffix(") heads WHERE " + domain.ColTaskDeletedAt + " IS NULL").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryTasks(ctx, query, args...)
+}
+
+// SetDayPlan replaces the tasks of ownerID planned on day, a YYYY-MM-DD date,
+// keeping the order of taskIDs. Tasks that do not belong to ownerID or are in
+// the trash are left out, so the number of planned tasks is returned.
+func (r *TaskRepository) SetDayPlan(ctx context.Context, ownerID, day string, taskIDs []string, createdAt time.Time) (int64, error) {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableDayPlanTasks).
+		Where(squirrel.Eq{domain.ColDayPlanOwnerID: ownerID}).
+		Where(squirrel.Expr(domain.ColDayPlanDay+" = ?::date", day)).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	if err != nil || len(taskIDs) == 0 {
+		return 0, err
+	}
+
+	tasks := squirrel.
+		Select().
+		Column(squirrel.Expr("?::uuid", ownerID)).
+		Column(squirrel.Expr("?::date", day)).
+		Column(domain.ColID).
+		Column(squirrel.Expr("array_position(?::uuid[], "+domain.ColID+")", taskIDs)).
+		Column(squirrel.Expr("?::timestamp", createdAt)).
+		From(domain.TableTask).
+		Where(squirrel.Expr(domain.ColID+" = ANY(?::uuid[])", taskIDs)).
+		Where(squirrel.Eq{
+			domain.ColTaskOwnerID:   ownerID,
+			domain.ColTaskDeletedAt: nil,
+		})
+
+	query, args, err = r.client.QueryBuilder().
+		Insert(domain.TableDayPlanTasks).
+		Columns(
+			domain.ColDayPlanOwnerID,
+			domain.ColDayPlanDay,
+			domain.ColDayPlanTaskID,
+			domain.ColDayPlanPosition,
+			domain.ColCreatedAt,
+		).
+		Select(tasks).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ListPlanned returns the tasks of ownerID outside the trash planned on the
+// days from fromDay to toDay included, keyed by YYYY-MM-DD date and in the
+// planned order.
+func (r *TaskRepository) ListPlanned(ctx context.Context, ownerID, fromDay, toDay string) (map[string][]*domain.Task, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(qualifyColumns(domain.TableTask, domain.TaskAllColumns)...).
+		Column("to_char(p."+domain.ColDayPlanDay+", 'YYYY-MM-DD')").
+		From(domain.TableDayPlanTasks+" p").
+		Join(domain.TableTask+" ON "+domain.TableTask+"."+domain.ColID+" = p."+domain.ColDayPlanTaskID).
+		Where(squirrel.Eq{
+			"p." + domain.ColDayPlanOwnerID:                  ownerID,
+			domain.TableTask + "." + domain.ColTaskDeletedAt: nil,
+		}).
+		Where(squirrel.Expr("p."+domain.ColDayPlanDay+" BETWEEN ?::date AND ?::date", fromDay, toDay)).
+		OrderBy("p."+domain.ColDayPlanDay+" ASC", "p."+domain.ColDayPlanPosition+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	planned := make(map[string][]*domain.Task)
+	for rows.Next() {
+		var day string
+		task, err := scanTask(rows, &day)
+		if err != nil {
+			return nil, err
+		}
+		planned[day] = append(planned[day], task)
+	}
+
+	return planned, rows.Err()
+}
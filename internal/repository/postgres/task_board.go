@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+// LockColumn locks a column of tasks of ownerID until the end of the
+// transaction, so that two transactions handing out positions in the column
+// do not read the same neighbours and pick the same position.
+func (r *TaskRepository) LockColumn(ctx context.Context, ownerID string, projectID, statusID *string) error {
+	key := ownerID
+	for _, id := range []*string{projectID, statusID} {
+		key += "/"
+		if id != nil {
+			key += *id
+		}
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select("pg_advisory_xact_lock(hashtextextended(?, 0))", key).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// LastPosition returns the highest position in a column of tasks of ownerID
+// not in the trash, or "" for an empty column. A nil projectID is the column
+// of tasks without project and a nil statusID the one of tasks without status.
+func (r *TaskRepository) LastPosition(ctx context.Context, ownerID string, projectID, statusID *string) (string, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("coalesce(max(" + domain.ColTaskPosition + "), '')").
+		From(domain.TableTask).
+		Where(columnConditions(ownerID, projectID, statusID)).
+		ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var position string
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&position)
+	if err != nil {
+		return "", err
+	}
+
+	return position, nil
+}
+
+// AdjacentPosition returns the position in a column that comes right after
+// position, or right before it when next is false, ignoring the task
+// excludeID. It returns "" when there is no such position.
+func (r *TaskRepository) AdjacentPosition(ctx context.Context, ownerID string, projectID, statusID *string, position string, next bool, excludeID string) (string, error) {
+	aggregate, cond := "max", squirrel.Sqlizer(squirrel.Lt{domain.ColTaskPosition: position})
+	if next {
+		aggregate, cond = "min", squirrel.Gt{domain.ColTaskPosition: position}
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select("coalesce(" + aggregate + "(" + domain.ColTaskPosition + "), '')").
+		From(domain.TableTask).
+		Where(columnConditions(ownerID, projectID, statusID)).
+		Where(cond).
+		Where(squirrel.NotEq{domain.ColID: excludeID}).
+		ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var adjacent string
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&adjacent)
+	if err != nil {
+		return "", err
+	}
+
+	return adjacent, nil
+}
+
+// Move puts a task of ownerID in the column of statusID at position.
+// pgx.ErrNoRows is returned when no such task exists.
+func (r *TaskRepository) Move(ctx context.Context, id, ownerID string, statusID *string, position string, updatedAt time.Time) (*domain.Task, error) {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableTask).
+		Set(domain.ColTaskStatusID, statusID).
+		Set(domain.ColTaskPosition, position).
+		Set(domain.ColUpdatedAt, updatedAt).
+		Where(squirrel.Eq{
+			domain.ColID:            id,
+			domain.ColTaskOwnerID:   ownerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		Suffix("RETURNING " + joinColumns(domain.TaskAllColumns)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTask(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// columnConditions matches the live tasks of a column of a board.
+func columnConditions(ownerID string, projectID, statusID *string) squirrel.Eq {
+	return squirrel.Eq{
+		domain.ColTaskOwnerID:   ownerID,
+		domain.ColTaskProjectID: projectID,
+		domain.ColTaskStatusID:  statusID,
+		domain.ColTaskDeletedAt: nil,
+	}
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+// ListDated returns up to limit tasks of ownerID, outside the trash and of
+// archived projects, that are due from the given time on, or start from then
+// on when they have no due date. They are ordered by that date.
+func (r *TaskRepository) ListDated(ctx context.Context, ownerID string, from time.Time, limit uint64) ([]*domain.Task, error) {
+	date := "COALESCE(" + domain.ColTaskDueDate + ", " + domain.ColTaskStartDate + ")"
+
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskAllColumns...).
+		From(domain.TableTask).
+		Where(squirrel.Eq{
+			domain.ColTaskOwnerID:   ownerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		Where(notInArchivedProject).
+		Where(squirrel.Expr(date+" >= ?", from)).
+		OrderBy(date+" ASC", domain.ColID+" ASC").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryTasks(ctx, query, args...)
+}
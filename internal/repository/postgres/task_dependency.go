@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+// blockersCTE selects every task blocking a task, directly or through other
+// tasks, trashed ones included. UNION stops the recursion on cycles.
+const blockersCTE = "WITH RECURSIVE blockers AS (" +
+	"SELECT " + domain.ColDependencyBlockerID + " AS id FROM " + domain.TableTaskDependencies +
+	" WHERE " + domain.ColDependencyTaskID + " = ? AND " + domain.ColDependencyOwnerID + " = ?" +
+	" UNION " +
+	"SELECT d." + domain.ColDependencyBlockerID + " FROM " + domain.TableTaskDependencies + " d" +
+	" JOIN blockers b ON d." + domain.ColDependencyTaskID + " = b.id WHERE d." + domain.ColDependencyOwnerID + " = ?" +
+	")"
+
+// levelsCTE ranks the open tasks given by the open CTE by the length of the
+// longest chain of open tasks blocking them. UNION keeps a single row per task
+// and level, and the level is bounded by the number of open tasks so that the
+// recursion ends even if the edges were to form a cycle.
+const levelsCTE = "edges AS (" +
+	"SELECT d." + domain.ColDependencyTaskID + ", d." + domain.ColDependencyBlockerID +
+	" FROM " + domain.TableTaskDependencies + " d" +
+	" JOIN open a ON a.id = d." + domain.ColDependencyTaskID +
+	" JOIN open b ON b.id = d." + domain.ColDependencyBlockerID +
+	"), levels (id, level) AS (" +
+	"SELECT o.id, 0 FROM open o WHERE NOT EXISTS (SELECT 1 FROM edges e WHERE e." + domain.ColDependencyTaskID + " = o.id)" +
+	" UNION " +
+	"SELECT e." + domain.ColDependencyTaskID + ", l.level + 1 FROM edges e" +
+	" JOIN levels l ON e." + domain.ColDependencyBlockerID + " = l.id" +
+	" WHERE l.level < (SELECT count(*) FROM open)" +
+	"), ranked AS (SELECT id, max(level) AS level FROM levels GROUP BY id)"
+
+// AddDependency makes blockerID block taskID. Adding an existing dependency
+// does nothing.
+func (r *TaskRepository) AddDependency(ctx context.Context, taskID, blockerID, ownerID string, createdAt time.Time) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableTaskDependencies).
+		Columns(
+			domain.ColDependencyTaskID,
+			domain.ColDependencyBlockerID,
+			domain.ColDependencyOwnerID,
+			domain.ColCreatedAt,
+		).
+		Values(taskID, blockerID, ownerID, createdAt).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// RemoveDependency stops blockerID from blocking taskID. pgx.ErrNoRows is
+// returned when there is no such dependency.
+func (r *TaskRepository) RemoveDependency(ctx context.Context, taskID, blockerID, ownerID string) error {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableTaskDependencies).
+		Where(squirrel.Eq{
+			domain.ColDependencyTaskID:    taskID,
+			domain.ColDependencyBlockerID: blockerID,
+			domain.ColDependencyOwnerID:   ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// IsBlockedBy reports whether blockerID blocks id, directly or through other
+// tasks.
+func (r *TaskRepository) IsBlockedBy(ctx context.Context, id, blockerID, ownerID string) (bool, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("1").
+		Prefix(blockersCTE+" SELECT EXISTS(", id, ownerID, ownerID).
+		From("blockers").
+		Where(squirrel.Eq{domain.ColID: blockerID}).
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var blocked bool
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&blocked)
+	if err != nil {
+		return false, err
+	}
+
+	return blocked, nil
+}
+
+// OpenBlockers returns the ids of the open tasks, not in the trash, directly
+// blocking a task.
+func (r *TaskRepository) OpenBlockers(ctx context.Context, id, ownerID string) ([]string, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("d." + domain.ColDependencyBlockerID).
+		From(domain.TableTaskDependencies + " d").
+		Join(domain.TableTask + " t ON t." + domain.ColID + " = d." + domain.ColDependencyBlockerID).
+		Where(squirrel.Eq{
+			"d." + domain.ColDependencyTaskID:  id,
+			"d." + domain.ColDependencyOwnerID: ownerID,
+			"t." + domain.ColTaskIsCompleted:   false,
+			"t." + domain.ColTaskDeletedAt:     nil,
+		}).
+		OrderBy("d." + domain.ColCreatedAt + " ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Dependencies returns, for each of the given tasks, the ids of the tasks
+// blocking it and of the tasks it blocks. Tasks in the trash are left out.
+func (r *TaskRepository) Dependencies(ctx context.Context, taskIDs []string) (map[string][]string, map[string][]string, error) {
+	blockedBy, blocks := make(map[string][]string), make(map[string][]string)
+	if len(taskIDs) == 0 {
+		return blockedBy, blocks, nil
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select("d."+domain.ColDependencyTaskID, "d."+domain.ColDependencyBlockerID).
+		From(domain.TableTaskDependencies+" d").
+		Join(domain.TableTask+" a ON a."+domain.ColID+" = d."+domain.ColDependencyTaskID).
+		Join(domain.TableTask+" b ON b."+domain.ColID+" = d."+domain.ColDependencyBlockerID).
+		Where(squirrel.Or{
+			squirrel.Eq{"d." + domain.ColDependencyTaskID: taskIDs},
+			squirrel.Eq{"d." + domain.ColDependencyBlockerID: taskIDs},
+		}).
+		Where(squirrel.Eq{
+			"a." + domain.ColTaskDeletedAt: nil,
+			"b." + domain.ColTaskDeletedAt: nil,
+		}).
+		OrderBy("d."+domain.ColCreatedAt+" ASC", "d."+domain.ColDependencyBlockerID+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, blockerID string
+		err = rows.Scan(&taskID, &blockerID)
+		if err != nil {
+			return nil, nil, err
+		}
+		blockedBy[taskID] = append(blockedBy[taskID], blockerID)
+		blocks[blockerID] = append(blocks[blockerID], taskID)
+	}
+
+	return blockedBy, blocks, rows.Err()
+}
+
+// Next returns up to limit open tasks of ownerID in topological order of
+// their dependencies: unblocked tasks first, then the tasks they unblock, and
+// so on. Within a level the most important and most urgent tasks come first.
+// Tasks in the trash or in archived projects are left out.
+func (r *TaskRepository) Next(ctx context.Context, ownerID string, limit uint64) ([]*domain.NextTask, error) {
+	open, openArgs, err := squirrel.
+		Select(domain.ColID).
+		From(domain.TableTask).
+		Where(squirrel.Eq{
+			domain.ColTaskOwnerID:     ownerID,
+			domain.ColTaskIsCompleted: false,
+			domain.ColTaskDeletedAt:   nil,
+		}).
+		Where(notInArchivedProject).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select(qualifyColumns("t", domain.TaskAllColumns)...).
+		Column("r.level").
+		Prefix("WITH RECURSIVE open AS ("+open+"), "+levelsCTE, openArgs...).
+		From("ranked r").
+		Join(domain.TableTask+" t ON t."+domain.ColID+" = r.id").
+		OrderBy(
+			"r.level ASC",
+			"t."+domain.ColTaskPriority+" DESC",
+			"t."+domain.ColTaskDueDate+" ASC NULLS LAST",
+			"t."+domain.ColCreatedAt+" ASC",
+			"t."+domain.ColID+" ASC",
+		).
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := make([]*domain.NextTask, 0)
+	for rows.Next() {
+		var level int
+		task, err := scanTask(rows, &level)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &domain.NextTask{Task: task, Level: level})
+	}
+
+	return tasks, rows.Err()
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+)
+
+type TaskEventRepository struct {
+	client postgres.DB
+}
+
+func NewTaskEventRepository(pgc postgres.DB) *TaskEventRepository {
+	return &TaskEventRepository{
+		client: pgc,
+	}
+}
+
+// Create appends events to the history with a single statement.
+func (r *TaskEventRepository) Create(ctx context.Context, events ...*domain.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	builder := r.client.QueryBuilder().
+		Insert(domain.TableTaskEvents).
+		Columns(domain.TaskEventAllColumns...)
+	for _, event := range events {
+		changes, err := json.Marshal(event.Changes)
+		if err != nil {
+			return err
+		}
+
+		builder = builder.Values(
+			event.ID,
+			event.TaskID,
+			event.OwnerID,
+			event.ActorID,
+			event.Type,
+			string(changes),
+			event.RequestID,
+			event.CreatedAt,
+		)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// List returns a page of the history of a task of ownerID, newest first.
+func (r *TaskEventRepository) List(ctx context.Context, taskID, ownerID string, page, pageSize uint64) ([]*domain.TaskEvent, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskEventAllColumns...).
+		From(domain.TableTaskEvents).
+		Where(squirrel.Eq{
+			domain.ColTaskEventTaskID:  taskID,
+			domain.ColTaskEventOwnerID: ownerID,
+		}).
+		OrderBy(domain.ColCreatedAt+" DESC", domain.ColID+" DESC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*domain.TaskEvent, 0)
+	for rows.Next() {
+		event, err := scanTaskEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *TaskEventRepository) Count(ctx context.Context, taskID, ownerID string) (int64, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("count(*)").
+		From(domain.TableTaskEvents).
+		Where(squirrel.Eq{
+			domain.ColTaskEventTaskID:  taskID,
+			domain.ColTaskEventOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func scanTaskEvent(row pgx.Row) (*domain.TaskEvent, error) {
+	var (
+		event   domain.TaskEvent
+		changes []byte
+	)
+	err := row.Scan(
+		&event.ID,
+		&event.TaskID,
+		&event.OwnerID,
+		&event.ActorID,
+		&event.Type,
+		&changes,
+		&event.RequestID,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(changes, &event.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+)
+
+type TaskSeriesRepository struct {
+	client postgres.DB
+}
+
+func NewTaskSeriesRepository(pgc postgres.DB) *TaskSeriesRepository {
+	return &TaskSeriesRepository{
+		client: pgc,
+	}
+}
+
+func (r *TaskSeriesRepository) Create(ctx context.Context, series *domain.TaskSeries) error {
+	labelIDs := series.LabelIDs
+	if labelIDs == nil {
+		labelIDs = []string{}
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableTaskSeries).
+		Columns(domain.TaskSeriesAllColumns...).
+		Values(
+			series.ID,
+			series.OwnerID,
+			series.RRule,
+			series.DTStart,
+			series.LastOccurrence,
+			series.Title,
+			series.Details,
+			series.Priority,
+			series.ProjectID,
+			series.ParentID,
+			labelIDs,
+			durationSeconds(series.StartOffset),
+			durationSeconds(series.DueOffset),
+			series.TimeZone,
+			series.CreatedAt,
+			series.UpdatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// Get returns a series of ownerID, or pgx.ErrNoRows.
+func (r *TaskSeriesRepository) Get(ctx context.Context, id, ownerID string) (*domain.TaskSeries, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskSeriesAllColumns...).
+		From(domain.TableTaskSeries).
+		Where(squirrel.Eq{
+			domain.ColID:            id,
+			domain.ColSeriesOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTaskSeries(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// End stops a series after lastOccurrence, unless it already ends earlier.
+func (r *TaskSeriesRepository) End(ctx context.Context, id, ownerID string, lastOccurrence int) error {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableTaskSeries).
+		Set(domain.ColSeriesLastOccurrence, squirrel.Expr(
+			"least(coalesce("+domain.ColSeriesLastOccurrence+", ?), ?)", lastOccurrence, lastOccurrence,
+		)).
+		Set(domain.ColUpdatedAt, time.Now().UTC()).
+		Where(squirrel.Eq{
+			domain.ColID:            id,
+			domain.ColSeriesOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// ListRules returns the recurrence rules of the given series keyed by id.
+func (r *TaskSeriesRepository) ListRules(ctx context.Context, ids []string) (map[string]string, error) {
+	rules := make(map[string]string)
+	if len(ids) == 0 {
+		return rules, nil
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.ColID, domain.ColSeriesRRule).
+		From(domain.TableTaskSeries).
+		Where(squirrel.Eq{domain.ColID: ids}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, rule string
+		err = rows.Scan(&id, &rule)
+		if err != nil {
+			return nil, err
+		}
+		rules[id] = rule
+	}
+
+	return rules, rows.Err()
+}
+
+// ListByIDs returns the given series keyed by id.
+func (r *TaskSeriesRepository) ListByIDs(ctx context.Context, ids []string) (map[string]*domain.TaskSeries, error) {
+	series := make(map[string]*domain.TaskSeries)
+	if len(ids) == 0 {
+		return series, nil
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskSeriesAllColumns...).
+		From(domain.TableTaskSeries).
+		Where(squirrel.Eq{domain.ColID: ids}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanTaskSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		series[s.ID] = s
+	}
+
+	return series, rows.Err()
+}
+
+// CreateOccurrence inserts the task of an occurrence unless the series already
+// has one, reporting whether it was created.
+func (r *TaskRepository) CreateOccurrence(ctx context.Context, task *domain.Task) (bool, error) {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableTask).
+		Columns(domain.TaskAllColumns...).
+		Values(
+			task.ID,
+			task.Title,
+			task.Details,
+			task.Priority,
+			task.IsCompleted,
+			task.StartDate,
+			task.DueDate,
+			task.OwnerID,
+			task.ParentID,
+			task.ProjectID,
+			task.SeriesID,
+			task.Occurrence,
+			task.StatusID,
+			task.Position,
+			task.EstimateMinutes,
+			task.CreatedAt,
+			task.UpdatedAt,
+			task.DeletedAt,
+			task.Version,
+		).
+		Suffix("ON CONFLICT (" + domain.ColTaskSeriesID + ", " + domain.ColTaskOccurrence + ")" +
+			" WHERE " + domain.ColTaskSeriesID + " IS NOT NULL DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// openOccurrencesCTE selects the open tasks of a series scheduled after an
+// occurrence, and their subtasks, that are not in the trash.
+var openOccurrencesCTE = "WITH RECURSIVE cut AS (" +
+	"SELECT id FROM " + domain.TableTask +
+	" WHERE series_id = ? AND owner_id = ? AND is_completed = false AND occurrence > ? AND deleted_at IS NULL" +
+	" UNION ALL " +
+	"SELECT t.id FROM " + domain.TableTask + " t" +
+	" JOIN cut c ON t.parent_id = c.id WHERE t.owner_id = ? AND t.deleted_at IS NULL" +
+	") CYCLE id SET is_cycle USING path"
+
+// TrashOpenOccurrences moves the open tasks of a series scheduled after the
+// given occurrence to the trash, along with their subtasks, as Trash does.
+func (r *TaskRepository) TrashOpenOccurrences(ctx context.Context, seriesID, ownerID string, after int, deletedAt time.Time) error {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableTask).
+		Prefix(openOccurrencesCTE, seriesID, ownerID, after, ownerID).
+		Set(domain.ColTaskDeletedAt, deletedAt).
+		Where("id IN (SELECT id FROM cut)").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+func scanTaskSeries(row pgx.Row) (*domain.TaskSeries, error) {
+	var (
+		series                 domain.TaskSeries
+		startOffset, dueOffset *int64
+	)
+	err := row.Scan(
+		&series.ID,
+		&series.OwnerID,
+		&series.RRule,
+		&series.DTStart,
+		&series.LastOccurrence,
+		&series.Title,
+		&series.Details,
+		&series.Priority,
+		&series.ProjectID,
+		&series.ParentID,
+		&series.LabelIDs,
+		&startOffset,
+		&dueOffset,
+		&series.TimeZone,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	series.StartOffset = secondsDuration(startOffset)
+	series.DueOffset = secondsDuration(dueOffset)
+	return &series, nil
+}
+
+// durationSeconds and secondsDuration convert offsets to and from the whole
+// seconds stored in BIGINT columns.
+func durationSeconds(d *time.Duration) *int64 {
+	if d == nil {
+		return nil
+	}
+
+	seconds := int64(*d / time.Second)
+	return &seconds
+}
+
+func secondsDuration(seconds *int64) *time.Duration {
+	if seconds == nil {
+		return nil
+	}
+
+	d := time.Duration(*seconds) * time.Second
+	return &d
+}
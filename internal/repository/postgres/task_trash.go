@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+// trashedSubtreeCTE selects a trashed task and the descendants trashed along
+// with it, that is at the very same time.
+var trashedSubtreeCTE = "WITH RECURSIVE trashed AS (" +
+	"SELECT id, deleted_at FROM " + domain.TableTask +
+	" WHERE id = ? AND owner_id = ? AND deleted_at IS NOT NULL" +
+	" UNION ALL " +
+	"SELECT t.id, t.deleted_at FROM " + domain.TableTask + " t" +
+	" JOIN trashed s ON t.parent_id = s.id WHERE t.owner_id = ? AND t.deleted_at = s.deleted_at" +
+	") CYCLE id SET is_cycle USING path"
+
+// Trash moves a task and its subtasks to the trash. pgx.ErrNoRows is returned
+// when the owner has no such task outside of the trash.
+func (r *TaskRepository) Trash(ctx context.Context, id, ownerID string, deletedAt time.Time) error {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableTask).
+		Prefix(subtreeCTE, id, ownerID, ownerID).
+		Set(domain.ColTaskDeletedAt, deletedAt).
+		Where("id IN (SELECT id FROM subtree)").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetTrashed returns a task of ownerID that is in the trash, or pgx.ErrNoRows.
+func (r *TaskRepository) GetTrashed(ctx context.Context, id, ownerID string) (*domain.Task, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskAllColumns...).
+		From(domain.TableTask).
+		Where(squirrel.Eq{
+			domain.ColID:          id,
+			domain.ColTaskOwnerID: ownerID,
+		}).
+		Where(squirrel.NotEq{domain.ColTaskDeletedAt: nil}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTask(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// Restore takes a task out of the trash together with the subtasks that were
+// trashed with it. pgx.ErrNoRows is returned when the task is not in the trash.
+func (r *TaskRepository) Restore(ctx context.Context, id, ownerID string, updatedAt time.Time) error {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableTask).
+		Prefix(trashedSubtreeCTE, id, ownerID, ownerID).
+		Set(domain.ColTaskDeletedAt, nil).
+		Set(domain.ColUpdatedAt, updatedAt).
+		Where("id IN (SELECT id FROM trashed)").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// keptAncestorsCTE selects the tasks that have a descendant either out of the
+// trash or trashed at or after the given time, starting from the children of
+// the tasks trashed before it.
+var keptAncestorsCTE = "WITH RECURSIVE kept AS (" +
+	"SELECT t.id, t.parent_id FROM " + domain.TableTask + " t" +
+	" JOIN " + domain.TableTask + " p ON p.id = t.parent_id" +
+	" WHERE p.deleted_at < ? AND (t.deleted_at IS NULL OR t.deleted_at >= ?)" +
+	" UNION " +
+	"SELECT p.id, p.parent_id FROM " + domain.TableTask + " p" +
+	" JOIN kept k ON p.id = k.parent_id" +
+	")"
+
+// Purge permanently removes the tasks trashed before the given time and
+// returns how many were removed. A task with a descendant still to be kept,
+// such as a subtask trashed on its own after it, waits for that descendant,
+// as removing the task would cascade to its subtasks.
+func (r *TaskRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableTask).
+		Prefix(keptAncestorsCTE, before, before).
+		Where(squirrel.Lt{domain.ColTaskDeletedAt: before}).
+		Where("id NOT IN (SELECT id FROM kept)").
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
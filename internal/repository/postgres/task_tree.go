@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+// subtreeCTE selects a task and all of its descendants owned by the same user
+// and not in the trash, with their depth below the root. The CYCLE clause stops
+// the recursion should the hierarchy ever contain a loop.
+var subtreeCTE = "WITH RECURSIVE subtree AS (" +
+	"SELECT " + strings.Join(domain.TaskAllColumns, ", ") + ", 0 AS depth FROM " + domain.TableTask +
+	" WHERE id = ? AND owner_id = ? AND deleted_at IS NULL" +
+	" UNION ALL " +
+	"SELECT t." + strings.Join(domain.TaskAllColumns, ", t.") + ", s.depth + 1 FROM " + domain.TableTask + " t" +
+	" JOIN subtree s ON t.parent_id = s.id WHERE t.owner_id = ? AND t.deleted_at IS NULL" +
+	") CYCLE id SET is_cycle USING path"
+
+// ancestorsCTE selects a task and all of its ancestors not in the trash.
+var ancestorsCTE = "WITH RECURSIVE ancestors AS (" +
+	"SELECT id, parent_id FROM " + domain.TableTask + " WHERE id = ? AND owner_id = ? AND deleted_at IS NULL" +
+	" UNION ALL " +
+	"SELECT t.id, t.parent_id FROM " + domain.TableTask + " t" +
+	" JOIN ancestors a ON t.id = a.parent_id WHERE t.owner_id = ? AND t.deleted_at IS NULL" +
+	") CYCLE id SET is_cycle USING path"
+
+// Children returns the direct subtasks of a task.
+func (r *TaskRepository) Children(ctx context.Context, id, ownerID string) ([]*domain.Task, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskAllColumns...).
+		From(domain.TableTask).
+		Where(squirrel.Eq{
+			domain.ColTaskParentID:  id,
+			domain.ColTaskOwnerID:   ownerID,
+			domain.ColTaskDeletedAt: nil,
+		}).
+		OrderBy(domain.ColCreatedAt+" ASC", domain.ColID+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryTasks(ctx, query, args...)
+}
+
+// Subtree returns a task followed by all of its descendants, shallowest first.
+// The result is empty when the task does not exist.
+func (r *TaskRepository) Subtree(ctx context.Context, id, ownerID string) ([]*domain.Task, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TaskAllColumns...).
+		Prefix(subtreeCTE, id, ownerID, ownerID).
+		From("subtree").
+		Where("NOT is_cycle").
+		OrderBy("depth ASC", domain.ColCreatedAt+" ASC", domain.ColID+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryTasks(ctx, query, args...)
+}
+
+// IsAncestorOrSelf reports whether ancestorID is id itself or one of its ancestors.
+func (r *TaskRepository) IsAncestorOrSelf(ctx context.Context, ancestorID, id, ownerID string) (bool, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("1").
+		Prefix(ancestorsCTE+" SELECT EXISTS(", id, ownerID, ownerID).
+		From("ancestors").
+		Where(squirrel.Eq{domain.ColID: ancestorID}).
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var found bool
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&found)
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+// CompleteSubtree marks every open descendant of a task as completed.
+func (r *TaskRepository) CompleteSubtree(ctx context.Context, id, ownerID string, updatedAt time.Time) error {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableTask).
+		Prefix(subtreeCTE, id, ownerID, ownerID).
+		Set(domain.ColTaskIsCompleted, true).
+		Set(domain.ColUpdatedAt, updatedAt).
+		Where("id IN (SELECT id FROM subtree WHERE depth > 0)").
+		Where(squirrel.Eq{domain.ColTaskIsCompleted: false}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+// ReopenAncestors marks every completed ancestor of a task as open again.
+func (r *TaskRepository) ReopenAncestors(ctx context.Context, id, ownerID string, updatedAt time.Time) error {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableTask).
+		Prefix(ancestorsCTE, id, ownerID, ownerID).
+		Set(domain.ColTaskIsCompleted, false).
+		Set(domain.ColUpdatedAt, updatedAt).
+		Where(squirrel.Expr("id IN (SELECT id FROM ancestors WHERE id <> ?)", id)).
+		Where(squirrel.Eq{domain.ColTaskIsCompleted: true}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return err
+}
+
+func (r *TaskRepository) queryTasks(ctx context.Context, query string, args ...any) ([]*domain.Task, error) {
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := make([]*domain.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
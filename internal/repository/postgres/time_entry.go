@@ -0,0 +1,316 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/postgres"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+)
+
+// spanSeconds is the length in whole seconds of a span of the spans CTE.
+const spanSeconds = "SUM(EXTRACT(EPOCH FROM sp.ended_at - sp.started_at))::bigint"
+
+type TimeEntryRepository struct {
+	client postgres.DB
+}
+
+func NewTimeEntryRepository(pgc postgres.DB) *TimeEntryRepository {
+	return &TimeEntryRepository{
+		client: pgc,
+	}
+}
+
+// List returns a page of the time entries of a task of ownerID, latest first.
+func (r *TimeEntryRepository) List(ctx context.Context, taskID, ownerID string, page, pageSize uint64) ([]*domain.TimeEntry, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TimeEntryAllColumns...).
+		From(domain.TableTimeEntries).
+		Where(squirrel.Eq{
+			domain.ColTimeEntryTaskID:  taskID,
+			domain.ColTimeEntryOwnerID: ownerID,
+		}).
+		OrderBy(domain.ColTimeEntryStartedAt+" DESC", domain.ColID+" ASC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.TimeEntry, 0)
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *TimeEntryRepository) Count(ctx context.Context, taskID, ownerID string) (int64, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select("count(*)").
+		From(domain.TableTimeEntries).
+		Where(squirrel.Eq{
+			domain.ColTimeEntryTaskID:  taskID,
+			domain.ColTimeEntryOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	err = r.client.Pool().QueryRow(ctx, query, args...).Scan(&total)
+	return total, err
+}
+
+// SumByTasks returns the tracked seconds of the given tasks, leaving out the
+// tasks without time entries.
+func (r *TimeEntryRepository) SumByTasks(ctx context.Context, taskIDs []string) (map[string]int64, error) {
+	sums := make(map[string]int64)
+	if len(taskIDs) == 0 {
+		return sums, nil
+	}
+
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.ColTimeEntryTaskID, "SUM(EXTRACT(EPOCH FROM ended_at - started_at))::bigint").
+		From(domain.TableTimeEntries).
+		Where(squirrel.Eq{domain.ColTimeEntryTaskID: taskIDs}).
+		GroupBy(domain.ColTimeEntryTaskID).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID  string
+			seconds int64
+		)
+		err = rows.Scan(&taskID, &seconds)
+		if err != nil {
+			return nil, err
+		}
+		sums[taskID] = seconds
+	}
+
+	return sums, rows.Err()
+}
+
+func (r *TimeEntryRepository) Get(ctx context.Context, id, taskID, ownerID string) (*domain.TimeEntry, error) {
+	query, args, err := r.client.QueryBuilder().
+		Select(domain.TimeEntryAllColumns...).
+		From(domain.TableTimeEntries).
+		Where(squirrel.Eq{
+			domain.ColID:               id,
+			domain.ColTimeEntryTaskID:  taskID,
+			domain.ColTimeEntryOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTimeEntry(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+// Create inserts a time entry. errorx.ErrTaskNotFound is returned when its
+// task no longer exists.
+func (r *TimeEntryRepository) Create(ctx context.Context, entry *domain.TimeEntry) error {
+	query, args, err := r.client.QueryBuilder().
+		Insert(domain.TableTimeEntries).
+		Columns(domain.TimeEntryAllColumns...).
+		Values(
+			entry.ID,
+			entry.TaskID,
+			entry.OwnerID,
+			entry.StartedAt,
+			entry.EndedAt,
+			entry.Note,
+			entry.Source,
+			entry.CreatedAt,
+			entry.UpdatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pool().Exec(ctx, query, args...)
+	return foreignKeyViolation(err, errorx.ErrTaskNotFound)
+}
+
+// Update replaces the span and note of a time entry. pgx.ErrNoRows is
+// returned when no such entry exists.
+func (r *TimeEntryRepository) Update(ctx context.Context, entry *domain.TimeEntry) (*domain.TimeEntry, error) {
+	query, args, err := r.client.QueryBuilder().
+		Update(domain.TableTimeEntries).
+		Set(domain.ColTimeEntryStartedAt, entry.StartedAt).
+		Set(domain.ColTimeEntryEndedAt, entry.EndedAt).
+		Set(domain.ColTimeEntryNote, entry.Note).
+		Set(domain.ColUpdatedAt, entry.UpdatedAt).
+		Where(squirrel.Eq{
+			domain.ColID:               entry.ID,
+			domain.ColTimeEntryTaskID:  entry.TaskID,
+			domain.ColTimeEntryOwnerID: entry.OwnerID,
+		}).
+		Suffix("RETURNING " + joinColumns(domain.TimeEntryAllColumns)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTimeEntry(r.client.Pool().QueryRow(ctx, query, args...))
+}
+
+func (r *TimeEntryRepository) Delete(ctx context.Context, id, taskID, ownerID string) error {
+	query, args, err := r.client.QueryBuilder().
+		Delete(domain.TableTimeEntries).
+		Where(squirrel.Eq{
+			domain.ColID:               id,
+			domain.ColTimeEntryTaskID:  taskID,
+			domain.ColTimeEntryOwnerID: ownerID,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.client.Pool().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Report sums the time tracked in the range of filter by day, project or
+// label. Entries are clipped to the range, and split at midnight in the
+// filter's location when grouping by day. The time of a task is counted
+// under each of its labels.
+func (r *TimeEntryRepository) Report(ctx context.Context, filter *domain.TimeReportFilter) ([]*domain.TimeReportRow, error) {
+	spans, spansArgs, err := squirrel.
+		Select(domain.ColTimeEntryTaskID).
+		Column(squirrel.Expr("GREATEST("+domain.ColTimeEntryStartedAt+", ?::timestamp) AS started_at", filter.From)).
+		Column(squirrel.Expr("LEAST("+domain.ColTimeEntryEndedAt+", ?::timestamp) AS ended_at", filter.To)).
+		From(domain.TableTimeEntries).
+		Where(squirrel.Eq{domain.ColTimeEntryOwnerID: filter.OwnerID}).
+		Where(squirrel.Lt{domain.ColTimeEntryStartedAt: filter.To}).
+		Where(squirrel.Gt{domain.ColTimeEntryEndedAt: filter.From}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var builder squirrel.SelectBuilder
+	switch filter.GroupBy {
+	case domain.TimeReportByDay:
+		// Each span is joined to the local days it covers, as UTC bounds.
+		tz := filter.Location.String()
+		builder = r.client.QueryBuilder().
+			Select(
+				"to_char(d.day, 'YYYY-MM-DD')",
+				"NULL",
+				"SUM(EXTRACT(EPOCH FROM LEAST(sp.ended_at, d.day_end) - GREATEST(sp.started_at, d.day_start)))::bigint",
+			).
+			From("spans sp").
+			JoinClause(
+				"CROSS JOIN LATERAL (SELECT day,"+
+					" (day AT TIME ZONE ?) AT TIME ZONE 'UTC' AS day_start,"+
+					" ((day + interval '1 day') AT TIME ZONE ?) AT TIME ZONE 'UTC' AS day_end"+
+					" FROM generate_series("+
+					"date_trunc('day', (sp.started_at AT TIME ZONE 'UTC') AT TIME ZONE ?),"+
+					" (sp.ended_at AT TIME ZONE 'UTC') AT TIME ZONE ?,"+
+					" interval '1 day') AS day) d",
+				tz, tz, tz, tz,
+			).
+			Where("LEAST(sp.ended_at, d.day_end) > GREATEST(sp.started_at, d.day_start)").
+			GroupBy("d.day").
+			OrderBy("d.day ASC")
+	case domain.TimeReportByProject:
+		builder = r.client.QueryBuilder().
+			Select("t."+domain.ColTaskProjectID, "p."+domain.ColProjectName, spanSeconds).
+			From("spans sp").
+			Join(domain.TableTask+" t ON t."+domain.ColID+" = sp."+domain.ColTimeEntryTaskID).
+			LeftJoin(domain.TableProjects+" p ON p."+domain.ColID+" = t."+domain.ColTaskProjectID).
+			GroupBy("t."+domain.ColTaskProjectID, "p."+domain.ColProjectName).
+			OrderBy("3 DESC", "2 ASC NULLS LAST")
+	default:
+		builder = r.client.QueryBuilder().
+			Select("l."+domain.ColID, "l."+domain.ColLabelName, spanSeconds).
+			From("spans sp").
+			LeftJoin(domain.TableTaskLabels+" tl ON tl."+domain.ColTaskID+" = sp."+domain.ColTimeEntryTaskID).
+			LeftJoin(domain.TableLabels+" l ON l."+domain.ColID+" = tl."+domain.ColLabelID).
+			GroupBy("l."+domain.ColID, "l."+domain.ColLabelName).
+			OrderBy("3 DESC", "2 ASC NULLS LAST")
+	}
+
+	query, args, err := builder.
+		Prefix("WITH spans AS ("+spans+")", spansArgs...).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.client.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := make([]*domain.TimeReportRow, 0)
+	for rows.Next() {
+		var row domain.TimeReportRow
+		err = rows.Scan(&row.Key, &row.Name, &row.Seconds)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, &row)
+	}
+
+	return report, rows.Err()
+}
+
+func scanTimeEntry(row pgx.Row) (*domain.TimeEntry, error) {
+	var entry domain.TimeEntry
+	err := row.Scan(
+		&entry.ID,
+		&entry.TaskID,
+		&entry.OwnerID,
+		&entry.StartedAt,
+		&entry.EndedAt,
+		&entry.Note,
+		&entry.Source,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Seconds = int64(entry.EndedAt.Sub(entry.StartedAt) / time.Second)
+	return &entry, nil
+}
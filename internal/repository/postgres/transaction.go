@@ -19,7 +19,11 @@ func NewTransactionManager(pgc postgres.DB) *TransactionManager {
 
 type Statement func(ctx context.Context, tx pgx.Tx) error
 
-func (tm *TransactionManager) WithTransaction(ctx context.Context, isoLevel pgx.TxIsoLevel, txFunc Statement) error {
+// WithTransaction runs txFunc in a transaction, committed when txFunc returns
+// nil and rolled back otherwise. The context given to txFunc carries the
+// transaction, so repositories called with it take part in the transaction.
+// Called with such a context, WithTransaction opens a savepoint instead.
+func (tm *TransactionManager) WithTransaction(ctx context.Context, isoLevel pgx.TxIsoLevel, txFunc Statement) (err error) {
 	tx, err := tm.client.Pool().BeginTx(ctx, pgx.TxOptions{
 		IsoLevel: isoLevel,
 	})
@@ -38,5 +42,5 @@ func (tm *TransactionManager) WithTransaction(ctx context.Context, isoLevel pgx.
 		}
 	}()
 
-	return txFunc(ctx, tx)
+	return txFunc(postgres.WithTx(ctx, tx), tx)
 }
@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"gitlab.com/jodworkspace/mvp/pkg/db/redis"
+)
+
+const (
+	// analyticsKeyPrefix prefixes the cached analytics of a user.
+	analyticsKeyPrefix = "analytics:"
+	// analyticsGenKeyPrefix prefixes the generation of the cached analytics
+	// of a user, bumped on each of their task writes.
+	analyticsGenKeyPrefix = "analytics:gen:"
+)
+
+// AnalyticsCache caches the analytics of each user under their generation.
+// Invalidating bumps the generation, so that entries computed before are
+// never read again and expire on their own.
+type AnalyticsCache struct {
+	redisClient redis.Client
+	ttl         time.Duration
+}
+
+func NewAnalyticsCache(redisClient redis.Client, ttl time.Duration) *AnalyticsCache {
+	return &AnalyticsCache{
+		redisClient: redisClient,
+		ttl:         ttl,
+	}
+}
+
+// Generation returns the current generation of the analytics of ownerID.
+func (c *AnalyticsCache) Generation(ctx context.Context, ownerID string) (int64, error) {
+	gen, err := c.redisClient.Get(ctx, analyticsGenKeyPrefix+ownerID).Int64()
+	if errors.Is(err, goredis.Nil) {
+		return 0, nil
+	}
+
+	return gen, err
+}
+
+// Get decodes into dest the entry cached under key for a generation of
+// ownerID, reporting whether there was one.
+func (c *AnalyticsCache) Get(ctx context.Context, ownerID string, gen int64, key string, dest any) (bool, error) {
+	value, err := c.redisClient.Get(ctx, analyticsKey(ownerID, gen, key)).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, json.Unmarshal(value, dest)
+}
+
+// Set caches value under key for a generation of ownerID.
+func (c *AnalyticsCache) Set(ctx context.Context, ownerID string, gen int64, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return c.redisClient.Set(ctx, analyticsKey(ownerID, gen, key), data, c.ttl).Err()
+}
+
+// Invalidate drops the cached analytics of ownerID.
+func (c *AnalyticsCache) Invalidate(ctx context.Context, ownerID string) error {
+	return c.redisClient.Incr(ctx, analyticsGenKeyPrefix+ownerID).Err()
+}
+
+func analyticsKey(ownerID string, gen int64, key string) string {
+	return analyticsKeyPrefix + ownerID + ":" + strconv.FormatInt(gen, 10) + ":" + key
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	goredis "github.com/redis/go-redis/v9"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/db/redis"
+)
+
+// timerKeyPrefix prefixes the key holding the running timer of a user.
+const timerKeyPrefix = "timer:"
+
+// TimerRepository keeps the running timer of each user under a single key,
+// so that starting a timer while another one runs fails atomically.
+type TimerRepository struct {
+	redisClient redis.Client
+}
+
+func NewTimerRepository(redisClient redis.Client) *TimerRepository {
+	return &TimerRepository{
+		redisClient: redisClient,
+	}
+}
+
+// Get returns the running timer of ownerID, or nil when none is running.
+func (r *TimerRepository) Get(ctx context.Context, ownerID string) (*domain.Timer, error) {
+	value, err := r.redisClient.Get(ctx, timerKeyPrefix+ownerID).Bytes()
+	return decodeTimer(value, err)
+}
+
+// Start stores timer as the running timer of its owner unless one is already
+// running, reporting whether it was stored.
+func (r *TimerRepository) Start(ctx context.Context, timer *domain.Timer) (bool, error) {
+	value, err := json.Marshal(timer)
+	if err != nil {
+		return false, err
+	}
+
+	return r.redisClient.SetNX(ctx, timerKeyPrefix+timer.OwnerID, value, 0).Result()
+}
+
+// Stop removes and returns the running timer of ownerID, or nil when none is
+// running.
+func (r *TimerRepository) Stop(ctx context.Context, ownerID string) (*domain.Timer, error) {
+	value, err := r.redisClient.GetDel(ctx, timerKeyPrefix+ownerID).Bytes()
+	return decodeTimer(value, err)
+}
+
+func decodeTimer(value []byte, err error) (*domain.Timer, error) {
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var timer domain.Timer
+	err = json.Unmarshal(value, &timer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &timer, nil
+}
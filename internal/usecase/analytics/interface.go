@@ -0,0 +1,26 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+type Repository interface {
+	Completed(ctx context.Context, filter *domain.AnalyticsFilter) ([]*domain.CompletionBucket, error)
+	LeadTime(ctx context.Context, filter *domain.AnalyticsFilter) (*domain.LeadTime, int64, error)
+	Priorities(ctx context.Context, ownerID string, now time.Time) ([]*domain.PriorityShare, int64, error)
+	Streaks(ctx context.Context, ownerID string, today time.Time, loc *time.Location) (*domain.Streaks, error)
+	Burndown(ctx context.Context, filter *domain.BurndownFilter) ([]*domain.BurndownPoint, error)
+}
+
+type Cache interface {
+	Generation(ctx context.Context, ownerID string) (int64, error)
+	Get(ctx context.Context, ownerID string, gen int64, key string, dest any) (bool, error)
+	Set(ctx context.Context, ownerID string, gen int64, key string, value any) error
+}
+
+type ProjectRepository interface {
+	Exists(ctx context.Context, id, ownerID string) (bool, error)
+}
@@ -0,0 +1,199 @@
+package analytics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// maxRange bounds the range of a dashboard or burndown.
+const maxRange = 366 * 24 * time.Hour
+
+type UseCase struct {
+	analyticsRepo Repository
+	projectRepo   ProjectRepository
+	cache         Cache
+	logger        *logger.ZapLogger
+}
+
+func NewUseCase(
+	analyticsRepo Repository,
+	projectRepo ProjectRepository,
+	cache Cache,
+	zl *logger.ZapLogger,
+) *UseCase {
+	return &UseCase{
+		analyticsRepo: analyticsRepo,
+		projectRepo:   projectRepo,
+		cache:         cache,
+		logger:        zl,
+	}
+}
+
+// Dashboard returns the analytics of a user over a range of at most a year.
+// Overdue tasks and streaks are counted as of now.
+func (u *UseCase) Dashboard(ctx context.Context, filter *domain.AnalyticsFilter) (*domain.Analytics, error) {
+	if !filter.To.After(filter.From) || filter.To.Sub(filter.From) > maxRange {
+		return nil, errorx.ErrInvalidReportRange
+	}
+
+	now := time.Now().UTC()
+	today := now.In(filter.Location)
+	key := cacheKey("dashboard", filter.Period, filter.From, filter.To, filter.Location, today.Format(time.DateOnly))
+
+	var analytics domain.Analytics
+	gen, found := u.cached(ctx, filter.OwnerID, key, &analytics)
+	if found {
+		return &analytics, nil
+	}
+
+	completed, err := u.analyticsRepo.Completed(ctx, filter)
+	if err != nil {
+		u.logger.Error(
+			"analyticsUseCase - analyticsRepo.Completed",
+			zap.String("owner_id", filter.OwnerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	leadTime, completedLate, err := u.analyticsRepo.LeadTime(ctx, filter)
+	if err != nil {
+		u.logger.Error(
+			"analyticsUseCase - analyticsRepo.LeadTime",
+			zap.String("owner_id", filter.OwnerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	priorities, overdue, err := u.analyticsRepo.Priorities(ctx, filter.OwnerID, now)
+	if err != nil {
+		u.logger.Error(
+			"analyticsUseCase - analyticsRepo.Priorities",
+			zap.String("owner_id", filter.OwnerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	streaks, err := u.analyticsRepo.Streaks(ctx, filter.OwnerID, today, filter.Location)
+	if err != nil {
+		u.logger.Error(
+			"analyticsUseCase - analyticsRepo.Streaks",
+			zap.String("owner_id", filter.OwnerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	analytics = domain.Analytics{
+		Completed:  completed,
+		LeadTime:   leadTime,
+		Overdue:    &domain.OverdueCount{Open: overdue, CompletedLate: completedLate},
+		Priorities: priorities,
+		Streaks:    streaks,
+	}
+	u.store(ctx, filter.OwnerID, gen, key, &analytics)
+
+	return &analytics, nil
+}
+
+// Burndown returns the burndown of a project of a user over a range of at
+// most a year.
+func (u *UseCase) Burndown(ctx context.Context, filter *domain.BurndownFilter) ([]*domain.BurndownPoint, error) {
+	if !filter.To.After(filter.From) || filter.To.Sub(filter.From) > maxRange {
+		return nil, errorx.ErrInvalidReportRange
+	}
+
+	exists, err := u.projectRepo.Exists(ctx, filter.ProjectID, filter.OwnerID)
+	if err != nil {
+		u.logger.Error(
+			"analyticsUseCase - projectRepo.Exists",
+			zap.String("project_id", filter.ProjectID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if !exists {
+		return nil, errorx.ErrProjectNotFound
+	}
+
+	key := cacheKey("burndown:"+filter.ProjectID, "", filter.From, filter.To, filter.Location, "")
+
+	var points []*domain.BurndownPoint
+	gen, found := u.cached(ctx, filter.OwnerID, key, &points)
+	if found {
+		return points, nil
+	}
+
+	points, err = u.analyticsRepo.Burndown(ctx, filter)
+	if err != nil {
+		u.logger.Error(
+			"analyticsUseCase - analyticsRepo.Burndown",
+			zap.String("project_id", filter.ProjectID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	u.store(ctx, filter.OwnerID, gen, key, points)
+
+	return points, nil
+}
+
+// cached reads the entry under key into dest, returning the generation to
+// store it under when there is none. The cache only saves work, so its
+// failures are logged and computing goes on.
+func (u *UseCase) cached(ctx context.Context, ownerID, key string, dest any) (int64, bool) {
+	gen, err := u.cache.Generation(ctx, ownerID)
+	if err != nil {
+		u.logger.Warn(
+			"analyticsUseCase - cache.Generation",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return -1, false
+	}
+
+	found, err := u.cache.Get(ctx, ownerID, gen, key, dest)
+	if err != nil {
+		u.logger.Warn(
+			"analyticsUseCase - cache.Get",
+			zap.String("owner_id", ownerID),
+			zap.String("key", key),
+			zap.Error(err),
+		)
+		return gen, false
+	}
+
+	return gen, found
+}
+
+// store caches value under the generation it was computed in, so that a task
+// write made meanwhile leaves it unread.
+func (u *UseCase) store(ctx context.Context, ownerID string, gen int64, key string, value any) {
+	if gen < 0 {
+		return
+	}
+
+	err := u.cache.Set(ctx, ownerID, gen, key, value)
+	if err != nil {
+		u.logger.Warn(
+			"analyticsUseCase - cache.Set",
+			zap.String("owner_id", ownerID),
+			zap.String("key", key),
+			zap.Error(err),
+		)
+	}
+}
+
+func cacheKey(kind, period string, from, to time.Time, loc *time.Location, today string) string {
+	return kind + ":" + period + ":" +
+		strconv.FormatInt(from.Unix(), 10) + ":" + strconv.FormatInt(to.Unix(), 10) + ":" +
+		loc.String() + ":" + today
+}
@@ -0,0 +1,22 @@
+package calendar
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+type TaskRepository interface {
+	ListDated(ctx context.Context, ownerID string, from time.Time, limit uint64) ([]*domain.Task, error)
+}
+
+type LabelRepository interface {
+	ListByTasks(ctx context.Context, taskIDs []string) (map[string][]*domain.Label, error)
+}
+
+type FeedRepository interface {
+	Create(ctx context.Context, feed *domain.CalendarFeed) error
+	Active(ctx context.Context, id, ownerID string, now time.Time) (bool, error)
+	DeleteByOwner(ctx context.Context, ownerID string) error
+}
@@ -0,0 +1,166 @@
+package calendar
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/config"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	postgresrepo "gitlab.com/jodworkspace/mvp/internal/repository/postgres"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/jwtx"
+	"go.uber.org/zap"
+)
+
+// maxCalendarTasks bounds the number of tasks of a calendar.
+const maxCalendarTasks = 5000
+
+type UseCase struct {
+	tokenCfg    *config.TokenConfig
+	calendarCfg *config.CalendarConfig
+	taskRepo    TaskRepository
+	labelRepo   LabelRepository
+	feedRepo    FeedRepository
+	txManager   *postgresrepo.TransactionManager
+	logger      *logger.ZapLogger
+}
+
+func NewUseCase(
+	tokenCfg *config.TokenConfig,
+	calendarCfg *config.CalendarConfig,
+	taskRepo TaskRepository,
+	labelRepo LabelRepository,
+	feedRepo FeedRepository,
+	txManager *postgresrepo.TransactionManager,
+	zl *logger.ZapLogger,
+) *UseCase {
+	return &UseCase{
+		tokenCfg:    tokenCfg,
+		calendarCfg: calendarCfg,
+		taskRepo:    taskRepo,
+		labelRepo:   labelRepo,
+		feedRepo:    feedRepo,
+		txManager:   txManager,
+		logger:      zl,
+	}
+}
+
+// Tasks returns the dated tasks of ownerID shown in calendars: those due, or
+// starting when they have no due date, within the configured history or later.
+func (u *UseCase) Tasks(ctx context.Context, ownerID string) ([]*domain.Task, error) {
+	from := time.Now().UTC().Add(-u.calendarCfg.History)
+	tasks, err := u.taskRepo.ListDated(ctx, ownerID, from, maxCalendarTasks)
+	if err != nil {
+		u.logger.Error(
+			"calendarUseCase - taskRepo.ListDated",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	labels, err := u.labelRepo.ListByTasks(ctx, taskIDs)
+	if err != nil {
+		u.logger.Error("calendarUseCase - labelRepo.ListByTasks", zap.Error(err))
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		task.Labels = labels[task.ID]
+	}
+
+	return tasks, nil
+}
+
+// CreateFeed issues the calendar feed token of ownerID, revoking any
+// previous one.
+func (u *UseCase) CreateFeed(ctx context.Context, ownerID string) (*domain.CalendarFeed, error) {
+	now := time.Now().UTC()
+	feed := &domain.CalendarFeed{
+		ID:        uuid.NewString(),
+		OwnerID:   ownerID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(u.calendarCfg.FeedExpiry),
+	}
+
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		err := u.feedRepo.DeleteByOwner(ctx, ownerID)
+		if err != nil {
+			return err
+		}
+
+		return u.feedRepo.Create(ctx, feed)
+	})
+	if err != nil {
+		u.logger.Error(
+			"calendarUseCase - feedRepo.Create",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	feed.Token = jwtx.GenerateToken(
+		[]byte(u.tokenCfg.Secret),
+		u.calendarCfg.FeedExpiry,
+		jwtx.WithID(feed.ID),
+		jwtx.WithIssuer(u.tokenCfg.Issuer),
+		jwtx.WithSubject(ownerID),
+		jwtx.WithAudience(domain.CalendarFeedAudience),
+	)
+
+	return feed, nil
+}
+
+// RevokeFeed revokes the calendar feed token of ownerID, if any.
+func (u *UseCase) RevokeFeed(ctx context.Context, ownerID string) error {
+	err := u.feedRepo.DeleteByOwner(ctx, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"calendarUseCase - feedRepo.DeleteByOwner",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// FeedOwner returns the owner of a calendar feed token, or
+// errorx.ErrInvalidFeedToken when the token is invalid, expired or revoked.
+func (u *UseCase) FeedOwner(ctx context.Context, token string) (string, error) {
+	claims, err := jwtx.ParseToken(token, []byte(u.tokenCfg.Secret), u.tokenCfg.Issuer)
+	if err != nil || !slices.Contains(claims.Audience, domain.CalendarFeedAudience) {
+		return "", errorx.ErrInvalidFeedToken
+	}
+
+	active, err := u.feedRepo.Active(ctx, claims.ID, claims.Subject, time.Now().UTC())
+	if err != nil {
+		u.logger.Error(
+			"calendarUseCase - feedRepo.Active",
+			zap.String("owner_id", claims.Subject),
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	if !active {
+		return "", errorx.ErrInvalidFeedToken
+	}
+
+	return claims.Subject, nil
+}
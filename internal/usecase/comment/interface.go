@@ -0,0 +1,22 @@
+package comment
+
+import (
+	"context"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+type Repository interface {
+	ListThreads(ctx context.Context, taskID string, page, pageSize uint64) ([]*domain.Comment, error)
+	CountThreads(ctx context.Context, taskID string) (int64, error)
+	Get(ctx context.Context, id, taskID string) (*domain.Comment, error)
+	Create(ctx context.Context, comment *domain.Comment) error
+	UpdateBody(ctx context.Context, comment *domain.Comment) (*domain.Comment, error)
+	Delete(ctx context.Context, id, taskID string) error
+	CreateRevision(ctx context.Context, revision *domain.CommentRevision) error
+	ListRevisions(ctx context.Context, commentID string) ([]*domain.CommentRevision, error)
+}
+
+type TaskRepository interface {
+	Get(ctx context.Context, id, ownerID string) (*domain.Task, error)
+}
@@ -0,0 +1,315 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	postgresrepo "gitlab.com/jodworkspace/mvp/internal/repository/postgres"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/markdown"
+	"go.uber.org/zap"
+)
+
+type UseCase struct {
+	commentRepo Repository
+	taskRepo    TaskRepository
+	txManager   *postgresrepo.TransactionManager
+	logger      *logger.ZapLogger
+}
+
+func NewUseCase(
+	commentRepo Repository,
+	taskRepo TaskRepository,
+	txManager *postgresrepo.TransactionManager,
+	zl *logger.ZapLogger,
+) *UseCase {
+	return &UseCase{
+		commentRepo: commentRepo,
+		taskRepo:    taskRepo,
+		txManager:   txManager,
+		logger:      zl,
+	}
+}
+
+// List returns a page of the threads of a task of ownerID, oldest first, each
+// top-level comment carrying its replies, together with the number of threads.
+func (u *UseCase) List(ctx context.Context, taskID, ownerID string, page, pageSize uint64) ([]*domain.Comment, int64, error) {
+	err := u.checkTask(ctx, taskID, ownerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments, err := u.commentRepo.ListThreads(ctx, taskID, page, pageSize)
+	if err != nil {
+		u.logger.Error(
+			"commentUseCase - commentRepo.ListThreads",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return nil, 0, err
+	}
+
+	total, err := u.commentRepo.CountThreads(ctx, taskID)
+	if err != nil {
+		u.logger.Error(
+			"commentUseCase - commentRepo.CountThreads",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return nil, 0, err
+	}
+
+	err = render(comments...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return threads(comments), total, nil
+}
+
+// Create adds a comment by ownerID to one of their tasks, replying to
+// comment.ParentID when set.
+func (u *UseCase) Create(ctx context.Context, ownerID string, comment *domain.Comment) error {
+	err := u.checkTask(ctx, comment.TaskID, ownerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	comment.ID = uuid.NewString()
+	comment.ThreadID = comment.ID
+	comment.AuthorID = ownerID
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	if comment.ParentID != nil {
+		parent, err := u.commentRepo.Get(ctx, *comment.ParentID, comment.TaskID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return errorx.ErrParentCommentNotFound
+			}
+
+			u.logger.Error(
+				"commentUseCase - commentRepo.Get",
+				zap.String("comment_id", *comment.ParentID),
+				zap.Error(err),
+			)
+			return err
+		}
+		comment.ThreadID = parent.ThreadID
+	}
+
+	err = u.commentRepo.Create(ctx, comment)
+	if err != nil {
+		u.logger.Error(
+			"commentUseCase - commentRepo.Create",
+			zap.String("task_id", comment.TaskID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	comment.Replies = make([]*domain.Comment, 0)
+	return render(comment)
+}
+
+// Update replaces the body of a comment written by ownerID, keeping the
+// former body as a revision. Setting the same body again changes nothing.
+func (u *UseCase) Update(ctx context.Context, ownerID string, comment *domain.Comment) error {
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		current, err := u.authored(ctx, comment.ID, comment.TaskID, ownerID)
+		if err != nil {
+			return err
+		}
+
+		if current.Body == comment.Body {
+			*comment = *current
+			return nil
+		}
+
+		comment.UpdatedAt = time.Now().UTC()
+		err = u.commentRepo.CreateRevision(ctx, &domain.CommentRevision{
+			ID:        uuid.NewString(),
+			CommentID: current.ID,
+			Body:      current.Body,
+			CreatedAt: comment.UpdatedAt,
+		})
+		if err != nil {
+			u.logger.Error(
+				"commentUseCase - commentRepo.CreateRevision",
+				zap.String("comment_id", comment.ID),
+				zap.Error(err),
+			)
+			return err
+		}
+
+		updated, err := u.commentRepo.UpdateBody(ctx, comment)
+		if err != nil {
+			u.logger.Error(
+				"commentUseCase - commentRepo.UpdateBody",
+				zap.String("comment_id", comment.ID),
+				zap.Error(err),
+			)
+			return err
+		}
+
+		*comment = *updated
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	comment.Replies = make([]*domain.Comment, 0)
+	return render(comment)
+}
+
+// Delete removes a comment written by ownerID together with its replies.
+func (u *UseCase) Delete(ctx context.Context, id, taskID, ownerID string) error {
+	_, err := u.authored(ctx, id, taskID, ownerID)
+	if err != nil {
+		return err
+	}
+
+	err = u.commentRepo.Delete(ctx, id, taskID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrCommentNotFound
+		}
+
+		u.logger.Error(
+			"commentUseCase - commentRepo.Delete",
+			zap.String("comment_id", id),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Revisions returns the former bodies of a comment, newest first.
+func (u *UseCase) Revisions(ctx context.Context, id, taskID, ownerID string) ([]*domain.CommentRevision, error) {
+	_, err := u.get(ctx, id, taskID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := u.commentRepo.ListRevisions(ctx, id)
+	if err != nil {
+		u.logger.Error(
+			"commentUseCase - commentRepo.ListRevisions",
+			zap.String("comment_id", id),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	for _, revision := range revisions {
+		revision.HTML, err = markdown.Render(revision.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return revisions, nil
+}
+
+// checkTask makes sure the task exists, is not trashed and belongs to ownerID.
+func (u *UseCase) checkTask(ctx context.Context, taskID, ownerID string) error {
+	_, err := u.taskRepo.Get(ctx, taskID, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrTaskNotFound
+		}
+
+		u.logger.Error(
+			"commentUseCase - taskRepo.Get",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// get returns a comment of a task of ownerID.
+func (u *UseCase) get(ctx context.Context, id, taskID, ownerID string) (*domain.Comment, error) {
+	err := u.checkTask(ctx, taskID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := u.commentRepo.Get(ctx, id, taskID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorx.ErrCommentNotFound
+		}
+
+		u.logger.Error(
+			"commentUseCase - commentRepo.Get",
+			zap.String("comment_id", id),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// authored returns a comment of a task of ownerID written by ownerID.
+func (u *UseCase) authored(ctx context.Context, id, taskID, ownerID string) (*domain.Comment, error) {
+	comment, err := u.get(ctx, id, taskID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.AuthorID != ownerID {
+		return nil, errorx.ErrNotCommentAuthor
+	}
+
+	return comment, nil
+}
+
+// render fills in the HTML of comments from their Markdown bodies.
+func render(comments ...*domain.Comment) error {
+	var err error
+	for _, comment := range comments {
+		comment.HTML, err = markdown.Render(comment.Body)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// threads nests the comments of whole threads under their parents, keeping
+// their order, and returns the top-level ones.
+func threads(comments []*domain.Comment) []*domain.Comment {
+	byID := make(map[string]*domain.Comment, len(comments))
+	for _, comment := range comments {
+		comment.Replies = make([]*domain.Comment, 0)
+		byID[comment.ID] = comment
+	}
+
+	roots := make([]*domain.Comment, 0)
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			if parent, ok := byID[*comment.ParentID]; ok {
+				parent.Replies = append(parent.Replies, comment)
+				continue
+			}
+		}
+
+		roots = append(roots, comment)
+	}
+
+	return roots
+}
@@ -0,0 +1,21 @@
+package focus
+
+import (
+	"context"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+type Repository interface {
+	Create(ctx context.Context, session *domain.FocusSession) error
+	Stats(ctx context.Context, filter *domain.FocusStatsFilter) ([]*domain.FocusStats, error)
+}
+
+type TaskRepository interface {
+	Get(ctx context.Context, id, ownerID string) (*domain.Task, error)
+}
+
+// Publisher pushes an event to the live connections of a user.
+type Publisher interface {
+	Publish(userID, event string, data any)
+}
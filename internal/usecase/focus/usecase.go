@@ -0,0 +1,286 @@
+package focus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/config"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// maxStatsRange bounds the range of focus statistics.
+const maxStatsRange = 366 * 24 * time.Hour
+
+// UseCase runs the focus sessions of the users. Running sessions live in
+// memory, one per user, each driven by its own ticker, and are lost when the
+// server stops.
+type UseCase struct {
+	focusCfg    *config.FocusConfig
+	sessionRepo Repository
+	taskRepo    TaskRepository
+	publisher   Publisher
+	logger      *logger.ZapLogger
+
+	mu       sync.Mutex
+	sessions map[string]*running
+}
+
+// running is a session in progress, stopped by closing stop.
+type running struct {
+	session *domain.FocusSession
+	stop    chan struct{}
+}
+
+func NewUseCase(
+	focusCfg *config.FocusConfig,
+	sessionRepo Repository,
+	taskRepo TaskRepository,
+	publisher Publisher,
+	zl *logger.ZapLogger,
+) *UseCase {
+	return &UseCase{
+		focusCfg:    focusCfg,
+		sessionRepo: sessionRepo,
+		taskRepo:    taskRepo,
+		publisher:   publisher,
+		logger:      zl,
+		sessions:    make(map[string]*running),
+	}
+}
+
+// Current returns the running session of ownerID.
+func (u *UseCase) Current(ownerID string) (*domain.FocusSession, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	r, ok := u.sessions[ownerID]
+	if !ok {
+		return nil, errorx.ErrNoFocusSession
+	}
+
+	return snapshot(r.session), nil
+}
+
+// Start starts a session on a task of ownerID. Lengths left nil fall back to
+// the configured ones, and a zero break skips the break.
+func (u *UseCase) Start(ctx context.Context, taskID, ownerID string, work, brk *time.Duration) (*domain.FocusSession, error) {
+	_, err := u.taskRepo.Get(ctx, taskID, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorx.ErrTaskNotFound
+		}
+
+		u.logger.Error(
+			"focusUseCase - taskRepo.Get",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	workLength, breakLength := u.focusCfg.WorkLength, u.focusCfg.BreakLength
+	if work != nil {
+		workLength = *work
+	}
+	if brk != nil {
+		breakLength = *brk
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	phaseEndsAt := now.Add(workLength)
+	r := &running{
+		session: &domain.FocusSession{
+			ID:           uuid.NewString(),
+			TaskID:       taskID,
+			OwnerID:      ownerID,
+			Phase:        domain.FocusPhaseWork,
+			PhaseEndsAt:  &phaseEndsAt,
+			WorkSeconds:  int(workLength / time.Second),
+			BreakSeconds: int(breakLength / time.Second),
+			StartedAt:    now,
+		},
+		stop: make(chan struct{}),
+	}
+
+	u.mu.Lock()
+	if _, ok := u.sessions[ownerID]; ok {
+		u.mu.Unlock()
+		return nil, errorx.ErrFocusSessionRunning
+	}
+	u.sessions[ownerID] = r
+	session := snapshot(r.session)
+	u.mu.Unlock()
+
+	u.publisher.Publish(ownerID, domain.FocusEventPhase, session)
+	go u.run(context.WithoutCancel(ctx), r)
+
+	return session, nil
+}
+
+// Interrupt counts an interruption of the running session of ownerID.
+func (u *UseCase) Interrupt(ownerID string) (*domain.FocusSession, error) {
+	u.mu.Lock()
+	r, ok := u.sessions[ownerID]
+	if !ok {
+		u.mu.Unlock()
+		return nil, errorx.ErrNoFocusSession
+	}
+	r.session.Interruptions++
+	session := snapshot(r.session)
+	u.mu.Unlock()
+
+	u.publisher.Publish(ownerID, domain.FocusEventInterrupted, session)
+	return session, nil
+}
+
+// Stop ends the running session of ownerID. A session stopped while working
+// is saved as not completed, with the time focused so far.
+func (u *UseCase) Stop(ctx context.Context, ownerID string) (*domain.FocusSession, error) {
+	u.mu.Lock()
+	r, ok := u.sessions[ownerID]
+	if !ok {
+		u.mu.Unlock()
+		return nil, errorx.ErrNoFocusSession
+	}
+	delete(u.sessions, ownerID)
+	close(r.stop)
+
+	working := r.session.Phase == domain.FocusPhaseWork
+	now := time.Now().UTC().Truncate(time.Second)
+	if working {
+		r.session.EndedAt = &now
+		r.session.FocusedSeconds = int(now.Sub(r.session.StartedAt) / time.Second)
+	}
+	r.session.Phase, r.session.PhaseEndsAt = domain.FocusPhaseDone, nil
+	session := snapshot(r.session)
+	u.mu.Unlock()
+
+	u.publisher.Publish(ownerID, domain.FocusEventPhase, session)
+	if working {
+		err := u.save(ctx, session)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return session, nil
+}
+
+// Stats sums up the sessions of a user by day or week and task over a range
+// of at most a year.
+func (u *UseCase) Stats(ctx context.Context, filter *domain.FocusStatsFilter) ([]*domain.FocusStats, error) {
+	if !filter.To.After(filter.From) || filter.To.Sub(filter.From) > maxStatsRange {
+		return nil, errorx.ErrInvalidReportRange
+	}
+
+	stats, err := u.sessionRepo.Stats(ctx, filter)
+	if err != nil {
+		u.logger.Error(
+			"focusUseCase - sessionRepo.Stats",
+			zap.String("owner_id", filter.OwnerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// run ticks the session until it is done or stopped.
+func (u *UseCase) run(ctx context.Context, r *running) {
+	ticker := time.NewTicker(u.focusCfg.TickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case now := <-ticker.C:
+			if u.tick(ctx, r, now.UTC()) {
+				return
+			}
+		}
+	}
+}
+
+// tick pushes the remaining time of the current phase, or moves the session
+// to the next phase once it is over, saving the session at the end of the
+// work phase. It reports whether the session is done.
+func (u *UseCase) tick(ctx context.Context, r *running, now time.Time) bool {
+	u.mu.Lock()
+	select {
+	case <-r.stop:
+		u.mu.Unlock()
+		return true
+	default:
+	}
+
+	s := r.session
+	if remaining := s.PhaseEndsAt.Sub(now); remaining > 0 {
+		tick := &domain.FocusTick{
+			Session:          snapshot(s),
+			RemainingSeconds: int((remaining + time.Second - 1) / time.Second),
+		}
+		u.mu.Unlock()
+
+		u.publisher.Publish(s.OwnerID, domain.FocusEventTick, tick)
+		return false
+	}
+
+	var finished *domain.FocusSession
+	if s.Phase == domain.FocusPhaseWork {
+		endedAt := *s.PhaseEndsAt
+		s.EndedAt = &endedAt
+		s.FocusedSeconds = s.WorkSeconds
+		s.Completed = true
+		finished = snapshot(s)
+	}
+
+	done := s.Phase == domain.FocusPhaseBreak || s.BreakSeconds == 0
+	if done {
+		s.Phase, s.PhaseEndsAt = domain.FocusPhaseDone, nil
+		delete(u.sessions, s.OwnerID)
+	} else {
+		breakEndsAt := s.PhaseEndsAt.Add(time.Duration(s.BreakSeconds) * time.Second)
+		s.Phase, s.PhaseEndsAt = domain.FocusPhaseBreak, &breakEndsAt
+	}
+	session := snapshot(s)
+	u.mu.Unlock()
+
+	u.publisher.Publish(session.OwnerID, domain.FocusEventPhase, session)
+	if finished != nil {
+		// The session is already over for the user, so a failed save is only
+		// logged.
+		_ = u.save(ctx, finished)
+	}
+
+	return done
+}
+
+func (u *UseCase) save(ctx context.Context, session *domain.FocusSession) error {
+	err := u.sessionRepo.Create(ctx, session)
+	if err != nil {
+		u.logger.Error(
+			"focusUseCase - sessionRepo.Create",
+			zap.String("session_id", session.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// snapshot copies a session so it can be handed out while the original keeps
+// changing.
+func snapshot(s *domain.FocusSession) *domain.FocusSession {
+	c := *s
+	return &c
+}
@@ -0,0 +1,15 @@
+package label
+
+import (
+	"context"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+type Repository interface {
+	List(ctx context.Context, ownerID string) ([]*domain.Label, error)
+	Get(ctx context.Context, id, ownerID string) (*domain.Label, error)
+	Create(ctx context.Context, label *domain.Label) error
+	Update(ctx context.Context, label *domain.Label) (*domain.Label, error)
+	Delete(ctx context.Context, id, ownerID string) error
+}
@@ -0,0 +1,122 @@
+package label
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+type UseCase struct {
+	labelRepo Repository
+	logger    *logger.ZapLogger
+}
+
+func NewUseCase(labelRepo Repository, zl *logger.ZapLogger) *UseCase {
+	return &UseCase{
+		labelRepo: labelRepo,
+		logger:    zl,
+	}
+}
+
+func (u *UseCase) List(ctx context.Context, ownerID string) ([]*domain.Label, error) {
+	labels, err := u.labelRepo.List(ctx, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"labelUseCase - labelRepo.List",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+func (u *UseCase) Get(ctx context.Context, id, ownerID string) (*domain.Label, error) {
+	label, err := u.labelRepo.Get(ctx, id, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"labelUseCase - labelRepo.Get",
+			zap.String("label_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorx.ErrLabelNotFound
+		}
+
+		return nil, err
+	}
+
+	return label, nil
+}
+
+func (u *UseCase) Create(ctx context.Context, label *domain.Label) error {
+	now := time.Now().UTC()
+	label.ID = uuid.NewString()
+	label.CreatedAt = now
+	label.UpdatedAt = now
+
+	err := u.labelRepo.Create(ctx, label)
+	if err != nil {
+		u.logger.Error(
+			"labelUseCase - labelRepo.Create",
+			zap.String("owner_id", label.OwnerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (u *UseCase) Update(ctx context.Context, label *domain.Label) error {
+	label.UpdatedAt = time.Now().UTC()
+
+	updated, err := u.labelRepo.Update(ctx, label)
+	if err != nil {
+		u.logger.Error(
+			"labelUseCase - labelRepo.Update",
+			zap.String("label_id", label.ID),
+			zap.String("owner_id", label.OwnerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrLabelNotFound
+		}
+
+		return err
+	}
+
+	*label = *updated
+	return nil
+}
+
+func (u *UseCase) Delete(ctx context.Context, id, ownerID string) error {
+	err := u.labelRepo.Delete(ctx, id, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"labelUseCase - labelRepo.Delete",
+			zap.String("label_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrLabelNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
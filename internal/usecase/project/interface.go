@@ -0,0 +1,21 @@
+package project
+
+import (
+	"context"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+type Repository interface {
+	List(ctx context.Context, ownerID string, includeArchived bool) ([]*domain.Project, error)
+	Get(ctx context.Context, id, ownerID string) (*domain.Project, error)
+	Create(ctx context.Context, project *domain.Project) error
+	Update(ctx context.Context, project *domain.Project) error
+	Delete(ctx context.Context, id, ownerID string) error
+
+	ListStatuses(ctx context.Context, projectID, ownerID string) ([]*domain.ProjectStatus, error)
+	GetStatus(ctx context.Context, id, projectID, ownerID string) (*domain.ProjectStatus, error)
+	CreateStatus(ctx context.Context, status *domain.ProjectStatus) error
+	UpdateStatus(ctx context.Context, status *domain.ProjectStatus) (*domain.ProjectStatus, error)
+	DeleteStatus(ctx context.Context, id, projectID, ownerID string) error
+}
@@ -0,0 +1,170 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/lexorank"
+	"go.uber.org/zap"
+)
+
+// ListStatuses returns the board columns of a project of ownerID in order.
+func (u *UseCase) ListStatuses(ctx context.Context, projectID, ownerID string) ([]*domain.ProjectStatus, error) {
+	_, err := u.Get(ctx, projectID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := u.projectRepo.ListStatuses(ctx, projectID, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"projectUseCase - projectRepo.ListStatuses",
+			zap.String("project_id", projectID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// CreateStatus adds a board column to a project at the given placement.
+func (u *UseCase) CreateStatus(ctx context.Context, status *domain.ProjectStatus, placement *domain.Placement) error {
+	statuses, err := u.ListStatuses(ctx, status.ProjectID, status.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	status.ID = uuid.NewString()
+	status.CreatedAt = now
+	status.UpdatedAt = now
+	status.Position, err = statusPosition(statuses, status.ID, placement)
+	if err != nil {
+		return err
+	}
+
+	err = u.projectRepo.CreateStatus(ctx, status)
+	if err != nil {
+		u.logger.Error(
+			"projectUseCase - projectRepo.CreateStatus",
+			zap.String("project_id", status.ProjectID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStatus replaces the name, color and done flag of a board column, and
+// moves it when a placement is given.
+func (u *UseCase) UpdateStatus(ctx context.Context, status *domain.ProjectStatus, placement *domain.Placement) error {
+	statuses, err := u.ListStatuses(ctx, status.ProjectID, status.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(statuses, func(s *domain.ProjectStatus) bool { return s.ID == status.ID })
+	if i < 0 {
+		return errorx.ErrStatusNotFound
+	}
+
+	status.Position = statuses[i].Position
+	if placement != nil {
+		status.Position, err = statusPosition(statuses, status.ID, placement)
+		if err != nil {
+			return err
+		}
+	}
+	status.UpdatedAt = time.Now().UTC()
+
+	updated, err := u.projectRepo.UpdateStatus(ctx, status)
+	if err != nil {
+		u.logger.Error(
+			"projectUseCase - projectRepo.UpdateStatus",
+			zap.String("status_id", status.ID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrStatusNotFound
+		}
+
+		return err
+	}
+
+	*status = *updated
+	return nil
+}
+
+// DeleteStatus removes a board column. Its tasks are left without status.
+func (u *UseCase) DeleteStatus(ctx context.Context, id, projectID, ownerID string) error {
+	err := u.projectRepo.DeleteStatus(ctx, id, projectID, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrStatusNotFound
+		}
+
+		u.logger.Error(
+			"projectUseCase - projectRepo.DeleteStatus",
+			zap.String("status_id", id),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// statusPosition returns the position of the status id among statuses for
+// the given placement.
+func statusPosition(statuses []*domain.ProjectStatus, id string, placement *domain.Placement) (string, error) {
+	others := slices.DeleteFunc(slices.Clone(statuses), func(s *domain.ProjectStatus) bool { return s.ID == id })
+	indexOf := func(id string) int {
+		return slices.IndexFunc(others, func(s *domain.ProjectStatus) bool { return s.ID == id })
+	}
+	positionAt := func(i int) string {
+		if i < 0 || i >= len(others) {
+			return ""
+		}
+		return others[i].Position
+	}
+
+	var lo, hi string
+	switch {
+	case placement.AfterID != nil && placement.BeforeID != nil:
+		after, before := indexOf(*placement.AfterID), indexOf(*placement.BeforeID)
+		if after < 0 || before < 0 {
+			return "", errorx.ErrInvalidPlacement
+		}
+		lo, hi = positionAt(after), positionAt(before)
+	case placement.AfterID != nil:
+		after := indexOf(*placement.AfterID)
+		if after < 0 {
+			return "", errorx.ErrInvalidPlacement
+		}
+		lo, hi = positionAt(after), positionAt(after+1)
+	case placement.BeforeID != nil:
+		before := indexOf(*placement.BeforeID)
+		if before < 0 {
+			return "", errorx.ErrInvalidPlacement
+		}
+		lo, hi = positionAt(before-1), positionAt(before)
+	default:
+		return lexorank.Next(positionAt(len(others) - 1))
+	}
+
+	position, err := lexorank.Between(lo, hi)
+	if err != nil {
+		return "", errorx.ErrInvalidPlacement
+	}
+
+	return position, nil
+}
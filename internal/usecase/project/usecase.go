@@ -0,0 +1,129 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+type UseCase struct {
+	projectRepo Repository
+	logger      *logger.ZapLogger
+}
+
+func NewUseCase(projectRepo Repository, zl *logger.ZapLogger) *UseCase {
+	return &UseCase{
+		projectRepo: projectRepo,
+		logger:      zl,
+	}
+}
+
+func (u *UseCase) List(ctx context.Context, ownerID string, includeArchived bool) ([]*domain.Project, error) {
+	projects, err := u.projectRepo.List(ctx, ownerID, includeArchived)
+	if err != nil {
+		u.logger.Error(
+			"projectUseCase - projectRepo.List",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (u *UseCase) Get(ctx context.Context, id, ownerID string) (*domain.Project, error) {
+	project, err := u.projectRepo.Get(ctx, id, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"projectUseCase - projectRepo.Get",
+			zap.String("project_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorx.ErrProjectNotFound
+		}
+
+		return nil, err
+	}
+
+	return project, nil
+}
+
+func (u *UseCase) Create(ctx context.Context, project *domain.Project) error {
+	now := time.Now().UTC()
+	project.ID = uuid.NewString()
+	project.CreatedAt = now
+	project.UpdatedAt = now
+
+	err := u.projectRepo.Create(ctx, project)
+	if err != nil {
+		u.logger.Error(
+			"projectUseCase - projectRepo.Create",
+			zap.String("owner_id", project.OwnerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Update replaces the mutable fields of a project and refreshes it, task
+// counts included.
+func (u *UseCase) Update(ctx context.Context, project *domain.Project) error {
+	project.UpdatedAt = time.Now().UTC()
+
+	err := u.projectRepo.Update(ctx, project)
+	if err != nil {
+		u.logger.Error(
+			"projectUseCase - projectRepo.Update",
+			zap.String("project_id", project.ID),
+			zap.String("owner_id", project.OwnerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrProjectNotFound
+		}
+
+		return err
+	}
+
+	updated, err := u.Get(ctx, project.ID, project.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	*project = *updated
+	return nil
+}
+
+func (u *UseCase) Delete(ctx context.Context, id, ownerID string) error {
+	err := u.projectRepo.Delete(ctx, id, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"projectUseCase - projectRepo.Delete",
+			zap.String("project_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrProjectNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
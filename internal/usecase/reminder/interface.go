@@ -0,0 +1,39 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+type Repository interface {
+	List(ctx context.Context, taskID, ownerID string) ([]*domain.Reminder, error)
+	Get(ctx context.Context, id, taskID, ownerID string) (*domain.Reminder, error)
+	Create(ctx context.Context, reminder *domain.Reminder) error
+	Delete(ctx context.Context, id, taskID, ownerID string) error
+	ClaimDue(ctx context.Context, now time.Time) (*domain.Reminder, error)
+	UpdateDelivery(ctx context.Context, reminder *domain.Reminder) error
+	CreateDelivery(ctx context.Context, delivery *domain.ReminderDelivery) error
+	ListDeliveries(ctx context.Context, reminderID string) ([]*domain.ReminderDelivery, error)
+	SentChannels(ctx context.Context, reminderID string) ([]string, error)
+}
+
+type TaskRepository interface {
+	Get(ctx context.Context, id, ownerID string) (*domain.Task, error)
+}
+
+type UserRepository interface {
+	Get(ctx context.Context, id string) (*domain.User, error)
+}
+
+// Notifier delivers reminders through a channel.
+type Notifier interface {
+	Channel() string
+	Notify(ctx context.Context, notification *domain.Notification) error
+}
+
+// Publisher pushes an event to the live connections of a user.
+type Publisher interface {
+	Publish(userID, event string, data any)
+}
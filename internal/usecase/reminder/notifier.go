@@ -0,0 +1,213 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/config"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook payload, hex encoded
+// and prefixed with "sha256=".
+const SignatureHeader = "X-Jod-Signature"
+
+// emailTimeout bounds the whole SMTP exchange of an email.
+const emailTimeout = 30 * time.Second
+
+// WSNotifier pushes reminders to the open WebSocket connections of their
+// owner. Owners without a connection miss them.
+type WSNotifier struct {
+	publisher Publisher
+}
+
+func NewWSNotifier(publisher Publisher) *WSNotifier {
+	return &WSNotifier{
+		publisher: publisher,
+	}
+}
+
+func (n *WSNotifier) Channel() string {
+	return domain.ReminderChannelWS
+}
+
+func (n *WSNotifier) Notify(_ context.Context, notification *domain.Notification) error {
+	n.publisher.Publish(notification.Reminder.OwnerID, domain.ReminderEvent, notification)
+	return nil
+}
+
+// EmailNotifier mails reminders to their owner over SMTP, upgrading to TLS
+// when the server offers STARTTLS.
+type EmailNotifier struct {
+	smtpCfg *config.SMTPConfig
+}
+
+func NewEmailNotifier(smtpCfg *config.SMTPConfig) *EmailNotifier {
+	return &EmailNotifier{
+		smtpCfg: smtpCfg,
+	}
+}
+
+func (n *EmailNotifier) Channel() string {
+	return domain.ReminderChannelEmail
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, notification *domain.Notification) error {
+	if notification.Email == "" {
+		return errors.New("the owner has no email address")
+	}
+
+	var dialer net.Dialer
+	addr := net.JoinHostPort(n.smtpCfg.Host, strconv.Itoa(int(n.smtpCfg.Port)))
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	err = conn.SetDeadline(time.Now().Add(emailTimeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, n.smtpCfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		err = c.StartTLS(&tls.Config{ServerName: n.smtpCfg.Host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if n.smtpCfg.Username != "" {
+		err = c.Auth(smtp.PlainAuth("", n.smtpCfg.Username, n.smtpCfg.Password, n.smtpCfg.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.Mail(n.smtpCfg.From)
+	if err != nil {
+		return err
+	}
+
+	err = c.Rcpt(notification.Email)
+	if err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(n.message(notification))
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// message builds the plain text email of a notification. The task title is
+// encoded in the subject so it can not inject headers.
+func (n *EmailNotifier) message(notification *domain.Notification) []byte {
+	task := notification.Task
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.smtpCfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", notification.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "Reminder: "+task.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(task.Title, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	if task.DueDate != nil {
+		fmt.Fprintf(&b, "\r\nDue %s\r\n", task.DueDate.UTC().Format(time.RFC1123))
+	}
+
+	return []byte(b.String())
+}
+
+// WebhookNotifier posts reminders as JSON to the webhook URL of each
+// reminder, signed with secret when one is set. Any status other than 2xx,
+// redirects included, fails the delivery. The client is expected to come
+// from NewWebhookClient.
+type WebhookNotifier struct {
+	client httpx.Client
+	secret string
+}
+
+func NewWebhookNotifier(client httpx.Client, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		client: client,
+		secret: secret,
+	}
+}
+
+func (n *WebhookNotifier) Channel() string {
+	return domain.ReminderChannelWebhook
+}
+
+type webhookPayload struct {
+	Event string `json:"event"`
+	*domain.Notification
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, notification *domain.Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:        domain.ReminderEvent,
+		Notification: notification,
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(body)
+		headers.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.DoRequest(ctx, http.MethodPost, notification.Reminder.WebhookURL, bytes.NewReader(body), headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &webhookStatusError{status: resp.Status, code: resp.StatusCode}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Run delivers the due reminders every poll interval until ctx is done.
+// Every reminder is claimed with SKIP LOCKED in a transaction of its own and
+// stays locked while it is delivered, so several instances can run the
+// scheduler without firing a reminder twice.
+func (u *UseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.reminderCfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for range u.reminderCfg.BatchSize {
+			claimed, err := u.deliverNext(ctx)
+			if err != nil || !claimed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverNext delivers the reminder that has been due the longest, telling
+// whether there was one.
+func (u *UseCase) deliverNext(ctx context.Context) (bool, error) {
+	claimed := false
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		now := time.Now().UTC()
+		reminder, err := u.reminderRepo.ClaimDue(ctx, now)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			u.logger.Error("reminderUseCase - reminderRepo.ClaimDue", zap.Error(err))
+			return err
+		}
+
+		claimed = true
+		return u.deliver(ctx, reminder, now)
+	})
+
+	return claimed, err
+}
+
+// deliver notifies through every channel of a reminder not delivered by a
+// previous attempt and logs the outcome of each. A reminder with failed
+// channels is retried with an exponential backoff until it runs out of
+// attempts.
+func (u *UseCase) deliver(ctx context.Context, reminder *domain.Reminder, now time.Time) error {
+	task, err := u.taskRepo.Get(ctx, reminder.TaskID, reminder.OwnerID)
+	if err != nil {
+		u.logger.Error(
+			"reminderUseCase - taskRepo.Get",
+			zap.String("task_id", reminder.TaskID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	sent, err := u.reminderRepo.SentChannels(ctx, reminder.ID)
+	if err != nil {
+		u.logger.Error(
+			"reminderUseCase - reminderRepo.SentChannels",
+			zap.String("reminder_id", reminder.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	notification := &domain.Notification{
+		Reminder: reminder,
+		Task:     task,
+	}
+	if slices.Contains(reminder.Channels, domain.ReminderChannelEmail) && !slices.Contains(sent, domain.ReminderChannelEmail) {
+		user, err := u.userRepo.Get(ctx, reminder.OwnerID)
+		if err != nil {
+			u.logger.Error(
+				"reminderUseCase - userRepo.Get",
+				zap.String("user_id", reminder.OwnerID),
+				zap.Error(err),
+			)
+			return err
+		}
+		notification.Email = user.Email
+	}
+
+	reminder.Attempts++
+	failed := false
+	for _, channel := range reminder.Channels {
+		if slices.Contains(sent, channel) {
+			continue
+		}
+
+		delivery := &domain.ReminderDelivery{
+			ID:         uuid.NewString(),
+			ReminderID: reminder.ID,
+			Channel:    channel,
+			Attempt:    reminder.Attempts,
+			Status:     domain.ReminderStatusSent,
+			CreatedAt:  time.Now().UTC(),
+		}
+
+		err = u.notify(ctx, channel, notification)
+		if err != nil {
+			u.logger.Warn(
+				"reminderUseCase - notify",
+				zap.String("reminder_id", reminder.ID),
+				zap.String("channel", channel),
+				zap.Int("attempt", reminder.Attempts),
+				zap.Error(err),
+			)
+			delivery.Status = domain.ReminderStatusFailed
+			delivery.Error = deliveryError(err)
+			failed = true
+		}
+
+		err = u.reminderRepo.CreateDelivery(ctx, delivery)
+		if err != nil {
+			u.logger.Error(
+				"reminderUseCase - reminderRepo.CreateDelivery",
+				zap.String("reminder_id", reminder.ID),
+				zap.Error(err),
+			)
+			return err
+		}
+	}
+
+	switch {
+	case !failed:
+		reminder.Status = domain.ReminderStatusSent
+		reminder.SentAt = &now
+		reminder.NextAttemptAt = nil
+	case reminder.Attempts >= u.reminderCfg.MaxAttempts:
+		reminder.Status = domain.ReminderStatusFailed
+		reminder.NextAttemptAt = nil
+	default:
+		next := now.Add(u.reminderCfg.RetryBackoff << min(reminder.Attempts-1, 16))
+		reminder.NextAttemptAt = &next
+	}
+	reminder.UpdatedAt = now
+
+	err = u.reminderRepo.UpdateDelivery(ctx, reminder)
+	if err != nil {
+		u.logger.Error(
+			"reminderUseCase - reminderRepo.UpdateDelivery",
+			zap.String("reminder_id", reminder.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// notify delivers a notification through channel, which may have been
+// disabled since the reminder was created.
+func (u *UseCase) notify(ctx context.Context, channel string, notification *domain.Notification) error {
+	notifier, ok := u.notifiers[channel]
+	if !ok {
+		return fmt.Errorf("channel %q is not enabled", channel)
+	}
+
+	return notifier.Notify(ctx, notification)
+}
@@ -0,0 +1,208 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/config"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	postgresrepo "gitlab.com/jodworkspace/mvp/internal/repository/postgres"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+type UseCase struct {
+	reminderCfg  *config.ReminderConfig
+	reminderRepo Repository
+	taskRepo     TaskRepository
+	userRepo     UserRepository
+	txManager    *postgresrepo.TransactionManager
+	notifiers    map[string]Notifier
+	logger       *logger.ZapLogger
+}
+
+// NewUseCase creates the reminder use case, delivering through notifiers.
+// Reminders can only use the channels of the given notifiers.
+func NewUseCase(
+	reminderCfg *config.ReminderConfig,
+	reminderRepo Repository,
+	taskRepo TaskRepository,
+	userRepo UserRepository,
+	txManager *postgresrepo.TransactionManager,
+	zl *logger.ZapLogger,
+	notifiers ...Notifier,
+) *UseCase {
+	byChannel := make(map[string]Notifier, len(notifiers))
+	for _, notifier := range notifiers {
+		byChannel[notifier.Channel()] = notifier
+	}
+
+	return &UseCase{
+		reminderCfg:  reminderCfg,
+		reminderRepo: reminderRepo,
+		taskRepo:     taskRepo,
+		userRepo:     userRepo,
+		txManager:    txManager,
+		notifiers:    byChannel,
+		logger:       zl,
+	}
+}
+
+// List returns the reminders of a task of ownerID.
+func (u *UseCase) List(ctx context.Context, taskID, ownerID string) ([]*domain.Reminder, error) {
+	_, err := u.getTask(ctx, taskID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders, err := u.reminderRepo.List(ctx, taskID, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"reminderUseCase - reminderRepo.List",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return reminders, nil
+}
+
+// Create adds a reminder to a task, due either at RemindAt or OffsetMinutes
+// before the due date of the task.
+func (u *UseCase) Create(ctx context.Context, reminder *domain.Reminder) error {
+	if (reminder.RemindAt == nil) == (reminder.OffsetMinutes == nil) {
+		return errorx.ErrInvalidReminder
+	}
+
+	channels := make([]string, 0, len(reminder.Channels))
+	for _, channel := range reminder.Channels {
+		if _, ok := u.notifiers[channel]; !ok {
+			return errorx.ErrUnsupportedChannel
+		}
+		if !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	if slices.Contains(channels, domain.ReminderChannelWebhook) {
+		if reminder.WebhookURL == "" {
+			return errorx.ErrWebhookURLRequired
+		}
+		err := checkWebhookURL(ctx, reminder.WebhookURL)
+		if err != nil {
+			return err
+		}
+	} else {
+		reminder.WebhookURL = ""
+	}
+
+	task, err := u.getTask(ctx, reminder.TaskID, reminder.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	if reminder.OffsetMinutes != nil && task.DueDate == nil {
+		return errorx.ErrReminderWithoutDueDate
+	}
+
+	now := time.Now().UTC()
+	if reminder.RemindAt != nil {
+		remindAt := reminder.RemindAt.UTC().Truncate(time.Second)
+		reminder.RemindAt = &remindAt
+	}
+	reminder.ID = uuid.NewString()
+	reminder.Channels = channels
+	reminder.Status = domain.ReminderStatusPending
+	reminder.Attempts = 0
+	reminder.NextAttemptAt = nil
+	reminder.SentAt = nil
+	reminder.CreatedAt = now
+	reminder.UpdatedAt = now
+
+	err = u.reminderRepo.Create(ctx, reminder)
+	if err != nil {
+		if errors.Is(err, errorx.ErrTaskNotFound) {
+			return err
+		}
+
+		u.logger.Error(
+			"reminderUseCase - reminderRepo.Create",
+			zap.String("task_id", reminder.TaskID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (u *UseCase) Delete(ctx context.Context, id, taskID, ownerID string) error {
+	err := u.reminderRepo.Delete(ctx, id, taskID, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrReminderNotFound
+		}
+
+		u.logger.Error(
+			"reminderUseCase - reminderRepo.Delete",
+			zap.String("reminder_id", id),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Deliveries returns the delivery log of a reminder of a task of ownerID.
+func (u *UseCase) Deliveries(ctx context.Context, id, taskID, ownerID string) ([]*domain.ReminderDelivery, error) {
+	_, err := u.reminderRepo.Get(ctx, id, taskID, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorx.ErrReminderNotFound
+		}
+
+		u.logger.Error(
+			"reminderUseCase - reminderRepo.Get",
+			zap.String("reminder_id", id),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	deliveries, err := u.reminderRepo.ListDeliveries(ctx, id)
+	if err != nil {
+		u.logger.Error(
+			"reminderUseCase - reminderRepo.ListDeliveries",
+			zap.String("reminder_id", id),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (u *UseCase) getTask(ctx context.Context, taskID, ownerID string) (*domain.Task, error) {
+	task, err := u.taskRepo.Get(ctx, taskID, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorx.ErrTaskNotFound
+		}
+
+		u.logger.Error(
+			"reminderUseCase - taskRepo.Get",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return task, nil
+}
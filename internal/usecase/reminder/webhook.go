@@ -0,0 +1,148 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	otelhttp "gitlab.com/jodworkspace/mvp/pkg/otel/http"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/httpx"
+)
+
+// nonPublicPrefixes are the ranges not covered by the checks of netip.Addr
+// that still do not reach the public internet.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// NewWebhookClient returns the client webhooks are posted with. It only
+// dials public addresses, checked on the address actually dialed so that a
+// host resolving elsewhere after the reminder was created is still refused,
+// and it does not follow redirects.
+func NewWebhookClient(timeout time.Duration) httpx.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !publicAddr(addrPort.Addr()) {
+				return errorx.ErrWebhookURLNotAllowed
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return httpx.NewHTTPClient(http.Client{
+		Timeout:   timeout,
+		Transport: otelhttp.WithTracing(transport),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	})
+}
+
+// checkWebhookURL refuses a webhook URL whose host does not resolve, or
+// resolves to an address that is not public.
+func checkWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return errorx.ErrWebhookURLNotAllowed
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return errorx.ErrWebhookURLNotAllowed
+	}
+
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return errorx.ErrWebhookURLNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// publicAddr reports whether addr is a global unicast address outside of the
+// private, unique local and other special purpose ranges.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// webhookStatusError reports a webhook answering with a status other than
+// 2xx.
+type webhookStatusError struct {
+	status string
+	code   int
+}
+
+func (e *webhookStatusError) Error() string {
+	return "webhook responded with " + e.status
+}
+
+// Failure categories of a delivery, recorded instead of the error itself so
+// that the delivery log does not echo what a webhook endpoint or the network
+// answered.
+const (
+	deliveryBlockedAddress   = "blocked_address"
+	deliveryTimeout          = "timeout"
+	deliveryConnectionFailed = "connection_failed"
+	deliveryClientError      = "http_4xx"
+	deliveryServerError      = "http_5xx"
+	deliveryUnexpectedStatus = "unexpected_status"
+	deliveryFailed           = "failed"
+)
+
+// deliveryError returns the failure category of a delivery error.
+func deliveryError(err error) string {
+	var (
+		statusErr *webhookStatusError
+		netErr    net.Error
+		opErr     *net.OpError
+		dnsErr    *net.DNSError
+	)
+	switch {
+	case errors.Is(err, errorx.ErrWebhookURLNotAllowed):
+		return deliveryBlockedAddress
+	case errors.As(err, &statusErr):
+		switch {
+		case statusErr.code >= 500:
+			return deliveryServerError
+		case statusErr.code >= 400:
+			return deliveryClientError
+		}
+		return deliveryUnexpectedStatus
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return deliveryTimeout
+	case errors.As(err, &opErr), errors.As(err, &dnsErr):
+		return deliveryConnectionFailed
+	}
+
+	return deliveryFailed
+}
@@ -0,0 +1,259 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/rrulex"
+	"go.uber.org/zap"
+)
+
+// maxAgendaDays bounds the number of days of an agenda.
+const maxAgendaDays = 92
+
+// Agenda lists, day by day, what ownerID has on in [from, to), two midnights
+// in loc: the tasks planned, starting or due on each day, the occurrences of
+// recurring tasks that have no task yet and, on today, the overdue tasks.
+func (u *UseCase) Agenda(ctx context.Context, ownerID string, from, to time.Time, loc *time.Location) ([]*domain.AgendaDay, error) {
+	if !to.After(from) || to.After(from.In(loc).AddDate(0, 0, maxAgendaDays)) {
+		return nil, errorx.ErrInvalidAgendaRange
+	}
+
+	days := make([]*domain.AgendaDay, 0)
+	byDate := make(map[string]*domain.AgendaDay)
+	for day := from.In(loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		agendaDay := &domain.AgendaDay{
+			Date:        day.Format(time.DateOnly),
+			Overdue:     make([]*domain.Task, 0),
+			Planned:     make([]*domain.Task, 0),
+			Starting:    make([]*domain.Task, 0),
+			Due:         make([]*domain.Task, 0),
+			Occurrences: make([]*domain.AgendaOccurrence, 0),
+		}
+		days = append(days, agendaDay)
+		byDate[agendaDay.Date] = agendaDay
+	}
+
+	dayOf := func(t *time.Time) *domain.AgendaDay {
+		if t == nil {
+			return nil
+		}
+		return byDate[t.In(loc).Format(time.DateOnly)]
+	}
+
+	scheduled, err := u.taskRepo.ListScheduled(ctx, ownerID, from, to)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.ListScheduled",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	tasks := make([]*domain.Task, 0, len(scheduled))
+	for _, task := range scheduled {
+		if day := dayOf(task.StartDate); day != nil {
+			day.Starting = append(day.Starting, task)
+		}
+		if day := dayOf(task.DueDate); day != nil {
+			day.Due = append(day.Due, task)
+		}
+		tasks = append(tasks, task)
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if day := dayOf(&today); day != nil {
+		day.Overdue, err = u.taskRepo.ListOverdue(ctx, ownerID, today.UTC())
+		if err != nil {
+			u.logger.Error(
+				"taskUseCase - taskRepo.ListOverdue",
+				zap.String("owner_id", ownerID),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		tasks = append(tasks, day.Overdue...)
+	}
+
+	planned, err := u.taskRepo.ListPlanned(ctx, ownerID, days[0].Date, days[len(days)-1].Date)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.ListPlanned",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	for date, plan := range planned {
+		if day := byDate[date]; day != nil {
+			day.Planned = plan
+			tasks = append(tasks, plan...)
+		}
+	}
+
+	occurrences, err := u.scheduledOccurrences(ctx, ownerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, occurrence := range occurrences {
+		anchor := occurrence.DueDate
+		if anchor == nil {
+			anchor = occurrence.StartDate
+		}
+		if day := dayOf(anchor); day != nil {
+			day.Occurrences = append(day.Occurrences, occurrence)
+		}
+	}
+
+	err = u.enrich(ctx, tasks...)
+	if err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
+
+// scheduledOccurrences expands the series of ownerID into the occurrences
+// scheduled in [from, to) after the latest task of each series.
+func (u *UseCase) scheduledOccurrences(ctx context.Context, ownerID string, from, to time.Time) ([]*domain.AgendaOccurrence, error) {
+	heads, err := u.taskRepo.ListSeriesHeads(ctx, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.ListSeriesHeads",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	seriesIDs := make([]string, 0, len(heads))
+	for _, head := range heads {
+		seriesIDs = append(seriesIDs, *head.SeriesID)
+	}
+
+	series, err := u.seriesRepo.ListByIDs(ctx, seriesIDs)
+	if err != nil {
+		u.logger.Error("taskUseCase - seriesRepo.ListByIDs", zap.Error(err))
+		return nil, err
+	}
+
+	occurrences := make([]*domain.AgendaOccurrence, 0)
+	for _, head := range heads {
+		s := series[*head.SeriesID]
+		if s == nil {
+			continue
+		}
+
+		rule, err := rrulex.Parse(s.RRule)
+		if err != nil {
+			return nil, err
+		}
+
+		// Occurrences keep their offsets from the rule, so their dates only
+		// grow: the walk starts at the first occurrence whose anchor date can
+		// fall in the range and stops past it.
+		offset := s.DueOffset
+		if offset == nil {
+			offset = s.StartOffset
+		}
+		if offset == nil {
+			continue
+		}
+
+		it := rule.Iterator(seriesStart(s))
+		it.Seek(from.Add(-*offset))
+		for {
+			i := it.Emitted()
+			at, ok := it.Next()
+			if !ok || (s.LastOccurrence != nil && i > *s.LastOccurrence) {
+				break
+			}
+
+			start, due := occurrenceDates(s, at)
+			anchor := due
+			if anchor == nil {
+				anchor = start
+			}
+			if !anchor.Before(to) {
+				break
+			}
+			if i <= head.Occurrence || anchor.Before(from) {
+				continue
+			}
+
+			occurrences = append(occurrences, &domain.AgendaOccurrence{
+				TaskID:     head.ID,
+				SeriesID:   s.ID,
+				Title:      s.Title,
+				Priority:   s.Priority,
+				Occurrence: i,
+				StartDate:  start,
+				DueDate:    due,
+			})
+		}
+	}
+
+	return occurrences, nil
+}
+
+// DayPlan returns the tasks ownerID planned on day, a YYYY-MM-DD date, in
+// their planned order.
+func (u *UseCase) DayPlan(ctx context.Context, ownerID, day string) ([]*domain.Task, error) {
+	planned, err := u.taskRepo.ListPlanned(ctx, ownerID, day, day)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.ListPlanned",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	tasks := planned[day]
+	if tasks == nil {
+		tasks = make([]*domain.Task, 0)
+	}
+
+	err = u.enrich(ctx, tasks...)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// PlanDay pins the given tasks of ownerID to day, in order, replacing the
+// previous plan of the day. The plan is kept apart from the dates of the
+// tasks.
+func (u *UseCase) PlanDay(ctx context.Context, ownerID, day string, taskIDs []string) ([]*domain.Task, error) {
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		planned, err := u.taskRepo.SetDayPlan(ctx, ownerID, day, taskIDs, time.Now().UTC())
+		if err != nil {
+			u.logger.Error(
+				"taskUseCase - taskRepo.SetDayPlan",
+				zap.String("owner_id", ownerID),
+				zap.String("day", day),
+				zap.Error(err),
+			)
+			return err
+		}
+
+		if planned != int64(len(taskIDs)) {
+			return errorx.ErrTaskNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.DayPlan(ctx, ownerID, day)
+}
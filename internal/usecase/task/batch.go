@@ -0,0 +1,119 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"go.uber.org/zap"
+)
+
+// errBatchAborted rolls back an atomic batch once one of its items failed.
+var errBatchAborted = errors.New("batch aborted")
+
+// Batch applies the operations to the tasks of ownerID in a single
+// transaction and reports the outcome of every (operation, task) pair, in
+// order. A best-effort batch runs each item in a savepoint so that a failing
+// item leaves the others applied. The returned error is only set when the
+// transaction itself failed.
+func (u *UseCase) Batch(ctx context.Context, ownerID, mode string, ops []*domain.TaskBatchOperation) ([]*domain.TaskBatchResult, error) {
+	results := make([]*domain.TaskBatchResult, 0, len(ops))
+	for i, op := range ops {
+		for _, id := range op.IDs {
+			results = append(results, &domain.TaskBatchResult{
+				Operation: i,
+				ID:        id,
+				Status:    domain.TaskBatchSkipped,
+			})
+		}
+	}
+
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		for _, result := range results {
+			op := ops[result.Operation]
+
+			var err error
+			if mode == domain.TaskBatchAtomic {
+				err = u.applyBatchItem(ctx, ownerID, op, result.ID)
+			} else {
+				err = u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+					return u.applyBatchItem(ctx, ownerID, op, result.ID)
+				})
+			}
+
+			if err != nil {
+				result.Status, result.Err = domain.TaskBatchFailed, err
+				if mode == domain.TaskBatchAtomic {
+					return errBatchAborted
+				}
+				continue
+			}
+
+			result.Status = domain.TaskBatchOK
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errBatchAborted) {
+		for _, result := range results {
+			if result.Status == domain.TaskBatchOK {
+				result.Status = domain.TaskBatchRolledBack
+			}
+		}
+		return results, nil
+	}
+
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - txManager.WithTransaction",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	u.invalidateAnalytics(ctx, ownerID)
+	return results, nil
+}
+
+// applyBatchItem applies op to one task of ownerID through the same paths as
+// the single task endpoints, so the same checks and cascades apply.
+func (u *UseCase) applyBatchItem(ctx context.Context, ownerID string, op *domain.TaskBatchOperation, id string) error {
+	var version *int64
+	if v, ok := op.Versions[id]; ok {
+		version = &v
+	}
+
+	patch := &domain.TaskPatch{}
+	switch op.Op {
+	case domain.TaskOpComplete, domain.TaskOpReopen:
+		patch.IsCompleted = domain.Optional[bool]{Value: op.Op == domain.TaskOpComplete, Set: true}
+	case domain.TaskOpPrioritise:
+		patch.Priority = domain.Optional[int]{Value: op.Priority, Set: true}
+	case domain.TaskOpMove:
+		patch.ProjectID = domain.Optional[*string]{Value: op.ProjectID, Set: true, Null: op.ProjectID == nil}
+	case domain.TaskOpRelabel:
+		task, err := u.Get(ctx, id, ownerID)
+		if err != nil {
+			return err
+		}
+
+		labelIDs := slices.DeleteFunc(task.LabelIDs(), func(labelID string) bool {
+			return slices.Contains(op.RemoveLabelIDs, labelID)
+		})
+		for _, labelID := range op.AddLabelIDs {
+			if !slices.Contains(labelIDs, labelID) {
+				labelIDs = append(labelIDs, labelID)
+			}
+		}
+		patch.LabelIDs = domain.Optional[[]string]{Value: labelIDs, Set: true}
+	case domain.TaskOpDelete:
+		return u.trashTask(ctx, id, ownerID, version)
+	}
+
+	_, err := u.patchTask(ctx, id, ownerID, version, patch, domain.TaskScopeThis)
+	return err
+}
@@ -0,0 +1,308 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/lexorank"
+	"go.uber.org/zap"
+)
+
+// maxBoardTasks bounds the number of tasks of a board.
+const maxBoardTasks = 1000
+
+// Board returns the tasks of a project of ownerID matching filter, grouped by
+// status in board order. The column of the tasks without status comes first.
+func (u *UseCase) Board(ctx context.Context, projectID, ownerID string, filter *domain.TaskFilter) ([]*domain.BoardColumn, error) {
+	filter.ProjectID, filter.NoProject = &projectID, false
+	filter.Sort = []domain.SortField{{Column: domain.ColTaskPosition}}
+
+	err := u.checkProject(ctx, filter.ProjectID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := u.projectRepo.ListStatuses(ctx, projectID, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - projectRepo.ListStatuses",
+			zap.String("project_id", projectID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	tasks, err := u.taskRepo.List(ctx, 1, maxBoardTasks, filter)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.List",
+			zap.String("project_id", projectID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	err = u.enrich(ctx, tasks...)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]*domain.BoardColumn, 0, len(statuses)+1)
+	columns = append(columns, &domain.BoardColumn{Tasks: make([]*domain.Task, 0)})
+	byStatus := make(map[string]*domain.BoardColumn, len(statuses))
+	for _, status := range statuses {
+		column := &domain.BoardColumn{Status: status, Tasks: make([]*domain.Task, 0)}
+		byStatus[status.ID] = column
+		columns = append(columns, column)
+	}
+
+	for _, task := range tasks {
+		column := columns[0]
+		if task.StatusID != nil && byStatus[*task.StatusID] != nil {
+			column = byStatus[*task.StatusID]
+		}
+		column.Tasks = append(column.Tasks, task)
+	}
+
+	return columns, nil
+}
+
+// Move puts a task of ownerID in the column of statusID of its project, nil
+// being the column of tasks without status, at the given placement. Moving a
+// task into a done column completes it and into another status reopens it,
+// as a patch would. Only the moved task is written. The version is handled
+// as in Update.
+func (u *UseCase) Move(ctx context.Context, id, ownerID string, version *int64, statusID *string, placement *domain.Placement) (*domain.Task, error) {
+	var task *domain.Task
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		err := u.checkVersion(ctx, id, ownerID, version)
+		if err != nil {
+			return err
+		}
+
+		before, err := u.Get(ctx, id, ownerID)
+		if err != nil {
+			return err
+		}
+
+		if before.ProjectID == nil {
+			return errorx.ErrTaskNotInProject
+		}
+
+		if statusID != nil {
+			status, err := u.projectRepo.GetStatus(ctx, *statusID, *before.ProjectID, ownerID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return errorx.ErrStatusNotFound
+				}
+
+				u.logger.Error(
+					"taskUseCase - projectRepo.GetStatus",
+					zap.String("status_id", *statusID),
+					zap.Error(err),
+				)
+				return err
+			}
+
+			if status.IsDone != before.IsCompleted {
+				patch := &domain.TaskPatch{IsCompleted: domain.Optional[bool]{Value: status.IsDone, Set: true}}
+				patched, err := u.patch(ctx, id, ownerID, patch, domain.TaskScopeThis)
+				if err != nil {
+					return err
+				}
+
+				err = u.checkCompletion(ctx, before, patched)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		position, err := u.place(ctx, before, statusID, placement)
+		if err != nil {
+			return err
+		}
+
+		task, err = u.taskRepo.Move(ctx, id, ownerID, statusID, position, time.Now().UTC())
+		if err != nil {
+			u.logger.Error(
+				"taskUseCase - taskRepo.Move",
+				zap.String("task_id", id),
+				zap.Error(err),
+			)
+
+			if errors.Is(err, pgx.ErrNoRows) {
+				return errorx.ErrTaskNotFound
+			}
+
+			return err
+		}
+
+		err = u.enrich(ctx, task)
+		if err != nil {
+			return err
+		}
+
+		return u.record(ctx, domain.TaskEventUpdated, before, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.invalidateAnalytics(ctx, ownerID)
+	return task, nil
+}
+
+// place returns the position of task in the column of statusID of its
+// project for the given placement. A single neighbour is completed with the
+// task next to it, and no neighbour puts the task at the end of the column.
+// The column stays locked until the end of the transaction.
+func (u *UseCase) place(ctx context.Context, task *domain.Task, statusID *string, placement *domain.Placement) (string, error) {
+	if placement.AfterID == nil && placement.BeforeID == nil {
+		return u.endPosition(ctx, task.OwnerID, task.ProjectID, statusID)
+	}
+
+	err := u.lockColumn(ctx, task.OwnerID, task.ProjectID, statusID)
+	if err != nil {
+		return "", err
+	}
+
+	var lo, hi string
+	if placement.AfterID != nil {
+		after, err := u.neighbour(ctx, *placement.AfterID, task, statusID)
+		if err != nil {
+			return "", err
+		}
+		lo = after.Position
+	}
+	if placement.BeforeID != nil {
+		before, err := u.neighbour(ctx, *placement.BeforeID, task, statusID)
+		if err != nil {
+			return "", err
+		}
+		hi = before.Position
+	}
+
+	switch {
+	case placement.BeforeID == nil:
+		hi, err = u.taskRepo.AdjacentPosition(ctx, task.OwnerID, task.ProjectID, statusID, lo, true, task.ID)
+	case placement.AfterID == nil:
+		lo, err = u.taskRepo.AdjacentPosition(ctx, task.OwnerID, task.ProjectID, statusID, hi, false, task.ID)
+	}
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.AdjacentPosition",
+			zap.String("task_id", task.ID),
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	position, err := lexorank.Between(lo, hi)
+	if err != nil {
+		return "", errorx.ErrInvalidPlacement
+	}
+
+	return position, nil
+}
+
+// neighbour returns the task id, which must be another task of the column
+// task is moved to.
+func (u *UseCase) neighbour(ctx context.Context, id string, task *domain.Task, statusID *string) (*domain.Task, error) {
+	neighbour, err := u.taskRepo.Get(ctx, id, task.OwnerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorx.ErrInvalidPlacement
+		}
+
+		u.logger.Error(
+			"taskUseCase - taskRepo.Get",
+			zap.String("task_id", id),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if neighbour.ID == task.ID || neighbour.Position == "" ||
+		!equalIDs(neighbour.ProjectID, task.ProjectID) || !equalIDs(neighbour.StatusID, statusID) {
+		return nil, errorx.ErrInvalidPlacement
+	}
+
+	return neighbour, nil
+}
+
+// endPosition returns a position at the end of a column of tasks of ownerID,
+// which stays locked until the end of the transaction.
+func (u *UseCase) endPosition(ctx context.Context, ownerID string, projectID, statusID *string) (string, error) {
+	err := u.lockColumn(ctx, ownerID, projectID, statusID)
+	if err != nil {
+		return "", err
+	}
+
+	last, err := u.taskRepo.LastPosition(ctx, ownerID, projectID, statusID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.LastPosition",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	return lexorank.Next(last)
+}
+
+// followProject moves a task whose project changed, which drops its status,
+// to the end of the column of tasks without status of its new project.
+func (u *UseCase) followProject(ctx context.Context, before, task *domain.Task) error {
+	if equalIDs(before.ProjectID, task.ProjectID) {
+		return nil
+	}
+
+	position, err := u.endPosition(ctx, task.OwnerID, task.ProjectID, nil)
+	if err != nil {
+		return err
+	}
+
+	moved, err := u.taskRepo.Move(ctx, task.ID, task.OwnerID, nil, position, task.UpdatedAt)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Move",
+			zap.String("task_id", task.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	task.StatusID, task.Position = moved.StatusID, moved.Position
+	task.Version, task.UpdatedAt = moved.Version, moved.UpdatedAt
+	return nil
+}
+
+// lockColumn locks a column of tasks of ownerID until the end of the
+// transaction.
+func (u *UseCase) lockColumn(ctx context.Context, ownerID string, projectID, statusID *string) error {
+	err := u.taskRepo.LockColumn(ctx, ownerID, projectID, statusID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.LockColumn",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func equalIDs(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,78 @@
+package task
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+)
+
+// encodeCursor seals the cursor with the AEAD, binding it to the owner so a
+// token can be neither forged, altered nor replayed by another user. The
+// result is URL safe.
+func (u *UseCase) encodeCursor(cursor *domain.TaskCursor, ownerID string) (string, error) {
+	plaintext, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := u.aead.Encrypt(plaintext, []byte(ownerID))
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(string(sealed))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeCursor opens a token produced by encodeCursor. Any failure is reported
+// as errorx.ErrInvalidPageToken, as is a token issued for a different sort order.
+func (u *UseCase) decodeCursor(token, ownerID string, sort []domain.SortField) (*domain.TaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) < u.aead.NonceSize() {
+		return nil, errorx.ErrInvalidPageToken
+	}
+
+	plaintext, err := u.aead.Decrypt([]byte(base64.StdEncoding.EncodeToString(raw)), []byte(ownerID))
+	if err != nil {
+		return nil, errorx.ErrInvalidPageToken
+	}
+
+	var cursor domain.TaskCursor
+	err = json.Unmarshal(plaintext, &cursor)
+	if err != nil || cursor.Sort != sortSignature(sort) {
+		return nil, errorx.ErrInvalidPageToken
+	}
+
+	return &cursor, nil
+}
+
+// newCursor captures the position of task in the listing ordered by sort.
+// Every sortable column is kept, as the keyset compares them all through
+// Task.ColumnValue.
+func newCursor(task *domain.Task, sort []domain.SortField, backward bool) *domain.TaskCursor {
+	return &domain.TaskCursor{
+		Last:     task.SortKey(),
+		Sort:     sortSignature(sort),
+		Backward: backward,
+	}
+}
+
+func sortSignature(sort []domain.SortField) string {
+	keys := make([]string, 0, len(sort))
+	for _, field := range sort {
+		if field.Desc {
+			keys = append(keys, "-"+field.Column)
+		} else {
+			keys = append(keys, field.Column)
+		}
+	}
+
+	return strings.Join(keys, ",")
+}
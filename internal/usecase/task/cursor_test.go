@@ -0,0 +1,122 @@
+package task
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/cipherx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+)
+
+func newCursorUseCase() *UseCase {
+	return &UseCase{aead: cipherx.MustNewAEAD(bytes.Repeat([]byte{7}, 32))}
+}
+
+// TestCursorKeepsSortColumns checks that a page token carries the value of
+// every sortable column, so that resuming a listing on any of them compares
+// against the last task of the page.
+func TestCursorKeepsSortColumns(t *testing.T) {
+	u := newCursorUseCase()
+	at := time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC)
+	start, due, deleted := at.Add(time.Hour), at.Add(48*time.Hour), at.Add(72*time.Hour)
+	task := &domain.Task{
+		ID:        "0b9e4a4e-8f5d-4c0e-9d0a-3f1f6c2d7b11",
+		Title:     "Write report",
+		Details:   "not a sort key",
+		Priority:  3,
+		StartDate: &start,
+		DueDate:   &due,
+		Position:  "i4",
+		CreatedAt: at,
+		UpdatedAt: at.Add(time.Minute),
+		DeletedAt: &deleted,
+	}
+
+	for key, column := range domain.TaskSortColumns {
+		t.Run(key, func(t *testing.T) {
+			sort := []domain.SortField{{Column: column, Desc: true}}
+			token, err := u.encodeCursor(newCursor(task, sort, false), "owner")
+			if err != nil {
+				t.Fatalf("encodeCursor() error = %v", err)
+			}
+
+			cursor, err := u.decodeCursor(token, "owner", sort)
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+
+			want, got := task.ColumnValue(column), cursor.Last.ColumnValue(column)
+			if got == nil || !sameValue(got, want) {
+				t.Errorf("cursor %s = %v, want %v", column, got, want)
+			}
+			if got := cursor.Last.ColumnValue(domain.ColID); got != task.ID {
+				t.Errorf("cursor id = %v, want %v", got, task.ID)
+			}
+		})
+	}
+}
+
+// TestDecodeCursorRejects checks that a page token only opens for the owner
+// and the sort order it was issued for, and that any change to it is refused.
+func TestDecodeCursorRejects(t *testing.T) {
+	u := newCursorUseCase()
+	sort := []domain.SortField{{Column: domain.ColTaskPriority, Desc: true}, {Column: domain.ColID}}
+	task := &domain.Task{ID: "0b9e4a4e-8f5d-4c0e-9d0a-3f1f6c2d7b11", Priority: 2}
+
+	token, err := u.encodeCursor(newCursor(task, sort, true), "owner")
+	if err != nil {
+		t.Fatalf("encodeCursor() error = %v", err)
+	}
+
+	cursor, err := u.decodeCursor(token, "owner", sort)
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if !cursor.Backward || cursor.Last.ID != task.ID {
+		t.Errorf("decodeCursor() = %+v, want the cursor that was sealed", cursor)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		t.Fatalf("encodeCursor() = %q, not URL safe base64: %v", token, err)
+	}
+	raw[len(raw)/2] ^= 1
+	tampered := base64.RawURLEncoding.EncodeToString(raw)
+
+	tests := []struct {
+		name    string
+		token   string
+		ownerID string
+		sort    []domain.SortField
+	}{
+		{name: "tampered", token: tampered, ownerID: "owner", sort: sort},
+		{name: "other owner", token: token, ownerID: "intruder", sort: sort},
+		{name: "other sort", token: token, ownerID: "owner", sort: []domain.SortField{{Column: domain.ColTaskPriority}, {Column: domain.ColID}}},
+		{name: "truncated", token: token[:len(token)-4], ownerID: "owner", sort: sort},
+		{name: "short", token: "c2hvcnQ", ownerID: "owner", sort: sort},
+		{name: "empty", token: "", ownerID: "owner", sort: sort},
+		{name: "not base64", token: "not a token!", ownerID: "owner", sort: sort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := u.decodeCursor(tt.token, tt.ownerID, tt.sort)
+			if !errors.Is(err, errorx.ErrInvalidPageToken) {
+				t.Errorf("decodeCursor() error = %v, want %v", err, errorx.ErrInvalidPageToken)
+			}
+		})
+	}
+}
+
+func sameValue(a, b any) bool {
+	ta, ok := a.(time.Time)
+	if !ok {
+		return a == b
+	}
+	tb, ok := b.(time.Time)
+	return ok && ta.Equal(tb)
+}
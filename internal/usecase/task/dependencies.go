@@ -0,0 +1,159 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// AddDependency makes blockerID block the task id, both owned by ownerID.
+// Dependencies that would make a task block itself, directly or through
+// other tasks, are rejected. The check and the insert run serializably so
+// that concurrent additions can not close a cycle.
+func (u *UseCase) AddDependency(ctx context.Context, id, blockerID, ownerID string) error {
+	if id == blockerID {
+		return errorx.ErrDependencyCycle
+	}
+
+	return u.txManager.WithTransaction(ctx, pgx.Serializable, func(ctx context.Context, _ pgx.Tx) error {
+		for _, taskID := range []string{id, blockerID} {
+			_, err := u.Get(ctx, taskID, ownerID)
+			if err != nil {
+				return err
+			}
+		}
+
+		cycle, err := u.taskRepo.IsBlockedBy(ctx, blockerID, id, ownerID)
+		if err != nil {
+			u.logger.Error(
+				"taskUseCase - taskRepo.IsBlockedBy",
+				zap.String("task_id", blockerID),
+				zap.String("blocker_id", id),
+				zap.Error(err),
+			)
+			return err
+		}
+
+		if cycle {
+			return errorx.ErrDependencyCycle
+		}
+
+		err = u.taskRepo.AddDependency(ctx, id, blockerID, ownerID, time.Now().UTC())
+		if err != nil {
+			u.logger.Error(
+				"taskUseCase - taskRepo.AddDependency",
+				zap.String("task_id", id),
+				zap.String("blocker_id", blockerID),
+				zap.Error(err),
+			)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// RemoveDependency stops blockerID from blocking the task id.
+func (u *UseCase) RemoveDependency(ctx context.Context, id, blockerID, ownerID string) error {
+	err := u.taskRepo.RemoveDependency(ctx, id, blockerID, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrDependencyNotFound
+		}
+
+		u.logger.Error(
+			"taskUseCase - taskRepo.RemoveDependency",
+			zap.String("task_id", id),
+			zap.String("blocker_id", blockerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Next returns up to limit open tasks of ownerID in the order they can be
+// done given their dependencies.
+func (u *UseCase) Next(ctx context.Context, ownerID string, limit uint64) ([]*domain.NextTask, error) {
+	next, err := u.taskRepo.Next(ctx, ownerID, limit)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Next",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	tasks := make([]*domain.Task, 0, len(next))
+	for _, task := range next {
+		tasks = append(tasks, task.Task)
+	}
+
+	err = u.enrich(ctx, tasks...)
+	if err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
+
+// checkCompletion refuses, when configured to, a change completing a task
+// while some of its blockers are still open.
+func (u *UseCase) checkCompletion(ctx context.Context, before, after *domain.Task) error {
+	if !u.taskCfg.BlockCompletion || before.IsCompleted || !after.IsCompleted {
+		return nil
+	}
+
+	blockers, err := u.taskRepo.OpenBlockers(ctx, after.ID, after.OwnerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.OpenBlockers",
+			zap.String("task_id", after.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if len(blockers) > 0 {
+		return errorx.ErrTaskBlocked
+	}
+
+	return nil
+}
+
+// attachDependencies loads the dependencies of all tasks with a single query.
+func (u *UseCase) attachDependencies(ctx context.Context, tasks ...*domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	blockedBy, blocks, err := u.taskRepo.Dependencies(ctx, taskIDs)
+	if err != nil {
+		u.logger.Error("taskUseCase - taskRepo.Dependencies", zap.Error(err))
+		return err
+	}
+
+	for _, task := range tasks {
+		task.BlockedBy, task.Blocks = blockedBy[task.ID], blocks[task.ID]
+		if task.BlockedBy == nil {
+			task.BlockedBy = make([]string, 0)
+		}
+		if task.Blocks == nil {
+			task.Blocks = make([]string, 0)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// History returns a page of the events of a task of ownerID, newest first,
+// together with their total. The history of a trashed task stays readable.
+func (u *UseCase) History(ctx context.Context, id, ownerID string, page, pageSize uint64) ([]*domain.TaskEvent, int64, error) {
+	_, err := u.taskRepo.Get(ctx, id, ownerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = u.taskRepo.GetTrashed(ctx, id, ownerID)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, 0, errorx.ErrTaskNotFound
+		}
+
+		u.logger.Error(
+			"taskUseCase - taskRepo.Get",
+			zap.String("task_id", id),
+			zap.Error(err),
+		)
+		return nil, 0, err
+	}
+
+	events, err := u.eventRepo.List(ctx, id, ownerID, page, pageSize)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - eventRepo.List",
+			zap.String("task_id", id),
+			zap.Error(err),
+		)
+		return nil, 0, err
+	}
+
+	total, err := u.eventRepo.Count(ctx, id, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - eventRepo.Count",
+			zap.String("task_id", id),
+			zap.Error(err),
+		)
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// record appends the change from before to after to the history of the task.
+// A nil before records its creation. An update that changed nothing is not
+// recorded, and one that completed or reopened the task is recorded as such.
+// Completions cascading to other tasks are not recorded on them.
+func (u *UseCase) record(ctx context.Context, eventType string, before, after *domain.Task) error {
+	changes := domain.TaskChanges(before, after)
+	if eventType == domain.TaskEventUpdated {
+		if len(changes) == 0 {
+			return nil
+		}
+		eventType = domain.TaskEventType(changes)
+	}
+
+	return u.recordEvent(ctx, eventType, after.ID, after.OwnerID, changes)
+}
+
+func (u *UseCase) recordEvent(ctx context.Context, eventType, taskID, ownerID string, changes map[string]*domain.FieldChange) error {
+	return u.recordEvents(ctx, newTaskEvent(ctx, eventType, taskID, ownerID, changes))
+}
+
+func (u *UseCase) recordEvents(ctx context.Context, events ...*domain.TaskEvent) error {
+	err := u.eventRepo.Create(ctx, events...)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - eventRepo.Create",
+			zap.Int("events", len(events)),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// invalidateAnalytics drops the cached analytics of ownerID. It is called once
+// the transaction of a task write committed, as a dashboard computed before
+// the commit would otherwise be cached with the old figures until it expires.
+// The cache only saves work, so its failures are logged.
+func (u *UseCase) invalidateAnalytics(ctx context.Context, ownerID string) {
+	err := u.analytics.Invalidate(ctx, ownerID)
+	if err != nil {
+		u.logger.Warn(
+			"taskUseCase - analytics.Invalidate",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+	}
+}
+
+// newTaskEvent builds an event made by the user of the request in ctx, if
+// any, stamped with the request ID set by chi's RequestID middleware.
+func newTaskEvent(ctx context.Context, eventType, taskID, ownerID string, changes map[string]*domain.FieldChange) *domain.TaskEvent {
+	event := &domain.TaskEvent{
+		ID:        uuid.NewString(),
+		TaskID:    taskID,
+		OwnerID:   ownerID,
+		Type:      eventType,
+		Changes:   changes,
+		RequestID: middleware.GetReqID(ctx),
+		CreatedAt: time.Now().UTC(),
+	}
+	if event.Changes == nil {
+		event.Changes = make(map[string]*domain.FieldChange)
+	}
+
+	if actorID, ok := ctx.Value(domain.KeyUserID).(string); ok && actorID != "" {
+		event.ActorID = &actorID
+	}
+
+	return event
+}
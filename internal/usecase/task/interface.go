@@ -2,15 +2,96 @@ package task
 
 import (
 	"context"
+	"time"
 
 	"gitlab.com/jodworkspace/mvp/internal/domain"
 )
 
 type Repository interface {
-	Count(ctx context.Context, ownerID string) (int64, error)
-	List(ctx context.Context, page, pageSize uint64, ownerID string) ([]*domain.Task, error)
+	Count(ctx context.Context, filter *domain.TaskFilter) (int64, error)
+	List(ctx context.Context, page, pageSize uint64, filter *domain.TaskFilter) ([]*domain.Task, error)
+	ListAfter(ctx context.Context, cursor *domain.TaskCursor, limit uint64, filter *domain.TaskFilter) ([]*domain.Task, error)
+	Search(ctx context.Context, search *domain.TaskSearch, page, pageSize uint64) ([]*domain.TaskSearchResult, error)
 	Create(ctx context.Context, task *domain.Task) (*domain.Task, error)
-	Get(ctx context.Context, id string) (*domain.Task, error)
+	CreateBatch(ctx context.Context, tasks []*domain.Task) error
+	Get(ctx context.Context, id, ownerID string) (*domain.Task, error)
+	LockVersion(ctx context.Context, id, ownerID string) (int64, error)
 	Update(ctx context.Context, task *domain.Task) (*domain.Task, error)
-	Delete(ctx context.Context, id string) error
+	Patch(ctx context.Context, id, ownerID string, changes map[string]any) (*domain.Task, error)
+	Trash(ctx context.Context, id, ownerID string, deletedAt time.Time) error
+	GetTrashed(ctx context.Context, id, ownerID string) (*domain.Task, error)
+	Restore(ctx context.Context, id, ownerID string, updatedAt time.Time) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+
+	Children(ctx context.Context, id, ownerID string) ([]*domain.Task, error)
+	Subtree(ctx context.Context, id, ownerID string) ([]*domain.Task, error)
+	IsAncestorOrSelf(ctx context.Context, ancestorID, id, ownerID string) (bool, error)
+	CompleteSubtree(ctx context.Context, id, ownerID string, updatedAt time.Time) error
+	ReopenAncestors(ctx context.Context, id, ownerID string, updatedAt time.Time) error
+
+	CreateOccurrence(ctx context.Context, task *domain.Task) (bool, error)
+	TrashOpenOccurrences(ctx context.Context, seriesID, ownerID string, after int, deletedAt time.Time) error
+
+	AddDependency(ctx context.Context, taskID, blockerID, ownerID string, createdAt time.Time) error
+	RemoveDependency(ctx context.Context, taskID, blockerID, ownerID string) error
+	IsBlockedBy(ctx context.Context, id, blockerID, ownerID string) (bool, error)
+	OpenBlockers(ctx context.Context, id, ownerID string) ([]string, error)
+	Dependencies(ctx context.Context, taskIDs []string) (map[string][]string, map[string][]string, error)
+	Next(ctx context.Context, ownerID string, limit uint64) ([]*domain.NextTask, error)
+
+	ListScheduled(ctx context.Context, ownerID string, from, to time.Time) ([]*domain.Task, error)
+	ListOverdue(ctx context.Context, ownerID string, before time.Time) ([]*domain.Task, error)
+	ListSeriesHeads(ctx context.Context, ownerID string) ([]*domain.Task, error)
+	SetDayPlan(ctx context.Context, ownerID, day string, taskIDs []string, createdAt time.Time) (int64, error)
+	ListPlanned(ctx context.Context, ownerID, fromDay, toDay string) (map[string][]*domain.Task, error)
+
+	LockColumn(ctx context.Context, ownerID string, projectID, statusID *string) error
+	LastPosition(ctx context.Context, ownerID string, projectID, statusID *string) (string, error)
+	AdjacentPosition(ctx context.Context, ownerID string, projectID, statusID *string, position string, next bool, excludeID string) (string, error)
+	Move(ctx context.Context, id, ownerID string, statusID *string, position string, updatedAt time.Time) (*domain.Task, error)
+}
+
+type LabelRepository interface {
+	List(ctx context.Context, ownerID string) ([]*domain.Label, error)
+	Create(ctx context.Context, label *domain.Label) error
+	CountOwned(ctx context.Context, ids []string, ownerID string) (int, error)
+	SetTaskLabels(ctx context.Context, taskID, ownerID string, labelIDs []string) error
+	AttachTaskLabels(ctx context.Context, taskLabels map[string][]string) error
+	ListByTasks(ctx context.Context, taskIDs []string) (map[string][]*domain.Label, error)
+}
+
+type ProjectRepository interface {
+	List(ctx context.Context, ownerID string, includeArchived bool) ([]*domain.Project, error)
+	Exists(ctx context.Context, id, ownerID string) (bool, error)
+	Create(ctx context.Context, project *domain.Project) error
+	ListStatuses(ctx context.Context, projectID, ownerID string) ([]*domain.ProjectStatus, error)
+	GetStatus(ctx context.Context, id, projectID, ownerID string) (*domain.ProjectStatus, error)
+}
+
+type EventRepository interface {
+	Create(ctx context.Context, events ...*domain.TaskEvent) error
+	List(ctx context.Context, taskID, ownerID string, page, pageSize uint64) ([]*domain.TaskEvent, error)
+	Count(ctx context.Context, taskID, ownerID string) (int64, error)
+}
+
+type CommentRepository interface {
+	CountByTasks(ctx context.Context, taskIDs []string) (map[string]int, error)
+}
+
+type TimeEntryRepository interface {
+	SumByTasks(ctx context.Context, taskIDs []string) (map[string]int64, error)
+}
+
+// AnalyticsCache is told about every task write of a user, so that their
+// cached analytics get recomputed.
+type AnalyticsCache interface {
+	Invalidate(ctx context.Context, ownerID string) error
+}
+
+type SeriesRepository interface {
+	Create(ctx context.Context, series *domain.TaskSeries) error
+	Get(ctx context.Context, id, ownerID string) (*domain.TaskSeries, error)
+	End(ctx context.Context, id, ownerID string, lastOccurrence int) error
+	ListByIDs(ctx context.Context, ids []string) (map[string]*domain.TaskSeries, error)
+	ListRules(ctx context.Context, ids []string) (map[string]string, error)
 }
@@ -0,0 +1,150 @@
+package task
+
+import (
+	"context"
+	"slices"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// checkLabels makes sure every label belongs to ownerID.
+func (u *UseCase) checkLabels(ctx context.Context, ownerID string, labelIDs []string) error {
+	labelIDs = slices.Compact(slices.Sorted(slices.Values(labelIDs)))
+	if len(labelIDs) == 0 {
+		return nil
+	}
+
+	owned, err := u.labelRepo.CountOwned(ctx, labelIDs, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - labelRepo.CountOwned",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if owned != len(labelIDs) {
+		return errorx.ErrLabelNotFound
+	}
+
+	return nil
+}
+
+// setLabels replaces the labels of task and loads them back.
+func (u *UseCase) setLabels(ctx context.Context, task *domain.Task, labelIDs []string) error {
+	err := u.labelRepo.SetTaskLabels(ctx, task.ID, task.OwnerID, labelIDs)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - labelRepo.SetTaskLabels",
+			zap.String("task_id", task.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return u.attachLabels(ctx, task)
+}
+
+// enrich loads what is stored beside the tasks: their labels, recurrence
+// rules, comment counts and dependencies.
+func (u *UseCase) enrich(ctx context.Context, tasks ...*domain.Task) error {
+	err := u.attachLabels(ctx, tasks...)
+	if err != nil {
+		return err
+	}
+
+	err = u.attachRecurrence(ctx, tasks...)
+	if err != nil {
+		return err
+	}
+
+	err = u.attachCommentCounts(ctx, tasks...)
+	if err != nil {
+		return err
+	}
+
+	err = u.attachTrackedTime(ctx, tasks...)
+	if err != nil {
+		return err
+	}
+
+	return u.attachDependencies(ctx, tasks...)
+}
+
+// attachCommentCounts counts the comments of all tasks with a single query.
+func (u *UseCase) attachCommentCounts(ctx context.Context, tasks ...*domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	counts, err := u.commentRepo.CountByTasks(ctx, taskIDs)
+	if err != nil {
+		u.logger.Error("taskUseCase - commentRepo.CountByTasks", zap.Error(err))
+		return err
+	}
+
+	for _, task := range tasks {
+		task.CommentCount = counts[task.ID]
+	}
+
+	return nil
+}
+
+// attachTrackedTime sums the time tracked on all tasks with a single query.
+func (u *UseCase) attachTrackedTime(ctx context.Context, tasks ...*domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	sums, err := u.timeRepo.SumByTasks(ctx, taskIDs)
+	if err != nil {
+		u.logger.Error("taskUseCase - timeRepo.SumByTasks", zap.Error(err))
+		return err
+	}
+
+	for _, task := range tasks {
+		task.TrackedSeconds = sums[task.ID]
+	}
+
+	return nil
+}
+
+// attachLabels loads the labels of all tasks with a single query.
+func (u *UseCase) attachLabels(ctx context.Context, tasks ...*domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	labels, err := u.labelRepo.ListByTasks(ctx, taskIDs)
+	if err != nil {
+		u.logger.Error("taskUseCase - labelRepo.ListByTasks", zap.Error(err))
+		return err
+	}
+
+	for _, task := range tasks {
+		task.Labels = labels[task.ID]
+		if task.Labels == nil {
+			task.Labels = make([]*domain.Label, 0)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package task
+
+import (
+	"context"
+
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// checkProject makes sure projectID, when given, is a project of the owner.
+func (u *UseCase) checkProject(ctx context.Context, projectID *string, ownerID string) error {
+	if projectID == nil {
+		return nil
+	}
+
+	found, err := u.projectRepo.Exists(ctx, *projectID, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - projectRepo.Exists",
+			zap.String("project_id", *projectID),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if !found {
+		return errorx.ErrProjectNotFound
+	}
+
+	return nil
+}
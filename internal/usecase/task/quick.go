@@ -0,0 +1,88 @@
+package task
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"go.uber.org/zap"
+)
+
+// QuickAdd creates a task with the labels named in labels, as typed in a
+// quick add. Names match the labels of the owner regardless of case, and the
+// missing labels are created.
+func (u *UseCase) QuickAdd(ctx context.Context, task *domain.Task, labels []string) error {
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		labelIDs, err := u.labelIDsByName(ctx, task.OwnerID, labels)
+		if err != nil {
+			return err
+		}
+
+		task.Labels = domain.LabelRefs(labelIDs)
+		err = u.create(ctx, task)
+		if err != nil {
+			return err
+		}
+
+		return u.record(ctx, domain.TaskEventCreated, nil, task)
+	})
+	if err != nil {
+		return err
+	}
+
+	u.invalidateAnalytics(ctx, task.OwnerID)
+	return nil
+}
+
+func (u *UseCase) labelIDsByName(ctx context.Context, ownerID string, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	labels, err := u.labelRepo.List(ctx, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - labelRepo.List",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	ids := make(map[string]string, len(labels))
+	for _, label := range labels {
+		ids[strings.ToLower(label.Name)] = label.ID
+	}
+
+	now := time.Now().UTC()
+	labelIDs := make([]string, 0, len(names))
+	for _, name := range names {
+		id := ids[strings.ToLower(name)]
+		if id == "" {
+			label := &domain.Label{
+				ID:        uuid.NewString(),
+				Name:      name,
+				OwnerID:   ownerID,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			err = u.labelRepo.Create(ctx, label)
+			if err != nil {
+				u.logger.Error(
+					"taskUseCase - labelRepo.Create",
+					zap.String("owner_id", ownerID),
+					zap.Error(err),
+				)
+				return nil, err
+			}
+			id = label.ID
+			ids[strings.ToLower(name)] = id
+		}
+		labelIDs = append(labelIDs, id)
+	}
+
+	return labelIDs, nil
+}
@@ -0,0 +1,400 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/rrulex"
+	"go.uber.org/zap"
+)
+
+// Occurrences previews the next n occurrences of a recurring task, after the
+// task itself.
+func (u *UseCase) Occurrences(ctx context.Context, id, ownerID string, n int) ([]*domain.TaskOccurrence, error) {
+	task, err := u.Get(ctx, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := u.getSeries(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	if series == nil {
+		return nil, errorx.ErrTaskNotRecurring
+	}
+
+	rule, err := rrulex.Parse(series.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := make([]*domain.TaskOccurrence, 0, n)
+	it := rule.Iterator(seriesStart(series))
+	for i := 0; len(occurrences) < n; i++ {
+		at, ok := it.Next()
+		if !ok || (series.LastOccurrence != nil && i > *series.LastOccurrence) {
+			break
+		}
+		if i <= task.Occurrence {
+			continue
+		}
+
+		start, due := occurrenceDates(series, at)
+		occurrences = append(occurrences, &domain.TaskOccurrence{
+			Occurrence: i,
+			StartDate:  start,
+			DueDate:    due,
+		})
+	}
+
+	return occurrences, nil
+}
+
+// checkRecurrence validates the rule a task is about to carry. A recurring
+// task needs a date to schedule its occurrences from.
+func checkRecurrence(task *domain.Task) error {
+	if task.RRule == "" {
+		return nil
+	}
+
+	_, err := rrulex.Parse(task.RRule)
+	if err != nil {
+		return err
+	}
+
+	if recurrenceAnchor(task) == nil {
+		return errorx.ErrRecurrenceWithoutDate
+	}
+
+	return nil
+}
+
+// applyRecurrence reconciles the series of a task that was just written with
+// the rule it should carry. Editing the rule, or any edit in the "future"
+// scope, ends the current series before this occurrence and starts a new one
+// from the task as it now stands, so earlier occurrences keep their history.
+func (u *UseCase) applyRecurrence(ctx context.Context, task *domain.Task, rrule, scope string) error {
+	series, err := u.getSeries(ctx, task)
+	if err != nil {
+		return err
+	}
+
+	if series == nil {
+		task.SeriesID, task.Occurrence, task.RRule = nil, 0, ""
+		if rrule == "" {
+			task.TimeZone = ""
+			return nil
+		}
+
+		rule, err := rrulex.Parse(rrule)
+		if err != nil {
+			return err
+		}
+
+		return u.startSeries(ctx, task, rule)
+	}
+
+	if rrule == "" {
+		err = u.endSeries(ctx, series, task.Occurrence)
+		if err != nil {
+			return err
+		}
+
+		task.TimeZone = ""
+		return u.moveToSeries(ctx, task, nil)
+	}
+
+	rule, err := rrulex.Parse(rrule)
+	if err != nil {
+		return err
+	}
+
+	if task.TimeZone == "" {
+		task.TimeZone = series.TimeZone
+	}
+
+	sameRule := rule.String() == series.RRule
+	if sameRule && task.TimeZone == series.TimeZone && scope != domain.TaskScopeFuture {
+		task.RRule = series.RRule
+		return nil
+	}
+
+	// The new series starts at this occurrence, so an unchanged COUNT only
+	// covers the occurrences left.
+	if sameRule && rule.Count > 0 {
+		rule.Count -= task.Occurrence
+	}
+
+	err = u.endSeries(ctx, series, task.Occurrence)
+	if err != nil {
+		return err
+	}
+
+	return u.startSeries(ctx, task, rule)
+}
+
+// startSeries makes task occurrence 0 of a new series following rule, with
+// the task as the template of the next occurrences.
+func (u *UseCase) startSeries(ctx context.Context, task *domain.Task, rule *rrulex.Rule) error {
+	anchor := recurrenceAnchor(task)
+	if anchor == nil {
+		return errorx.ErrRecurrenceWithoutDate
+	}
+
+	timeZone := task.TimeZone
+	if timeZone == "" {
+		timeZone = time.UTC.String()
+	}
+
+	now := time.Now().UTC()
+	series := &domain.TaskSeries{
+		ID:        uuid.NewString(),
+		OwnerID:   task.OwnerID,
+		RRule:     rule.String(),
+		DTStart:   *anchor,
+		TimeZone:  timeZone,
+		Title:     task.Title,
+		Details:   task.Details,
+		Priority:  task.Priority,
+		ProjectID: task.ProjectID,
+		ParentID:  task.ParentID,
+		LabelIDs:  task.LabelIDs(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if task.StartDate != nil {
+		offset := task.StartDate.Sub(*anchor)
+		series.StartOffset = &offset
+	}
+	if task.DueDate != nil {
+		offset := task.DueDate.Sub(*anchor)
+		series.DueOffset = &offset
+	}
+
+	err := u.seriesRepo.Create(ctx, series)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - seriesRepo.Create",
+			zap.String("task_id", task.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.moveToSeries(ctx, task, &series.ID)
+	if err != nil {
+		return err
+	}
+
+	task.RRule, task.TimeZone = series.RRule, series.TimeZone
+	return nil
+}
+
+// endSeries stops a series before the given occurrence and moves its open
+// occurrences from there on, the given one excepted, to the trash.
+func (u *UseCase) endSeries(ctx context.Context, series *domain.TaskSeries, occurrence int) error {
+	err := u.seriesRepo.End(ctx, series.ID, series.OwnerID, occurrence-1)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - seriesRepo.End",
+			zap.String("series_id", series.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.taskRepo.TrashOpenOccurrences(ctx, series.ID, series.OwnerID, occurrence, time.Now().UTC())
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.TrashOpenOccurrences",
+			zap.String("series_id", series.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// moveToSeries makes task occurrence 0 of the given series, or detaches it.
+func (u *UseCase) moveToSeries(ctx context.Context, task *domain.Task, seriesID *string) error {
+	moved, err := u.taskRepo.Patch(ctx, task.ID, task.OwnerID, map[string]any{
+		domain.ColTaskSeriesID:   seriesID,
+		domain.ColTaskOccurrence: 0,
+	})
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Patch",
+			zap.String("task_id", task.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	task.SeriesID, task.Occurrence, task.RRule = seriesID, 0, ""
+	task.Version, task.UpdatedAt = moved.Version, moved.UpdatedAt
+	return nil
+}
+
+// spawnNextOccurrence creates the task of the occurrence following a completed
+// recurring task from its series template. It does nothing once the series is
+// exhausted or when the next occurrence already exists.
+func (u *UseCase) spawnNextOccurrence(ctx context.Context, task *domain.Task) error {
+	if !task.IsCompleted || task.SeriesID == nil {
+		return nil
+	}
+
+	series, err := u.getSeries(ctx, task)
+	if err != nil || series == nil {
+		return err
+	}
+
+	next := task.Occurrence + 1
+	if series.LastOccurrence != nil && next > *series.LastOccurrence {
+		return nil
+	}
+
+	rule, err := rrulex.Parse(series.RRule)
+	if err != nil {
+		return err
+	}
+
+	at, ok := rule.Nth(seriesStart(series), next)
+	if !ok {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	occurrence := &domain.Task{
+		ID:         uuid.NewString(),
+		Title:      series.Title,
+		Details:    series.Details,
+		Priority:   series.Priority,
+		OwnerID:    series.OwnerID,
+		ParentID:   series.ParentID,
+		ProjectID:  series.ProjectID,
+		SeriesID:   &series.ID,
+		Occurrence: next,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	occurrence.StartDate, occurrence.DueDate = occurrenceDates(series, at)
+	occurrence.Position, err = u.endPosition(ctx, occurrence.OwnerID, occurrence.ProjectID, nil)
+	if err != nil {
+		return err
+	}
+
+	created, err := u.taskRepo.CreateOccurrence(ctx, occurrence)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.CreateOccurrence",
+			zap.String("series_id", series.ID),
+			zap.Int("occurrence", next),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if !created {
+		return nil
+	}
+
+	err = u.setLabels(ctx, occurrence, series.LabelIDs)
+	if err != nil {
+		return err
+	}
+
+	return u.record(ctx, domain.TaskEventCreated, nil, occurrence)
+}
+
+// attachRecurrence loads the recurrence rules of all tasks with a single query.
+func (u *UseCase) attachRecurrence(ctx context.Context, tasks ...*domain.Task) error {
+	seriesIDs := make([]string, 0)
+	for _, task := range tasks {
+		if task.SeriesID != nil {
+			seriesIDs = append(seriesIDs, *task.SeriesID)
+		}
+	}
+
+	if len(seriesIDs) == 0 {
+		return nil
+	}
+
+	rules, err := u.seriesRepo.ListRules(ctx, seriesIDs)
+	if err != nil {
+		u.logger.Error("taskUseCase - seriesRepo.ListRules", zap.Error(err))
+		return err
+	}
+
+	for _, task := range tasks {
+		if task.SeriesID != nil {
+			task.RRule = rules[*task.SeriesID]
+		}
+	}
+
+	return nil
+}
+
+// getSeries returns the series of task, or nil when it has none.
+func (u *UseCase) getSeries(ctx context.Context, task *domain.Task) (*domain.TaskSeries, error) {
+	if task.SeriesID == nil {
+		return nil, nil
+	}
+
+	series, err := u.seriesRepo.Get(ctx, *task.SeriesID, task.OwnerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		u.logger.Error(
+			"taskUseCase - seriesRepo.Get",
+			zap.String("series_id", *task.SeriesID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// recurrenceAnchor is the instant a recurring task is scheduled at: its due
+// date, or its start date when it has none.
+func recurrenceAnchor(task *domain.Task) *time.Time {
+	if task.DueDate != nil {
+		return task.DueDate
+	}
+
+	return task.StartDate
+}
+
+func occurrenceDates(series *domain.TaskSeries, at time.Time) (start, due *time.Time) {
+	if series.StartOffset != nil {
+		t := at.Add(*series.StartOffset).UTC()
+		start = &t
+	}
+	if series.DueOffset != nil {
+		t := at.Add(*series.DueOffset).UTC()
+		due = &t
+	}
+
+	return start, due
+}
+
+// seriesStart returns the start of a series in its time zone, which the rule
+// is expanded in so that occurrences keep their wall clock time across DST
+// changes.
+func seriesStart(series *domain.TaskSeries) time.Time {
+	loc, err := time.LoadLocation(series.TimeZone)
+	if err != nil {
+		return series.DTStart
+	}
+
+	return series.DTStart.In(loc)
+}
@@ -0,0 +1,50 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/rrulex"
+)
+
+// TestOccurrencesKeepWallClockAcrossDST checks that a daily series at 09:00
+// in New York stays at 09:00 local time once daylight saving time starts.
+func TestOccurrencesKeepWallClockAcrossDST(t *testing.T) {
+	rule, err := rrulex.Parse("FREQ=DAILY")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offset := time.Duration(0)
+	series := &domain.TaskSeries{
+		RRule:     rule.String(),
+		DTStart:   time.Date(2026, time.March, 6, 14, 0, 0, 0, time.UTC),
+		TimeZone:  "America/New_York",
+		DueOffset: &offset,
+	}
+
+	loc, err := time.LoadLocation(series.TimeZone)
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	for n, want := range map[int]time.Time{
+		0: time.Date(2026, time.March, 6, 14, 0, 0, 0, time.UTC),
+		1: time.Date(2026, time.March, 7, 14, 0, 0, 0, time.UTC),
+		3: time.Date(2026, time.March, 9, 13, 0, 0, 0, time.UTC),
+	} {
+		at, ok := rule.Nth(seriesStart(series), n)
+		if !ok {
+			t.Fatalf("occurrence %d: not found", n)
+		}
+
+		_, due := occurrenceDates(series, at)
+		if !due.Equal(want) || due.Location() != time.UTC {
+			t.Errorf("occurrence %d: got %v, want %v", n, due, want)
+		}
+		if local := due.In(loc); local.Hour() != 9 {
+			t.Errorf("occurrence %d: got %v local, want 09:00", n, local)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package task
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"go.uber.org/zap"
+)
+
+var highlighter = strings.NewReplacer(
+	domain.HighlightStart, "<mark>",
+	domain.HighlightStop, "</mark>",
+)
+
+// Search finds the owner's tasks matching q, best matches first. The query
+// accepts plain words, "quoted phrases" and prefixes ending with "*".
+func (u *UseCase) Search(ctx context.Context, q, ownerID string, page, pageSize uint64) ([]*domain.TaskSearchResult, error) {
+	search := parseSearchQuery(q)
+	search.OwnerID = ownerID
+	if search.IsEmpty() {
+		return []*domain.TaskSearchResult{}, nil
+	}
+
+	results, err := u.taskRepo.Search(ctx, search, page, pageSize)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Search",
+			zap.String("owner_id", ownerID),
+			zap.String("query", q),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	tasks := make([]*domain.Task, 0, len(results))
+	for _, result := range results {
+		tasks = append(tasks, result.Task)
+	}
+
+	err = u.enrich(ctx, tasks...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		result.TitleHighlight = highlighter.Replace(html.EscapeString(result.TitleHighlight))
+		result.DetailsHighlight = highlighter.Replace(html.EscapeString(result.DetailsHighlight))
+	}
+
+	return results, nil
+}
+
+func parseSearchQuery(q string) *domain.TaskSearch {
+	search := &domain.TaskSearch{}
+
+	for len(q) > 0 {
+		q = strings.TrimLeftFunc(q, unicode.IsSpace)
+		if q == "" {
+			break
+		}
+
+		if q[0] == '"' {
+			end := strings.IndexByte(q[1:], '"')
+			phrase := q[1:]
+			q = ""
+			if end >= 0 {
+				phrase, q = phrase[:end], phrase[end+1:]
+			}
+			if phrase = strings.TrimSpace(phrase); phrase != "" {
+				search.Phrases = append(search.Phrases, phrase)
+			}
+			continue
+		}
+
+		end := strings.IndexFunc(q, unicode.IsSpace)
+		if end < 0 {
+			end = len(q)
+		}
+		term := q[:end]
+		q = q[end:]
+
+		if strings.HasSuffix(term, "*") {
+			prefix := strings.Map(func(r rune) rune {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					return r
+				}
+				return -1
+			}, term)
+			if prefix != "" {
+				search.Prefixes = append(search.Prefixes, prefix)
+			}
+			continue
+		}
+
+		search.Words = append(search.Words, term)
+	}
+
+	return search
+}
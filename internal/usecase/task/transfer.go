@@ -0,0 +1,297 @@
+package task
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/lexorank"
+	"go.uber.org/zap"
+)
+
+const (
+	// exportPageSize is the number of tasks read at once while exporting.
+	exportPageSize = 500
+
+	// importBatchSize is the number of tasks inserted by a single statement.
+	importBatchSize = 500
+)
+
+// Export passes every task of ownerID outside the trash to fn, oldest first,
+// reading them a page at a time so that exports of any size stream.
+func (u *UseCase) Export(ctx context.Context, ownerID string, fn func(*domain.TaskRecord) error) error {
+	projects, err := u.projectRepo.List(ctx, ownerID, true)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - projectRepo.List",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	projectNames := make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+
+	filter := &domain.TaskFilter{
+		OwnerID:         ownerID,
+		IncludeArchived: true,
+		Sort:            []domain.SortField{{Column: domain.ColCreatedAt}},
+	}
+
+	var cursor *domain.TaskCursor
+	for {
+		var tasks []*domain.Task
+		if cursor == nil {
+			tasks, err = u.taskRepo.List(ctx, 1, exportPageSize, filter)
+		} else {
+			tasks, err = u.taskRepo.ListAfter(ctx, cursor, exportPageSize, filter)
+		}
+		if err != nil {
+			u.logger.Error(
+				"taskUseCase - taskRepo.ListAfter",
+				zap.String("owner_id", ownerID),
+				zap.Error(err),
+			)
+			return err
+		}
+
+		err = u.attachLabels(ctx, tasks...)
+		if err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			err = fn(taskRecord(task, projectNames))
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(tasks) < exportPageSize {
+			return nil
+		}
+		cursor = &domain.TaskCursor{Last: *tasks[len(tasks)-1]}
+	}
+}
+
+// Import creates a task of ownerID for every record in a single transaction,
+// in batches. Projects and labels are matched by name, and the missing ones
+// are created. Records are expected to be valid.
+func (u *UseCase) Import(ctx context.Context, ownerID string, records []*domain.TaskRecord) error {
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		now := time.Now().UTC()
+
+		projectIDs, err := u.resolveProjects(ctx, ownerID, records, now)
+		if err != nil {
+			return err
+		}
+
+		labelIDs, err := u.resolveLabels(ctx, ownerID, records, now)
+		if err != nil {
+			return err
+		}
+
+		// Imported tasks go to the end of their project in file order.
+		positions := make(map[string]string)
+
+		for batch := range slices.Chunk(records, importBatchSize) {
+			tasks := make([]*domain.Task, 0, len(batch))
+			events := make([]*domain.TaskEvent, 0, len(batch))
+			taskLabels := make(map[string][]string)
+			for _, record := range batch {
+				task := &domain.Task{
+					ID:          uuid.NewString(),
+					Title:       record.Title,
+					Details:     record.Details,
+					Priority:    record.Priority,
+					IsCompleted: record.IsCompleted,
+					StartDate:   record.StartDate,
+					DueDate:     record.DueDate,
+					OwnerID:     ownerID,
+					CreatedAt:   now,
+					UpdatedAt:   now,
+				}
+				if record.CreatedAt != nil {
+					task.CreatedAt = record.CreatedAt.UTC()
+				}
+				if record.UpdatedAt != nil {
+					task.UpdatedAt = record.UpdatedAt.UTC()
+				}
+				if record.Project != "" {
+					projectID := projectIDs[record.Project]
+					task.ProjectID = &projectID
+				}
+				task.Position, err = u.nextPosition(ctx, positions, task)
+				if err != nil {
+					return err
+				}
+				for _, label := range record.Labels {
+					taskLabels[task.ID] = append(taskLabels[task.ID], labelIDs[label])
+				}
+
+				task.Labels = domain.LabelRefs(taskLabels[task.ID])
+
+				tasks = append(tasks, task)
+				events = append(events, newTaskEvent(ctx, domain.TaskEventCreated, task.ID, ownerID, domain.TaskChanges(nil, task)))
+			}
+
+			err = u.taskRepo.CreateBatch(ctx, tasks)
+			if err != nil {
+				return err
+			}
+
+			err = u.labelRepo.AttachTaskLabels(ctx, taskLabels)
+			if err != nil {
+				return err
+			}
+
+			err = u.recordEvents(ctx, events...)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - Import",
+			zap.String("owner_id", ownerID),
+			zap.Int("records", len(records)),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	u.invalidateAnalytics(ctx, ownerID)
+	return nil
+}
+
+// nextPosition returns the position following the last one handed out in
+// the project of task, starting from the end of the project.
+func (u *UseCase) nextPosition(ctx context.Context, positions map[string]string, task *domain.Task) (string, error) {
+	key := ""
+	if task.ProjectID != nil {
+		key = *task.ProjectID
+	}
+
+	last, ok := positions[key]
+	if !ok {
+		err := u.lockColumn(ctx, task.OwnerID, task.ProjectID, nil)
+		if err != nil {
+			return "", err
+		}
+
+		last, err = u.taskRepo.LastPosition(ctx, task.OwnerID, task.ProjectID, nil)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	position, err := lexorank.Next(last)
+	if err != nil {
+		return "", err
+	}
+
+	positions[key] = position
+	return position, nil
+}
+
+// resolveProjects maps the project names of records to the ids of the
+// projects of ownerID, creating the projects that do not exist yet.
+func (u *UseCase) resolveProjects(ctx context.Context, ownerID string, records []*domain.TaskRecord, now time.Time) (map[string]string, error) {
+	projects, err := u.projectRepo.List(ctx, ownerID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]string, len(projects))
+	for _, project := range projects {
+		ids[project.Name] = project.ID
+	}
+
+	for _, record := range records {
+		if record.Project == "" || ids[record.Project] != "" {
+			continue
+		}
+
+		project := &domain.Project{
+			ID:        uuid.NewString(),
+			Name:      record.Project,
+			OwnerID:   ownerID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		err = u.projectRepo.Create(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		ids[project.Name] = project.ID
+	}
+
+	return ids, nil
+}
+
+// resolveLabels maps the label names of records to the ids of the labels of
+// ownerID, creating the labels that do not exist yet.
+func (u *UseCase) resolveLabels(ctx context.Context, ownerID string, records []*domain.TaskRecord, now time.Time) (map[string]string, error) {
+	labels, err := u.labelRepo.List(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]string, len(labels))
+	for _, label := range labels {
+		ids[label.Name] = label.ID
+	}
+
+	for _, record := range records {
+		for _, name := range record.Labels {
+			if ids[name] != "" {
+				continue
+			}
+
+			label := &domain.Label{
+				ID:        uuid.NewString(),
+				Name:      name,
+				OwnerID:   ownerID,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			err = u.labelRepo.Create(ctx, label)
+			if err != nil {
+				return nil, err
+			}
+			ids[label.Name] = label.ID
+		}
+	}
+
+	return ids, nil
+}
+
+func taskRecord(task *domain.Task, projectNames map[string]string) *domain.TaskRecord {
+	record := &domain.TaskRecord{
+		Title:       task.Title,
+		Details:     task.Details,
+		Priority:    task.Priority,
+		IsCompleted: task.IsCompleted,
+		StartDate:   task.StartDate,
+		DueDate:     task.DueDate,
+		CreatedAt:   &task.CreatedAt,
+		UpdatedAt:   &task.UpdatedAt,
+	}
+	if task.ProjectID != nil {
+		record.Project = projectNames[*task.ProjectID]
+	}
+	for _, label := range task.Labels {
+		record.Labels = append(record.Labels, label.Name)
+	}
+
+	return record
+}
@@ -0,0 +1,154 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// Delete moves a task and its subtasks to the trash, where they stay until
+// restored or purged. When version is given, the task is only trashed if it
+// is still at that version.
+func (u *UseCase) Delete(ctx context.Context, id, ownerID string, version *int64) error {
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		return u.trashTask(ctx, id, ownerID, version)
+	})
+	if err != nil {
+		return err
+	}
+
+	u.invalidateAnalytics(ctx, ownerID)
+	return nil
+}
+
+// trashTask is Delete within the transaction of the caller.
+func (u *UseCase) trashTask(ctx context.Context, id, ownerID string, version *int64) error {
+	err := u.checkVersion(ctx, id, ownerID, version)
+	if err != nil {
+		return err
+	}
+
+	err = u.delete(ctx, id, ownerID)
+	if err != nil {
+		return err
+	}
+
+	return u.recordEvent(ctx, domain.TaskEventDeleted, id, ownerID, nil)
+}
+
+func (u *UseCase) delete(ctx context.Context, id, ownerID string) error {
+	err := u.taskRepo.Trash(ctx, id, ownerID, time.Now().UTC())
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Trash",
+			zap.String("task_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrTaskNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Restore takes a task out of the trash along with the subtasks trashed with
+// it. A subtask can only be restored once its parent is.
+func (u *UseCase) Restore(ctx context.Context, id, ownerID string) error {
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		err := u.restore(ctx, id, ownerID)
+		if err != nil {
+			return err
+		}
+
+		return u.recordEvent(ctx, domain.TaskEventRestored, id, ownerID, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	u.invalidateAnalytics(ctx, ownerID)
+	return nil
+}
+
+func (u *UseCase) restore(ctx context.Context, id, ownerID string) error {
+	task, err := u.taskRepo.GetTrashed(ctx, id, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.GetTrashed",
+			zap.String("task_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrTaskNotFound
+		}
+
+		return err
+	}
+
+	if task.ParentID != nil {
+		_, err = u.taskRepo.Get(ctx, *task.ParentID, ownerID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrParentTaskTrashed
+		}
+		if err != nil {
+			u.logger.Error(
+				"taskUseCase - taskRepo.Get",
+				zap.String("task_id", *task.ParentID),
+				zap.Error(err),
+			)
+			return err
+		}
+	}
+
+	err = u.taskRepo.Restore(ctx, id, ownerID, time.Now().UTC())
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Restore",
+			zap.String("task_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrTaskNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// PurgeTrash permanently removes the tasks trashed more than retention ago,
+// once right away and then every interval, until ctx is done.
+func (u *UseCase) PurgeTrash(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := u.taskRepo.Purge(ctx, time.Now().UTC().Add(-retention))
+		if err != nil {
+			u.logger.Error("taskUseCase - taskRepo.Purge", zap.Error(err))
+		} else if purged > 0 {
+			u.logger.Info("taskUseCase - purged trashed tasks", zap.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// Children lists the direct subtasks of a task owned by ownerID.
+func (u *UseCase) Children(ctx context.Context, id, ownerID string) ([]*domain.Task, error) {
+	_, err := u.Get(ctx, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := u.taskRepo.Children(ctx, id, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Children",
+			zap.String("task_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	err = u.enrich(ctx, tasks...)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Tree returns a task with all of its descendants and their completion progress.
+func (u *UseCase) Tree(ctx context.Context, id, ownerID string) (*domain.TaskNode, error) {
+	tasks, err := u.taskRepo.Subtree(ctx, id, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Subtree",
+			zap.String("task_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		return nil, errorx.ErrTaskNotFound
+	}
+
+	err = u.enrich(ctx, tasks...)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTree(tasks), nil
+}
+
+// buildTree links tasks ordered root first, parents before children.
+func buildTree(tasks []*domain.Task) *domain.TaskNode {
+	nodes := make(map[string]*domain.TaskNode, len(tasks))
+	root := &domain.TaskNode{Task: tasks[0], Subtasks: make([]*domain.TaskNode, 0)}
+	nodes[root.ID] = root
+
+	for _, task := range tasks[1:] {
+		node := &domain.TaskNode{Task: task, Subtasks: make([]*domain.TaskNode, 0)}
+		nodes[task.ID] = node
+
+		if parent, ok := nodes[*task.ParentID]; ok {
+			parent.Subtasks = append(parent.Subtasks, node)
+		}
+	}
+
+	rollUp(root)
+	return root
+}
+
+// rollUp sets the progress of every node and returns the number of completed
+// and total descendants of node.
+func rollUp(node *domain.TaskNode) (completed, total int) {
+	for _, child := range node.Subtasks {
+		c, t := rollUp(child)
+		completed += c
+		total += t
+
+		total++
+		if child.IsCompleted {
+			completed++
+		}
+	}
+
+	switch {
+	case total > 0:
+		node.Progress = float64(completed) * 100 / float64(total)
+	case node.IsCompleted:
+		node.Progress = 100
+	}
+
+	return completed, total
+}
+
+// checkParent makes sure parentID is an existing task of the owner that does
+// not sit below the task id, so the hierarchy stays acyclic.
+func (u *UseCase) checkParent(ctx context.Context, id string, parentID *string, ownerID string) error {
+	if parentID == nil {
+		return nil
+	}
+
+	if *parentID == id {
+		return errorx.ErrTaskCycle
+	}
+
+	_, err := u.Get(ctx, *parentID, ownerID)
+	if err != nil {
+		if errors.Is(err, errorx.ErrTaskNotFound) {
+			return errorx.ErrParentTaskNotFound
+		}
+		return err
+	}
+
+	cycle, err := u.taskRepo.IsAncestorOrSelf(ctx, id, *parentID, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.IsAncestorOrSelf",
+			zap.String("task_id", id),
+			zap.String("parent_id", *parentID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if cycle {
+		return errorx.ErrTaskCycle
+	}
+
+	return nil
+}
+
+// cascadeCompletion completes the subtasks of a completed task, and reopens the
+// ancestors of a task that is open again.
+func (u *UseCase) cascadeCompletion(ctx context.Context, task *domain.Task) error {
+	now := time.Now().UTC()
+
+	if task.IsCompleted {
+		err := u.taskRepo.CompleteSubtree(ctx, task.ID, task.OwnerID, now)
+		if err != nil {
+			u.logger.Error(
+				"taskUseCase - taskRepo.CompleteSubtree",
+				zap.String("task_id", task.ID),
+				zap.Error(err),
+			)
+		}
+		return err
+	}
+
+	if task.ParentID == nil {
+		return nil
+	}
+
+	err := u.taskRepo.ReopenAncestors(ctx, task.ID, task.OwnerID, now)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.ReopenAncestors",
+			zap.String("task_id", task.ID),
+			zap.Error(err),
+		)
+	}
+	return err
+}
@@ -2,59 +2,240 @@ package task
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/config"
 	"gitlab.com/jodworkspace/mvp/internal/domain"
+	postgresrepo "gitlab.com/jodworkspace/mvp/internal/repository/postgres"
 	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/cipherx"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
 	"go.uber.org/zap"
 )
 
 type UseCase struct {
-	taskRepo Repository
-	logger   *logger.ZapLogger
+	taskCfg     *config.TaskConfig
+	taskRepo    Repository
+	labelRepo   LabelRepository
+	projectRepo ProjectRepository
+	seriesRepo  SeriesRepository
+	eventRepo   EventRepository
+	commentRepo CommentRepository
+	timeRepo    TimeEntryRepository
+	analytics   AnalyticsCache
+	txManager   *postgresrepo.TransactionManager
+	aead        *cipherx.AEAD
+	logger      *logger.ZapLogger
 }
 
-func NewUseCase(taskRepo Repository, zl *logger.ZapLogger) *UseCase {
+func NewUseCase(
+	taskCfg *config.TaskConfig,
+	taskRepo Repository,
+	labelRepo LabelRepository,
+	projectRepo ProjectRepository,
+	seriesRepo SeriesRepository,
+	eventRepo EventRepository,
+	commentRepo CommentRepository,
+	timeRepo TimeEntryRepository,
+	analytics AnalyticsCache,
+	txManager *postgresrepo.TransactionManager,
+	aead *cipherx.AEAD,
+	zl *logger.ZapLogger,
+) *UseCase {
 	return &UseCase{
-		taskRepo: taskRepo,
-		logger:   zl,
+		taskCfg:     taskCfg,
+		taskRepo:    taskRepo,
+		labelRepo:   labelRepo,
+		projectRepo: projectRepo,
+		seriesRepo:  seriesRepo,
+		eventRepo:   eventRepo,
+		commentRepo: commentRepo,
+		timeRepo:    timeRepo,
+		analytics:   analytics,
+		txManager:   txManager,
+		aead:        aead,
+		logger:      zl,
 	}
 }
-func (u *UseCase) Count(ctx context.Context, ownerID string) (int64, error) {
-	count, err := u.taskRepo.Count(ctx, ownerID)
+func (u *UseCase) Count(ctx context.Context, filter *domain.TaskFilter) (int64, error) {
+	count, err := u.taskRepo.Count(ctx, filter)
 	if err != nil {
-		u.logger.Error("taskUseCase - taskRepo.Count", zap.Error(err))
+		u.logger.Error(
+			"taskUseCase - taskRepo.Count",
+			zap.String("owner_id", filter.OwnerID),
+			zap.Error(err),
+		)
 		return 0, err
 	}
 
 	return count, nil
 }
 
-func (u *UseCase) List(ctx context.Context, page, pageSize uint64, ownerID string) ([]*domain.Task, error) {
-	tasks, err := u.taskRepo.List(ctx, page, pageSize, ownerID)
+// List returns a page of tasks matching filter together with the tokens of the
+// surrounding pages. A page token selects keyset pagination; without one the
+// page number is used as an offset, and the returned tokens let the client
+// switch to keyset pagination from there.
+func (u *UseCase) List(ctx context.Context, p *domain.Pagination, filter *domain.TaskFilter) ([]*domain.Task, *domain.PageTokens, error) {
+	err := u.checkProject(ctx, filter.ProjectID, filter.OwnerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.PageToken == "" {
+		return u.listByOffset(ctx, p, filter)
+	}
+
+	cursor, err := u.decodeCursor(p.PageToken, filter.OwnerID, filter.Sort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tasks, err := u.taskRepo.ListAfter(ctx, cursor, p.PageSize+1, filter)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.ListAfter",
+			zap.Uint64("page_size", p.PageSize),
+			zap.String("owner_id", filter.OwnerID),
+			zap.Error(err),
+		)
+		return nil, nil, err
+	}
+
+	hasMore := uint64(len(tasks)) > p.PageSize
+	if hasMore && cursor.Backward {
+		tasks = tasks[1:]
+	} else if hasMore {
+		tasks = tasks[:p.PageSize]
+	}
+
+	hasPrev, hasNext := true, hasMore
+	if cursor.Backward {
+		hasPrev, hasNext = hasMore, true
+	}
+
+	err = u.enrich(ctx, tasks...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := u.pageTokens(tasks, filter, hasPrev, hasNext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tasks, tokens, nil
+}
+
+func (u *UseCase) listByOffset(ctx context.Context, p *domain.Pagination, filter *domain.TaskFilter) ([]*domain.Task, *domain.PageTokens, error) {
+	tasks, err := u.taskRepo.List(ctx, p.Page, p.PageSize, filter)
 	if err != nil {
 		u.logger.Error(
 			"taskUseCase - taskRepo.List",
-			zap.Uint64("page", page),
-			zap.Uint64("page_size", pageSize),
-			zap.String("ownerID", ownerID),
+			zap.Uint64("page", p.Page),
+			zap.Uint64("page_size", p.PageSize),
+			zap.String("owner_id", filter.OwnerID),
 			zap.Error(err),
 		)
-		return nil, err
+		return nil, nil, err
+	}
+
+	err = u.enrich(ctx, tasks...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := u.pageTokens(tasks, filter, p.Page > 1, uint64(len(tasks)) == p.PageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tasks, tokens, nil
+}
+
+func (u *UseCase) pageTokens(tasks []*domain.Task, filter *domain.TaskFilter, hasPrev, hasNext bool) (*domain.PageTokens, error) {
+	tokens := &domain.PageTokens{}
+	if len(tasks) == 0 {
+		return tokens, nil
 	}
 
-	return tasks, nil
+	var err error
+	if hasPrev {
+		tokens.Prev, err = u.encodeCursor(newCursor(tasks[0], filter.Sort, true), filter.OwnerID)
+		if err != nil {
+			u.logger.Error("taskUseCase - encodeCursor", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if hasNext {
+		tokens.Next, err = u.encodeCursor(newCursor(tasks[len(tasks)-1], filter.Sort, false), filter.OwnerID)
+		if err != nil {
+			u.logger.Error("taskUseCase - encodeCursor", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return tokens, nil
 }
 
+// Create stores a new task. Labels are referenced by id in task.Labels and
+// are loaded back once attached. A task with an RRule starts a new series.
 func (u *UseCase) Create(ctx context.Context, task *domain.Task) error {
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		err := u.create(ctx, task)
+		if err != nil {
+			return err
+		}
+
+		return u.record(ctx, domain.TaskEventCreated, nil, task)
+	})
+	if err != nil {
+		return err
+	}
+
+	u.invalidateAnalytics(ctx, task.OwnerID)
+	return nil
+}
+
+func (u *UseCase) create(ctx context.Context, task *domain.Task) error {
 	now := time.Now().UTC()
 	task.ID = uuid.NewString()
 	task.IsCompleted = false
+	task.SeriesID, task.Occurrence = nil, 0
 	task.CreatedAt = now
 	task.UpdatedAt = now
 
-	_, err := u.taskRepo.Create(ctx, task)
+	err := checkRecurrence(task)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkParent(ctx, task.ID, task.ParentID, task.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkProject(ctx, task.ProjectID, task.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	labelIDs := task.LabelIDs()
+	err = u.checkLabels(ctx, task.OwnerID, labelIDs)
+	if err != nil {
+		return err
+	}
+
+	task.StatusID = nil
+	task.Position, err = u.endPosition(ctx, task.OwnerID, task.ProjectID, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.taskRepo.Create(ctx, task)
 	if err != nil {
 		u.logger.Error(
 			"taskUseCase- taskRepo.Create",
@@ -64,27 +245,299 @@ func (u *UseCase) Create(ctx context.Context, task *domain.Task) error {
 		return err
 	}
 
-	return nil
+	err = u.setLabels(ctx, task, labelIDs)
+	if err != nil {
+		return err
+	}
+
+	err = u.applyRecurrence(ctx, task, task.RRule, domain.TaskScopeThis)
+	if err != nil {
+		return err
+	}
+
+	return u.cascadeCompletion(ctx, task)
 }
 
-func (u *UseCase) Get(ctx context.Context, id string) (*domain.Task, error) {
-	return nil, nil
+func (u *UseCase) Get(ctx context.Context, id, ownerID string) (*domain.Task, error) {
+	task, err := u.taskRepo.Get(ctx, id, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Get",
+			zap.String("task_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorx.ErrTaskNotFound
+		}
+
+		return nil, err
+	}
+
+	err = u.enrich(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
 }
 
-func (u *UseCase) Update(ctx context.Context, task *domain.Task) error {
+// Update replaces the mutable fields, the labels and the recurrence rule of a
+// task owned by task.OwnerID and refreshes task with the stored values. With
+// the "future" scope, the next occurrences of a recurring task follow the edit
+// too. Completing a recurring task creates its next occurrence. When version
+// is given, the task is only updated if it is still at that version.
+func (u *UseCase) Update(ctx context.Context, task *domain.Task, version *int64, scope string) error {
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		err := u.checkVersion(ctx, task.ID, task.OwnerID, version)
+		if err != nil {
+			return err
+		}
+
+		before, err := u.Get(ctx, task.ID, task.OwnerID)
+		if err != nil {
+			return err
+		}
+
+		err = u.update(ctx, task, scope)
+		if err != nil {
+			return err
+		}
+
+		err = u.checkCompletion(ctx, before, task)
+		if err != nil {
+			return err
+		}
+
+		err = u.followProject(ctx, before, task)
+		if err != nil {
+			return err
+		}
+
+		return u.record(ctx, domain.TaskEventUpdated, before, task)
+	})
+	if err != nil {
+		return err
+	}
+
+	u.invalidateAnalytics(ctx, task.OwnerID)
 	return nil
 }
 
-func (u *UseCase) Delete(ctx context.Context, id string) error {
-	err := u.taskRepo.Delete(ctx, id)
+func (u *UseCase) update(ctx context.Context, task *domain.Task, scope string) error {
+	task.UpdatedAt = time.Now().UTC()
+
+	err := checkRecurrence(task)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkParent(ctx, task.ID, task.ParentID, task.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkProject(ctx, task.ProjectID, task.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	labelIDs := task.LabelIDs()
+	err = u.checkLabels(ctx, task.OwnerID, labelIDs)
+	if err != nil {
+		return err
+	}
+
+	updated, err := u.taskRepo.Update(ctx, task)
+	if err != nil {
+		u.logger.Error(
+			"taskUseCase - taskRepo.Update",
+			zap.String("task_id", task.ID),
+			zap.String("owner_id", task.OwnerID),
+			zap.Error(err),
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrTaskNotFound
+		}
+
+		return err
+	}
+
+	rrule, timeZone := task.RRule, task.TimeZone
+	*task = *updated
+	task.TimeZone = timeZone
+	err = u.setLabels(ctx, task, labelIDs)
+	if err != nil {
+		return err
+	}
+
+	err = u.applyRecurrence(ctx, task, rrule, scope)
+	if err != nil {
+		return err
+	}
+
+	err = u.cascadeCompletion(ctx, task)
+	if err != nil {
+		return err
+	}
+
+	return u.spawnNextOccurrence(ctx, task)
+}
+
+// Patch applies a merge patch to a task owned by ownerID, touching only the
+// columns present in the patch. The scope and version are handled as in Update.
+func (u *UseCase) Patch(ctx context.Context, id, ownerID string, version *int64, patch *domain.TaskPatch, scope string) (*domain.Task, error) {
+	var task *domain.Task
+	err := u.txManager.WithTransaction(ctx, pgx.ReadCommitted, func(ctx context.Context, _ pgx.Tx) error {
+		var err error
+		task, err = u.patchTask(ctx, id, ownerID, version, patch, scope)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.invalidateAnalytics(ctx, ownerID)
+	return task, nil
+}
+
+// patchTask is Patch within the transaction of the caller.
+func (u *UseCase) patchTask(ctx context.Context, id, ownerID string, version *int64, patch *domain.TaskPatch, scope string) (*domain.Task, error) {
+	err := u.checkVersion(ctx, id, ownerID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := u.Get(ctx, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := u.patch(ctx, id, ownerID, patch, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.checkCompletion(ctx, before, task)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.followProject(ctx, before, task)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.record(ctx, domain.TaskEventUpdated, before, task)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (u *UseCase) patch(ctx context.Context, id, ownerID string, patch *domain.TaskPatch, scope string) (*domain.Task, error) {
+	changes := patch.Changes()
+	if len(changes) == 0 && !patch.LabelIDs.Set && !patch.RRule.Set && scope != domain.TaskScopeFuture {
+		return u.Get(ctx, id, ownerID)
+	}
+	changes[domain.ColUpdatedAt] = time.Now().UTC()
+
+	if patch.RRule.Set && patch.RRule.Value != nil {
+		current, err := u.Get(ctx, id, ownerID)
+		if err != nil {
+			return nil, err
+		}
+
+		patch.Apply(current)
+		err = checkRecurrence(current)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.ParentID.Set {
+		err := u.checkParent(ctx, id, patch.ParentID.Value, ownerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.ProjectID.Set {
+		err := u.checkProject(ctx, patch.ProjectID.Value, ownerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.LabelIDs.Set {
+		err := u.checkLabels(ctx, ownerID, patch.LabelIDs.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	task, err := u.taskRepo.Patch(ctx, id, ownerID, changes)
 	if err != nil {
 		u.logger.Error(
-			"taskUseCase - taskRepo.Delete",
+			"taskUseCase - taskRepo.Patch",
 			zap.String("task_id", id),
+			zap.String("owner_id", ownerID),
 			zap.Error(err),
 		)
-		return err
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorx.ErrTaskNotFound
+		}
+
+		return nil, err
 	}
 
-	return nil
+	if patch.LabelIDs.Set {
+		err = u.setLabels(ctx, task, patch.LabelIDs.Value)
+	} else {
+		err = u.attachLabels(ctx, task)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.attachRecurrence(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.RRule.Set || scope == domain.TaskScopeFuture {
+		task.TimeZone = patch.TimeZone.Value
+		rrule := task.RRule
+		if patch.RRule.Set {
+			rrule = ""
+			if patch.RRule.Value != nil {
+				rrule = *patch.RRule.Value
+			}
+		}
+
+		err = u.applyRecurrence(ctx, task, rrule, scope)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.IsCompleted.Set || patch.ParentID.Set {
+		err = u.cascadeCompletion(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.IsCompleted.Set {
+		err = u.spawnNextOccurrence(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
 }
@@ -0,0 +1,40 @@
+package task
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// checkVersion makes sure the task is still at version, when given, and locks
+// it until the end of the transaction so that no concurrent write slips in
+// between the check and the write.
+func (u *UseCase) checkVersion(ctx context.Context, id, ownerID string, version *int64) error {
+	if version == nil {
+		return nil
+	}
+
+	current, err := u.taskRepo.LockVersion(ctx, id, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrTaskNotFound
+		}
+
+		u.logger.Error(
+			"taskUseCase - taskRepo.LockVersion",
+			zap.String("task_id", id),
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if current != *version {
+		return errorx.ErrTaskModified
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package timeentry
+
+import (
+	"context"
+
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+)
+
+type Repository interface {
+	List(ctx context.Context, taskID, ownerID string, page, pageSize uint64) ([]*domain.TimeEntry, error)
+	Count(ctx context.Context, taskID, ownerID string) (int64, error)
+	Get(ctx context.Context, id, taskID, ownerID string) (*domain.TimeEntry, error)
+	Create(ctx context.Context, entry *domain.TimeEntry) error
+	Update(ctx context.Context, entry *domain.TimeEntry) (*domain.TimeEntry, error)
+	Delete(ctx context.Context, id, taskID, ownerID string) error
+	Report(ctx context.Context, filter *domain.TimeReportFilter) ([]*domain.TimeReportRow, error)
+}
+
+type TimerRepository interface {
+	Get(ctx context.Context, ownerID string) (*domain.Timer, error)
+	Start(ctx context.Context, timer *domain.Timer) (bool, error)
+	Stop(ctx context.Context, ownerID string) (*domain.Timer, error)
+}
+
+type TaskRepository interface {
+	Get(ctx context.Context, id, ownerID string) (*domain.Task, error)
+}
@@ -0,0 +1,338 @@
+package timeentry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/jodworkspace/mvp/internal/domain"
+	"gitlab.com/jodworkspace/mvp/pkg/logger"
+	"gitlab.com/jodworkspace/mvp/pkg/utils/errorx"
+	"go.uber.org/zap"
+)
+
+// maxReportRange bounds the range of a report.
+const maxReportRange = 366 * 24 * time.Hour
+
+type UseCase struct {
+	entryRepo Repository
+	timerRepo TimerRepository
+	taskRepo  TaskRepository
+	logger    *logger.ZapLogger
+}
+
+func NewUseCase(
+	entryRepo Repository,
+	timerRepo TimerRepository,
+	taskRepo TaskRepository,
+	zl *logger.ZapLogger,
+) *UseCase {
+	return &UseCase{
+		entryRepo: entryRepo,
+		timerRepo: timerRepo,
+		taskRepo:  taskRepo,
+		logger:    zl,
+	}
+}
+
+// Timer returns the running timer of ownerID.
+func (u *UseCase) Timer(ctx context.Context, ownerID string) (*domain.Timer, error) {
+	timer, err := u.timerRepo.Get(ctx, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"timeEntryUseCase - timerRepo.Get",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if timer == nil {
+		return nil, errorx.ErrNoRunningTimer
+	}
+
+	return timer, nil
+}
+
+// StartTimer starts a timer on a task of ownerID. A user runs a single timer
+// at a time, so the running one has to be stopped first.
+func (u *UseCase) StartTimer(ctx context.Context, taskID, ownerID, note string) (*domain.Timer, error) {
+	err := u.checkTask(ctx, taskID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	timer := &domain.Timer{
+		TaskID:    taskID,
+		OwnerID:   ownerID,
+		StartedAt: time.Now().UTC().Truncate(time.Second),
+		Note:      note,
+	}
+
+	started, err := u.timerRepo.Start(ctx, timer)
+	if err != nil {
+		u.logger.Error(
+			"timeEntryUseCase - timerRepo.Start",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if !started {
+		return nil, errorx.ErrTimerRunning
+	}
+
+	return timer, nil
+}
+
+// StopTimer stops the running timer of ownerID and saves the elapsed time as
+// an entry of its task. The timer keeps running if the entry can not be
+// saved, unless its task has been deleted in the meantime.
+func (u *UseCase) StopTimer(ctx context.Context, ownerID string) (*domain.TimeEntry, error) {
+	timer, err := u.timerRepo.Stop(ctx, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"timeEntryUseCase - timerRepo.Stop",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if timer == nil {
+		return nil, errorx.ErrNoRunningTimer
+	}
+
+	now := time.Now().UTC()
+	entry := &domain.TimeEntry{
+		ID:        uuid.NewString(),
+		TaskID:    timer.TaskID,
+		OwnerID:   ownerID,
+		StartedAt: timer.StartedAt,
+		EndedAt:   now.Truncate(time.Second),
+		Note:      timer.Note,
+		Source:    domain.TimeEntrySourceTimer,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if !entry.EndedAt.After(entry.StartedAt) {
+		entry.EndedAt = entry.StartedAt.Add(time.Second)
+	}
+	entry.Seconds = int64(entry.EndedAt.Sub(entry.StartedAt) / time.Second)
+
+	err = u.entryRepo.Create(ctx, entry)
+	if err != nil {
+		if errors.Is(err, errorx.ErrTaskNotFound) {
+			return nil, err
+		}
+
+		u.logger.Error(
+			"timeEntryUseCase - entryRepo.Create",
+			zap.String("task_id", timer.TaskID),
+			zap.Error(err),
+		)
+
+		_, restoreErr := u.timerRepo.Start(ctx, timer)
+		if restoreErr != nil {
+			u.logger.Error(
+				"timeEntryUseCase - timerRepo.Start",
+				zap.String("task_id", timer.TaskID),
+				zap.Error(restoreErr),
+			)
+		}
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// DiscardTimer stops the running timer of ownerID without saving it.
+func (u *UseCase) DiscardTimer(ctx context.Context, ownerID string) error {
+	timer, err := u.timerRepo.Stop(ctx, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"timeEntryUseCase - timerRepo.Stop",
+			zap.String("owner_id", ownerID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if timer == nil {
+		return errorx.ErrNoRunningTimer
+	}
+
+	return nil
+}
+
+// List returns a page of the time entries of a task of ownerID, latest first,
+// together with their number.
+func (u *UseCase) List(ctx context.Context, taskID, ownerID string, page, pageSize uint64) ([]*domain.TimeEntry, int64, error) {
+	err := u.checkTask(ctx, taskID, ownerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries, err := u.entryRepo.List(ctx, taskID, ownerID, page, pageSize)
+	if err != nil {
+		u.logger.Error(
+			"timeEntryUseCase - entryRepo.List",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return nil, 0, err
+	}
+
+	total, err := u.entryRepo.Count(ctx, taskID, ownerID)
+	if err != nil {
+		u.logger.Error(
+			"timeEntryUseCase - entryRepo.Count",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// Create records time spent on a task by hand.
+func (u *UseCase) Create(ctx context.Context, entry *domain.TimeEntry) error {
+	err := checkSpan(entry)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkTask(ctx, entry.TaskID, entry.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	entry.ID = uuid.NewString()
+	entry.Source = domain.TimeEntrySourceManual
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+	entry.Seconds = int64(entry.EndedAt.Sub(entry.StartedAt) / time.Second)
+
+	err = u.entryRepo.Create(ctx, entry)
+	if err != nil {
+		u.logger.Error(
+			"timeEntryUseCase - entryRepo.Create",
+			zap.String("task_id", entry.TaskID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Update replaces the span and note of a time entry.
+func (u *UseCase) Update(ctx context.Context, entry *domain.TimeEntry) error {
+	err := checkSpan(entry)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkTask(ctx, entry.TaskID, entry.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	entry.UpdatedAt = time.Now().UTC()
+	updated, err := u.entryRepo.Update(ctx, entry)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrTimeEntryNotFound
+		}
+
+		u.logger.Error(
+			"timeEntryUseCase - entryRepo.Update",
+			zap.String("entry_id", entry.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	*entry = *updated
+	return nil
+}
+
+func (u *UseCase) Delete(ctx context.Context, id, taskID, ownerID string) error {
+	err := u.checkTask(ctx, taskID, ownerID)
+	if err != nil {
+		return err
+	}
+
+	err = u.entryRepo.Delete(ctx, id, taskID, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrTimeEntryNotFound
+		}
+
+		u.logger.Error(
+			"timeEntryUseCase - entryRepo.Delete",
+			zap.String("entry_id", id),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Report sums the time tracked by a user over a range of at most a year, by
+// day, project or label.
+func (u *UseCase) Report(ctx context.Context, filter *domain.TimeReportFilter) ([]*domain.TimeReportRow, error) {
+	if !filter.To.After(filter.From) || filter.To.Sub(filter.From) > maxReportRange {
+		return nil, errorx.ErrInvalidReportRange
+	}
+
+	report, err := u.entryRepo.Report(ctx, filter)
+	if err != nil {
+		u.logger.Error(
+			"timeEntryUseCase - entryRepo.Report",
+			zap.String("owner_id", filter.OwnerID),
+			zap.String("group_by", filter.GroupBy),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// checkTask makes sure the task exists and belongs to ownerID.
+func (u *UseCase) checkTask(ctx context.Context, taskID, ownerID string) error {
+	_, err := u.taskRepo.Get(ctx, taskID, ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.ErrTaskNotFound
+		}
+
+		u.logger.Error(
+			"timeEntryUseCase - taskRepo.Get",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// checkSpan normalises the span of an entry to UTC seconds and makes sure it
+// is not empty.
+func checkSpan(entry *domain.TimeEntry) error {
+	entry.StartedAt = entry.StartedAt.UTC().Truncate(time.Second)
+	entry.EndedAt = entry.EndedAt.UTC().Truncate(time.Second)
+	if !entry.EndedAt.After(entry.StartedAt) {
+		return errorx.ErrInvalidTimeSpan
+	}
+
+	return nil
+}
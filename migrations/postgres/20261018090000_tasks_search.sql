@@ -0,0 +1,80 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION task_search_config(lang TEXT) RETURNS regconfig AS $$
+SELECT CASE split_part(lower(coalesce(lang, '')), '-', 1)
+           WHEN 'ar' THEN 'arabic'
+           WHEN 'da' THEN 'danish'
+           WHEN 'de' THEN 'german'
+           WHEN 'en' THEN 'english'
+           WHEN 'es' THEN 'spanish'
+           WHEN 'fi' THEN 'finnish'
+           WHEN 'fr' THEN 'french'
+           WHEN 'hu' THEN 'hungarian'
+           WHEN 'id' THEN 'indonesian'
+           WHEN 'it' THEN 'italian'
+           WHEN 'nl' THEN 'dutch'
+           WHEN 'no' THEN 'norwegian'
+           WHEN 'pt' THEN 'portuguese'
+           WHEN 'ro' THEN 'romanian'
+           WHEN 'ru' THEN 'russian'
+           WHEN 'sv' THEN 'swedish'
+           WHEN 'tr' THEN 'turkish'
+           ELSE 'simple'
+       END::regconfig;
+$$ LANGUAGE SQL IMMUTABLE;
+-- +goose StatementEnd
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_config regconfig NOT NULL DEFAULT 'simple';
+
+UPDATE tasks t SET search_config = task_search_config(u.preferred_language)
+FROM users u WHERE u.id = t.owner_id;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector(search_config, coalesce(title, '')), 'A') ||
+    setweight(to_tsvector(search_config, coalesce(details, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector);
+
+-- Index new tasks with their owner's language
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION tasks_set_search_config() RETURNS TRIGGER AS $$
+BEGIN
+    SELECT task_search_config(preferred_language) INTO NEW.search_config
+    FROM users WHERE id = NEW.owner_id;
+    NEW.search_config := coalesce(NEW.search_config, 'simple'::regconfig);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER tasks_set_search_config
+    BEFORE INSERT ON tasks
+    FOR EACH ROW EXECUTE FUNCTION tasks_set_search_config();
+
+-- Re-index a user's tasks when their language changes
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION users_sync_task_search_config() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE tasks SET search_config = task_search_config(NEW.preferred_language)
+    WHERE owner_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER users_sync_task_search_config
+    AFTER UPDATE OF preferred_language ON users
+    FOR EACH ROW
+    WHEN (OLD.preferred_language IS DISTINCT FROM NEW.preferred_language)
+    EXECUTE FUNCTION users_sync_task_search_config();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS users_sync_task_search_config ON users;
+DROP FUNCTION IF EXISTS users_sync_task_search_config();
+DROP TRIGGER IF EXISTS tasks_set_search_config ON tasks;
+DROP FUNCTION IF EXISTS tasks_set_search_config();
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_config;
+DROP FUNCTION IF EXISTS task_search_config(TEXT);
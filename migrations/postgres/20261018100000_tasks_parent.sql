@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tasks(id) ON DELETE CASCADE;
+ALTER TABLE tasks ADD CONSTRAINT tasks_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_parent ON tasks (owner_id, parent_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tasks_owner_parent;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_parent_not_self;
+ALTER TABLE tasks DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS labels (
+                                      id UUID PRIMARY KEY,
+                                      owner_id UUID NOT NULL,
+                                      name VARCHAR(64) NOT NULL,
+                                      color VARCHAR(7),
+                                      created_at TIMESTAMP,
+                                      updated_at TIMESTAMP,
+                                      FOREIGN KEY (owner_id) REFERENCES users(id),
+                                      UNIQUE (owner_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS task_labels (
+                                           task_id UUID NOT NULL,
+                                           label_id UUID NOT NULL,
+                                           FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+                                           FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE,
+                                           PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_labels_label_id ON task_labels (label_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS task_labels;
+DROP TABLE IF EXISTS labels;
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS projects (
+                                        id UUID PRIMARY KEY,
+                                        owner_id UUID NOT NULL,
+                                        name VARCHAR(128) NOT NULL,
+                                        description TEXT NOT NULL DEFAULT '',
+                                        color VARCHAR(7) NOT NULL DEFAULT '',
+                                        is_archived BOOLEAN NOT NULL DEFAULT FALSE,
+                                        sort_order INT NOT NULL DEFAULT 0,
+                                        created_at TIMESTAMP,
+                                        updated_at TIMESTAMP,
+                                        FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_owner_sort ON projects (owner_id, sort_order);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id UUID REFERENCES projects(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_project ON tasks (owner_id, project_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tasks_owner_project;
+ALTER TABLE tasks DROP COLUMN IF EXISTS project_id;
+DROP INDEX IF EXISTS idx_projects_owner_sort;
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS task_series (
+                                           id UUID PRIMARY KEY,
+                                           owner_id UUID NOT NULL,
+                                           rrule TEXT NOT NULL,
+                                           dtstart TIMESTAMP NOT NULL,
+                                           last_occurrence INT,
+                                           title VARCHAR(255) NOT NULL,
+                                           details TEXT NOT NULL DEFAULT '',
+                                           priority INT,
+                                           project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+                                           parent_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+                                           label_ids UUID[] NOT NULL DEFAULT '{}',
+                                           start_offset BIGINT,
+                                           due_offset BIGINT,
+                                           created_at TIMESTAMP,
+                                           updated_at TIMESTAMP,
+                                           FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES task_series(id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS occurrence INT NOT NULL DEFAULT 0;
+
+-- At most one task per occurrence, so completing twice never spawns twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_series_occurrence ON tasks (series_id, occurrence)
+    WHERE series_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tasks_series_occurrence;
+ALTER TABLE tasks DROP COLUMN IF EXISTS occurrence;
+ALTER TABLE tasks DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS task_series;
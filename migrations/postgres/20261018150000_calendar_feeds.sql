@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+                                              id UUID PRIMARY KEY,
+                                              owner_id UUID NOT NULL,
+                                              created_at TIMESTAMP NOT NULL,
+                                              expires_at TIMESTAMP NOT NULL,
+                                              FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_feeds_owner_id ON calendar_feeds (owner_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS calendar_feeds;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS task_events (
+                                           id UUID PRIMARY KEY,
+                                           task_id UUID NOT NULL,
+                                           owner_id UUID NOT NULL,
+                                           actor_id UUID,
+                                           type VARCHAR(32) NOT NULL,
+                                           changes JSONB NOT NULL DEFAULT '{}',
+                                           request_id VARCHAR(128) NOT NULL DEFAULT '',
+                                           created_at TIMESTAMP NOT NULL,
+                                           FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events (task_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS task_events;
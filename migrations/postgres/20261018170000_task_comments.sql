@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS task_comments (
+                                             id UUID PRIMARY KEY,
+                                             task_id UUID NOT NULL,
+                                             thread_id UUID NOT NULL,
+                                             parent_id UUID,
+                                             author_id UUID NOT NULL,
+                                             body TEXT NOT NULL,
+                                             created_at TIMESTAMP NOT NULL,
+                                             updated_at TIMESTAMP NOT NULL,
+                                             edited_at TIMESTAMP,
+                                             FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+                                             FOREIGN KEY (parent_id) REFERENCES task_comments(id) ON DELETE CASCADE,
+                                             FOREIGN KEY (author_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments (task_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_task_comments_thread_id ON task_comments (thread_id);
+
+CREATE TABLE IF NOT EXISTS task_comment_revisions (
+                                                      id UUID PRIMARY KEY,
+                                                      comment_id UUID NOT NULL,
+                                                      body TEXT NOT NULL,
+                                                      created_at TIMESTAMP NOT NULL,
+                                                      FOREIGN KEY (comment_id) REFERENCES task_comments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_comment_revisions_comment_id ON task_comment_revisions (comment_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS task_comment_revisions;
+DROP TABLE IF EXISTS task_comments;
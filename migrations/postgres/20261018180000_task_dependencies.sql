@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS task_dependencies (
+                                                 task_id UUID NOT NULL,
+                                                 blocker_id UUID NOT NULL,
+                                                 owner_id UUID NOT NULL,
+                                                 created_at TIMESTAMP NOT NULL,
+                                                 FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+                                                 FOREIGN KEY (blocker_id) REFERENCES tasks(id) ON DELETE CASCADE,
+                                                 PRIMARY KEY (task_id, blocker_id),
+                                                 CHECK (task_id <> blocker_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocker_id ON task_dependencies (blocker_id);
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_owner_id ON task_dependencies (owner_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS task_dependencies;
@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS project_statuses (
+                                                id UUID PRIMARY KEY,
+                                                project_id UUID NOT NULL,
+                                                owner_id UUID NOT NULL,
+                                                name VARCHAR(64) NOT NULL,
+                                                color VARCHAR(7),
+                                                is_done BOOLEAN NOT NULL DEFAULT FALSE,
+                                                position TEXT COLLATE "C" NOT NULL,
+                                                created_at TIMESTAMP NOT NULL,
+                                                updated_at TIMESTAMP NOT NULL,
+                                                FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+                                                UNIQUE (project_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_statuses_project_id ON project_statuses (project_id, position);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status_id UUID REFERENCES project_statuses(id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS position TEXT COLLATE "C" NOT NULL DEFAULT '';
+
+-- Rank the existing tasks of every project by creation. The keys are valid
+-- lexorank keys: lowercase base 36 digits not ending with 0.
+UPDATE tasks
+SET position = ranked.position
+FROM (
+         SELECT id, lpad(row_number() OVER (PARTITION BY owner_id, project_id ORDER BY created_at, id)::text, 8, '0') || 'i' AS position
+         FROM tasks
+     ) ranked
+WHERE tasks.id = ranked.id;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_board ON tasks (project_id, status_id, position);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tasks_board;
+ALTER TABLE tasks DROP COLUMN IF EXISTS position;
+ALTER TABLE tasks DROP COLUMN IF EXISTS status_id;
+DROP TABLE IF EXISTS project_statuses;
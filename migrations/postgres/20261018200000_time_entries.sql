@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_minutes INTEGER CHECK (estimate_minutes > 0);
+
+CREATE TABLE IF NOT EXISTS time_entries (
+                                            id UUID PRIMARY KEY,
+                                            task_id UUID NOT NULL,
+                                            owner_id UUID NOT NULL,
+                                            started_at TIMESTAMP NOT NULL,
+                                            ended_at TIMESTAMP NOT NULL,
+                                            note TEXT NOT NULL DEFAULT '',
+                                            source VARCHAR(16) NOT NULL,
+                                            created_at TIMESTAMP NOT NULL,
+                                            updated_at TIMESTAMP NOT NULL,
+                                            CHECK (ended_at > started_at),
+                                            FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+                                            FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON time_entries (task_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_time_entries_owner_id ON time_entries (owner_id, started_at, ended_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS time_entries;
+ALTER TABLE tasks DROP COLUMN IF EXISTS estimate_minutes;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS focus_sessions (
+                                              id UUID PRIMARY KEY,
+                                              task_id UUID NOT NULL,
+                                              owner_id UUID NOT NULL,
+                                              work_seconds INTEGER NOT NULL,
+                                              break_seconds INTEGER NOT NULL,
+                                              focused_seconds INTEGER NOT NULL,
+                                              interruptions INTEGER NOT NULL DEFAULT 0,
+                                              completed BOOLEAN NOT NULL,
+                                              started_at TIMESTAMP NOT NULL,
+                                              ended_at TIMESTAMP NOT NULL,
+                                              FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+                                              FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_focus_sessions_owner_id ON focus_sessions (owner_id, started_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS focus_sessions;
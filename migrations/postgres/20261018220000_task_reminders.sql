@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS task_reminders (
+                                              id UUID PRIMARY KEY,
+                                              task_id UUID NOT NULL,
+                                              owner_id UUID NOT NULL,
+                                              remind_at TIMESTAMP,
+                                              offset_minutes INTEGER,
+                                              channels TEXT[] NOT NULL,
+                                              webhook_url TEXT NOT NULL DEFAULT '',
+                                              status VARCHAR(16) NOT NULL,
+                                              attempts INTEGER NOT NULL DEFAULT 0,
+                                              next_attempt_at TIMESTAMP,
+                                              sent_at TIMESTAMP,
+                                              created_at TIMESTAMP NOT NULL,
+                                              updated_at TIMESTAMP NOT NULL,
+                                              CHECK ((remind_at IS NULL) <> (offset_minutes IS NULL)),
+                                              FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+                                              FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_reminders_task_id ON task_reminders (task_id);
+CREATE INDEX IF NOT EXISTS idx_task_reminders_pending ON task_reminders (remind_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS reminder_deliveries (
+                                                   id UUID PRIMARY KEY,
+                                                   reminder_id UUID NOT NULL,
+                                                   channel VARCHAR(16) NOT NULL,
+                                                   attempt INTEGER NOT NULL,
+                                                   status VARCHAR(16) NOT NULL,
+                                                   error TEXT NOT NULL DEFAULT '',
+                                                   created_at TIMESTAMP NOT NULL,
+                                                   FOREIGN KEY (reminder_id) REFERENCES task_reminders(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminder_deliveries_reminder_id ON reminder_deliveries (reminder_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS reminder_deliveries;
+DROP TABLE IF EXISTS task_reminders;
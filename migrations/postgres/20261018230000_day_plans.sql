@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS day_plan_tasks (
+                                              owner_id UUID NOT NULL,
+                                              day DATE NOT NULL,
+                                              task_id UUID NOT NULL,
+                                              position INTEGER NOT NULL,
+                                              created_at TIMESTAMP NOT NULL,
+                                              PRIMARY KEY (owner_id, day, task_id),
+                                              FOREIGN KEY (owner_id) REFERENCES users(id),
+                                              FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_day_plan_tasks_task_id ON day_plan_tasks (task_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS day_plan_tasks;
@@ -0,0 +1,36 @@
+-- +goose Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+
+-- Completed tasks get the time of their last completion event, or their last
+-- update when they have none.
+UPDATE tasks t SET completed_at = coalesce(
+    (SELECT max(e.created_at) FROM task_events e WHERE e.task_id = t.id AND e.type = 'completed'),
+    t.updated_at
+)
+WHERE t.is_completed;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_completed_at ON tasks (owner_id, completed_at) WHERE completed_at IS NOT NULL;
+
+-- Keep completed_at in step with is_completed, whichever way a task is written
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION tasks_set_completed_at() RETURNS TRIGGER AS $$
+BEGIN
+    IF NOT NEW.is_completed THEN
+        NEW.completed_at := NULL;
+    ELSIF TG_OP = 'INSERT' OR NOT OLD.is_completed THEN
+        NEW.completed_at := coalesce(NEW.completed_at, NEW.updated_at);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER tasks_set_completed_at
+    BEFORE INSERT OR UPDATE OF is_completed ON tasks
+    FOR EACH ROW EXECUTE FUNCTION tasks_set_completed_at();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS tasks_set_completed_at ON tasks;
+DROP FUNCTION IF EXISTS tasks_set_completed_at();
+DROP INDEX IF EXISTS idx_tasks_owner_completed_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+
+-- Every write of a task bumps its version, which clients get as its ETag
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION tasks_bump_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER tasks_bump_version
+    BEFORE UPDATE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION tasks_bump_version();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS tasks_bump_version ON tasks;
+DROP FUNCTION IF EXISTS tasks_bump_version();
+ALTER TABLE tasks DROP COLUMN IF EXISTS version;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Occurrences are expanded in the time zone the series was created in, so
+-- that they keep their wall clock time across DST changes
+ALTER TABLE task_series ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- +goose Down
+ALTER TABLE task_series DROP COLUMN IF EXISTS timezone;
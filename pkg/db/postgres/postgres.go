@@ -42,7 +42,7 @@ func NewPostgresDB(dsn string, options ...Option) (DB, error) {
 }
 
 func (c *db) Pool() Pool {
-	return c.pgxPool
+	return txAwarePool{c.pgxPool}
 }
 
 func (c *db) QueryBuilder() squirrel.StatementBuilderType {
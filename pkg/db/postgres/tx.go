@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type txKey struct{}
+
+// WithTx returns a copy of ctx carrying tx. Queries issued through Pool with
+// that context run inside tx instead of on a pooled connection.
+func WithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx, if any.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// txAwarePool routes queries to the transaction carried by their context.
+// Beginning a transaction inside one creates a savepoint.
+type txAwarePool struct {
+	*pgxpool.Pool
+}
+
+func (p txAwarePool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Query(ctx, sql, args...)
+	}
+
+	return p.Pool.Query(ctx, sql, args...)
+}
+
+func (p txAwarePool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.QueryRow(ctx, sql, args...)
+	}
+
+	return p.Pool.QueryRow(ctx, sql, args...)
+}
+
+func (p txAwarePool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Exec(ctx, sql, arguments...)
+	}
+
+	return p.Pool.Exec(ctx, sql, arguments...)
+}
+
+func (p txAwarePool) BeginTx(ctx context.Context, options pgx.TxOptions) (pgx.Tx, error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Begin(ctx)
+	}
+
+	return p.Pool.BeginTx(ctx, options)
+}
@@ -20,7 +20,11 @@ type CmdableSubset interface {
 	Exists(ctx context.Context, keys ...string) *goredis.IntCmd
 	Get(ctx context.Context, key string) *goredis.StringCmd
 	Set(ctx context.Context, key string, value any, expiration time.Duration) *goredis.StatusCmd
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) *goredis.BoolCmd
+	GetDel(ctx context.Context, key string) *goredis.StringCmd
 	Del(ctx context.Context, keys ...string) *goredis.IntCmd
+	Incr(ctx context.Context, key string) *goredis.IntCmd
+	Eval(ctx context.Context, script string, keys []string, args ...any) *goredis.Cmd
 	MGet(ctx context.Context, keys ...string) *goredis.SliceCmd
 	MSet(ctx context.Context, values ...any) *goredis.StatusCmd
 	io.Closer
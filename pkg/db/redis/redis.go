@@ -65,10 +65,26 @@ func (c *client) Set(ctx context.Context, key string, value any, expiration time
 	return c.rdb.Set(ctx, key, value, expiration)
 }
 
+func (c *client) SetNX(ctx context.Context, key string, value any, expiration time.Duration) *goredis.BoolCmd {
+	return c.rdb.SetNX(ctx, key, value, expiration)
+}
+
+func (c *client) GetDel(ctx context.Context, key string) *goredis.StringCmd {
+	return c.rdb.GetDel(ctx, key)
+}
+
 func (c *client) Del(ctx context.Context, keys ...string) *goredis.IntCmd {
 	return c.rdb.Del(ctx, keys...)
 }
 
+func (c *client) Incr(ctx context.Context, key string) *goredis.IntCmd {
+	return c.rdb.Incr(ctx, key)
+}
+
+func (c *client) Eval(ctx context.Context, script string, keys []string, args ...any) *goredis.Cmd {
+	return c.rdb.Eval(ctx, script, keys, args...)
+}
+
 func (c *client) MGet(ctx context.Context, keys ...string) *goredis.SliceCmd {
 	return c.rdb.MGet(ctx, keys...)
 }
@@ -166,7 +166,12 @@ func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 }
 
 func TransportWithTracing() http.RoundTripper {
+	return WithTracing(http.DefaultTransport)
+}
+
+// WithTracing traces the requests made through transport.
+func WithTracing(transport http.RoundTripper) http.RoundTripper {
 	return &tracingTransport{
-		transport: http.DefaultTransport,
+		transport: transport,
 	}
 }
@@ -5,9 +5,12 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"io"
 )
 
+var ErrShortCiphertext = errors.New("ciphertext too short")
+
 type AEAD struct {
 	cipher.AEAD
 }
@@ -71,6 +74,10 @@ func (a *AEAD) Decrypt(encodedCiphertext []byte, data ...[]byte) ([]byte, error)
 		return nil, err
 	}
 
+	if len(ciphertext) < a.NonceSize() {
+		return nil, ErrShortCiphertext
+	}
+
 	nonce := ciphertext[:a.NonceSize()]
 	encrypted := ciphertext[a.NonceSize():]
 
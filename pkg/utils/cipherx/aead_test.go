@@ -0,0 +1,120 @@
+package cipherx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func newTestAEAD(t *testing.T) *AEAD {
+	t.Helper()
+
+	aead, err := NewAEAD(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("NewAEAD() error = %v", err)
+	}
+
+	return aead
+}
+
+func TestNewAEADRejectsKeySize(t *testing.T) {
+	_, err := NewAEAD([]byte("short"))
+	if err == nil {
+		t.Error("NewAEAD() error = nil, want an error")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	aead := newTestAEAD(t)
+	plaintext := []byte(`{"sort":"-priority"}`)
+
+	first, err := aead.Encrypt(plaintext, []byte("owner"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	second, err := aead.Encrypt(plaintext, []byte("owner"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if bytes.Equal(first, second) {
+		t.Error("Encrypt() returned the same ciphertext twice, want a fresh nonce")
+	}
+
+	got, err := aead.Decrypt(first, []byte("owner"))
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt() = %q, want %q", got, plaintext)
+	}
+}
+
+func TestEncryptEmpty(t *testing.T) {
+	aead := newTestAEAD(t)
+
+	ciphertext, err := aead.Encrypt(nil)
+	if err != nil || len(ciphertext) != 0 {
+		t.Errorf("Encrypt(nil) = %q, %v, want empty", ciphertext, err)
+	}
+
+	plaintext, err := aead.Decrypt(nil)
+	if err != nil || len(plaintext) != 0 {
+		t.Errorf("Decrypt(nil) = %q, %v, want empty", plaintext, err)
+	}
+}
+
+func TestDecryptRejects(t *testing.T) {
+	aead := newTestAEAD(t)
+
+	ciphertext, err := aead.Encrypt([]byte("secret"), []byte("owner"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(string(ciphertext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned invalid base64: %v", err)
+	}
+	raw[len(raw)-1] ^= 1
+	tampered := []byte(base64.StdEncoding.EncodeToString(raw))
+
+	short := []byte(base64.StdEncoding.EncodeToString([]byte("tiny")))
+	other, err := NewAEAD(bytes.Repeat([]byte{2}, 32))
+	if err != nil {
+		t.Fatalf("NewAEAD() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		aead       *AEAD
+		ciphertext []byte
+		data       []byte
+	}{
+		{name: "tampered", aead: aead, ciphertext: tampered, data: []byte("owner")},
+		{name: "other data", aead: aead, ciphertext: ciphertext, data: []byte("intruder")},
+		{name: "no data", aead: aead, ciphertext: ciphertext},
+		{name: "other key", aead: other, ciphertext: ciphertext, data: []byte("owner")},
+		{name: "not base64", aead: aead, ciphertext: []byte("%%%"), data: []byte("owner")},
+		{name: "short", aead: aead, ciphertext: short, data: []byte("owner")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data [][]byte
+			if tt.data != nil {
+				data = append(data, tt.data)
+			}
+
+			plaintext, err := tt.aead.Decrypt(tt.ciphertext, data...)
+			if err == nil {
+				t.Errorf("Decrypt() = %q, want an error", plaintext)
+			}
+		})
+	}
+
+	_, err = aead.Decrypt(short)
+	if !errors.Is(err, ErrShortCiphertext) {
+		t.Errorf("Decrypt(short) error = %v, want %v", err, ErrShortCiphertext)
+	}
+}
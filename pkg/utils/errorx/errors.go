@@ -11,8 +11,60 @@ var (
 
 	ErrInvalidProvider = errors.New("invalid provider")
 
+	ErrInvalidPageToken = errors.New("invalid page token")
+
 	ErrUserNotFound = errors.New("user not found")
 	ErrLinkNotFound = errors.New("link not found")
+	ErrTaskNotFound = errors.New("task not found")
+
+	ErrTaskModified    = errors.New("the task was modified since it was read, fetch it again")
+	ErrIfMatchRequired = errors.New("the If-Match header is required, send the ETag of the task")
+	ErrVersionRequired = errors.New("the version of every task is required, send it in versions")
+	ErrInvalidIfMatch  = errors.New("the If-Match header must hold a single strong entity tag or *")
+
+	ErrLabelNotFound = errors.New("label not found")
+	ErrLabelExists   = errors.New("label already exists")
+
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrStatusNotFound   = errors.New("status not found")
+	ErrStatusExists     = errors.New("status already exists")
+	ErrTaskNotInProject = errors.New("the task is not in a project")
+	ErrInvalidPlacement = errors.New("the neighbours are not next to each other in the same column")
+
+	ErrParentTaskNotFound = errors.New("parent task not found")
+	ErrTaskCycle          = errors.New("a task can not be nested under itself or its subtasks")
+	ErrParentTaskTrashed  = errors.New("the parent task is in the trash, restore it first")
+
+	ErrDependencyCycle    = errors.New("the dependency would make the task block itself")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrTaskBlocked        = errors.New("the task is blocked by open tasks")
+
+	ErrTaskNotRecurring      = errors.New("task is not recurring")
+	ErrRecurrenceWithoutDate = errors.New("a recurring task needs a start or due date")
+
+	ErrInvalidFeedToken = errors.New("invalid or revoked calendar feed token")
+
+	ErrCommentNotFound       = errors.New("comment not found")
+	ErrParentCommentNotFound = errors.New("parent comment not found")
+	ErrNotCommentAuthor      = errors.New("only the author can change a comment")
+
+	ErrTimerRunning       = errors.New("a timer is already running")
+	ErrNoRunningTimer     = errors.New("no timer is running")
+	ErrTimeEntryNotFound  = errors.New("time entry not found")
+	ErrInvalidTimeSpan    = errors.New("a time entry must end after it starts")
+	ErrInvalidReportRange = errors.New("the report range must end after it starts and span at most a year")
+
+	ErrFocusSessionRunning = errors.New("a focus session is already running")
+	ErrNoFocusSession      = errors.New("no focus session is running")
+
+	ErrReminderNotFound       = errors.New("reminder not found")
+	ErrInvalidReminder        = errors.New("a reminder needs either a time or an offset before the due date")
+	ErrReminderWithoutDueDate = errors.New("an offset reminder needs a task with a due date")
+	ErrUnsupportedChannel     = errors.New("unsupported reminder channel")
+	ErrWebhookURLRequired     = errors.New("the webhook channel needs a webhook URL")
+	ErrWebhookURLNotAllowed   = errors.New("the webhook URL must resolve to public addresses only")
+
+	ErrInvalidAgendaRange = errors.New("the agenda range must end after it starts and span at most 92 days")
 )
 
 func handleHTTPError(err error) {}
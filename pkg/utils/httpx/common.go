@@ -2,6 +2,11 @@ package httpx
 
 import "net/http"
 
+const (
+	MediaTypeJSON       = "application/json"
+	MediaTypeMergePatch = "application/merge-patch+json"
+)
+
 func NoContent(w http.ResponseWriter) error {
 	w.WriteHeader(http.StatusNoContent)
 	_, err := w.Write([]byte{})
@@ -0,0 +1,115 @@
+// Package icalx writes iCalendar (RFC 5545) objects.
+package icalx
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest a content line may be before folding.
+const maxLineOctets = 75
+
+const dateTimeLayout = "20060102T150405Z"
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// Component is a calendar component such as VCALENDAR, VTODO or VEVENT.
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// Property is a content line. Value is written as is, Params as NAME=VALUE.
+type Property struct {
+	Name   string
+	Params []string
+	Value  string
+}
+
+func NewComponent(name string) *Component {
+	return &Component{Name: name}
+}
+
+// Add appends a property whose value is already formatted.
+func (c *Component) Add(name, value string, params ...string) {
+	c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// AddText appends a TEXT property, escaping its value.
+func (c *Component) AddText(name, text string) {
+	c.Add(name, EscapeText(text))
+}
+
+// AddTime appends a DATE-TIME property in UTC.
+func (c *Component) AddTime(name string, t time.Time) {
+	c.Add(name, FormatDateTime(t))
+}
+
+// AddComponent nests a component.
+func (c *Component) AddComponent(child *Component) {
+	c.Components = append(c.Components, child)
+}
+
+// Encode writes the component with CRLF line endings, folding lines longer
+// than 75 octets without splitting UTF-8 sequences.
+func (c *Component) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	c.encode(bw)
+	return bw.Flush()
+}
+
+func (c *Component) encode(w *bufio.Writer) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		line := p.Name
+		for _, param := range p.Params {
+			line += ";" + param
+		}
+		writeLine(w, line+":"+p.Value)
+	}
+	for _, child := range c.Components {
+		child.encode(w)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		_, _ = w.WriteString(line[:cut])
+		_, _ = w.WriteString("\r\n ")
+		line = line[cut:]
+
+		// Continuation lines start with a space that counts towards the limit.
+		limit = maxLineOctets - 1
+	}
+
+	_, _ = w.WriteString(line)
+	_, _ = w.WriteString("\r\n")
+}
+
+// EscapeText escapes a TEXT value.
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// FormatDateTime formats t as a UTC DATE-TIME.
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
@@ -0,0 +1,114 @@
+package icalx
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Buy milk", want: "Buy milk"},
+		{text: `a\b`, want: `a\\b`},
+		{text: "one; two, three", want: `one\; two\, three`},
+		{text: "line\r\nline\nline\rline", want: `line\nline\nline\nline`},
+		{text: `\n`, want: `\\n`},
+		{text: "colon: stays", want: "colon: stays"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := EscapeText(tt.text); got != tt.want {
+				t.Errorf("EscapeText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDateTime(t *testing.T) {
+	at := time.Date(2026, time.October, 19, 9, 5, 7, 0, time.FixedZone("ICT", 7*60*60))
+	if got := FormatDateTime(at); got != "20261019T020507Z" {
+		t.Errorf("FormatDateTime() = %q, want %q", got, "20261019T020507Z")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	cal := NewComponent("VCALENDAR")
+	cal.Add("VERSION", "2.0")
+	todo := NewComponent("VTODO")
+	todo.AddText("SUMMARY", "Pay rent, water; gas")
+	todo.Add("DUE", "20261020", "VALUE=DATE")
+	todo.AddTime("DTSTAMP", time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC))
+	cal.AddComponent(todo)
+
+	var b strings.Builder
+	err := cal.Encode(&b)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	want := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"SUMMARY:Pay rent\\, water\\; gas\r\n" +
+		"DUE;VALUE=DATE:20261020\r\n" +
+		"DTSTAMP:20261018T000000Z\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	if b.String() != want {
+		t.Errorf("Encode() = %q, want %q", b.String(), want)
+	}
+}
+
+// TestEncodeFolds checks that long lines are folded at 75 octets, the
+// leading space of continuation lines included, without splitting a UTF-8
+// sequence, and that unfolding gives the line back.
+func TestEncodeFolds(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "short", text: "Buy milk"},
+		{name: "exactly 75", text: strings.Repeat("a", 75-len("SUMMARY:"))},
+		{name: "ascii", text: strings.Repeat("abcdefghij", 20)},
+		{name: "multibyte", text: strings.Repeat("é", 50) + strings.Repeat("日本語", 30)},
+		{name: "emoji", text: "x" + strings.Repeat("🙂", 60)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewComponent("VTODO")
+			c.AddText("SUMMARY", tt.text)
+
+			var b strings.Builder
+			err := c.Encode(&b)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			out := strings.TrimSuffix(b.String(), "\r\n")
+			lines := strings.Split(out, "\r\n")
+			for i, line := range lines {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %d is %d octets long, want at most %d", i, len(line), maxLineOctets)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d = %q splits a UTF-8 sequence", i, line)
+				}
+				if i > 1 && i < len(lines)-1 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d = %q, want a leading space", i, line)
+				}
+			}
+
+			unfolded := strings.ReplaceAll(out, "\r\n ", "")
+			want := "BEGIN:VTODO\r\nSUMMARY:" + tt.text + "\r\nEND:VTODO"
+			if unfolded != want {
+				t.Errorf("unfolded = %q, want %q", unfolded, want)
+			}
+		})
+	}
+}
@@ -38,19 +38,19 @@ func GenerateToken(secret []byte, expiry time.Duration, opts ...Option) string {
 func ParseToken(tokenString string, secret []byte, issuer ...string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
 		return secret, nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 	if err != nil {
 		return nil, err
 	}
 
 	claims, ok := token.Claims.(*Claims)
-	if ok && token.Valid {
-		return claims, nil
+	if !ok || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
 	}
 
 	if len(issuer) > 0 && claims.Issuer != issuer[0] {
 		return nil, jwt.ErrTokenInvalidClaims
 	}
 
-	return nil, jwt.ErrTokenInvalidClaims
+	return claims, nil
 }
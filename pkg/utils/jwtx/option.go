@@ -2,6 +2,12 @@ package jwtx
 
 type Option func(claims *Claims)
 
+func WithID(id string) Option {
+	return func(claims *Claims) {
+		claims.ID = id
+	}
+}
+
 func WithIssuer(issuer string) Option {
 	return func(claims *Claims) {
 		claims.Issuer = issuer
@@ -0,0 +1,135 @@
+// Package lexorank generates string keys that sort between two other keys, so
+// that an item can be moved in an ordered list by rewriting only its own key.
+//
+// Keys are made of the digits 0-9 and the lowercase letters a-z, compared
+// byte by byte, and never end with "0" so that there is always room for a key
+// before them. The empty string stands for the start or the end of the list.
+package lexorank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// stepDepth is the number of leading digits Next increments, leaving room for
+// 36^stepDepth keys appended one after the other before keys grow longer.
+const stepDepth = 4
+
+var (
+	ErrInvalidKey   = errors.New("invalid rank key")
+	ErrInvalidRange = errors.New("rank keys out of order")
+)
+
+// Between returns a key sorting after a and before b. An empty a means the
+// start of the list and an empty b its end.
+func Between(a, b string) (string, error) {
+	if !valid(a) || !valid(b) {
+		return "", ErrInvalidKey
+	}
+
+	if b != "" && a >= b {
+		return "", ErrInvalidRange
+	}
+
+	return midpoint(a, b), nil
+}
+
+// Next returns a key sorting after a, at the end of the list. Unlike
+// Between(a, ""), which halves the room left after a, it steps by a fixed
+// amount so that appending many keys keeps them short.
+func Next(a string) (string, error) {
+	if !valid(a) {
+		return "", ErrInvalidKey
+	}
+
+	if a == "" {
+		return midpoint("", ""), nil
+	}
+
+	key := []byte(a)
+	if len(key) > stepDepth {
+		key = key[:stepDepth]
+	}
+	for len(key) < stepDepth {
+		key = append(key, digits[0])
+	}
+
+	for i := len(key) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, key[i])
+		if d < len(digits)-1 {
+			key[i] = digits[d+1]
+			return strings.TrimRight(string(key), digits[:1]), nil
+		}
+		key[i] = digits[0]
+	}
+
+	// Every leading digit is the last one: there is no room left to step.
+	return midpoint(a, ""), nil
+}
+
+func valid(key string) bool {
+	if key == "" {
+		return true
+	}
+
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+
+	return key[len(key)-1] != digits[0]
+}
+
+// midpoint returns a key between a and b, b being empty for no upper bound.
+// It is the shortest such key, roughly halfway between them.
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the common prefix, a being padded with zeros.
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	lo, hi := 0, len(digits)
+	if a != "" {
+		lo = strings.IndexByte(digits, a[0])
+	}
+	if b != "" {
+		hi = strings.IndexByte(digits, b[0])
+	}
+
+	if hi-lo > 1 {
+		return string(digits[(lo+hi)/2])
+	}
+
+	// The first digits are consecutive. A longer b has room right at its
+	// first digit, otherwise the key continues after the first digit of a.
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	return string(digits[lo]) + midpoint(suffix(a, 1), "")
+}
+
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+
+	return digits[0]
+}
+
+func suffix(key string, n int) string {
+	if n < len(key) {
+		return key[n:]
+	}
+
+	return ""
+}
@@ -0,0 +1,161 @@
+package lexorank
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{a: "", b: "", want: "i"},
+		{a: "", b: "i", want: "9"},
+		{a: "i", b: "", want: "r"},
+		{a: "", b: "1", want: "0i"},
+		{a: "z", b: "", want: "zi"},
+		{a: "1", b: "3", want: "2"},
+		{a: "1", b: "2", want: "1i"},
+		{a: "1", b: "1i", want: "19"},
+		{a: "1i", b: "2", want: "1r"},
+		{a: "", b: "01", want: "00i"},
+		{a: "y", b: "z", want: "yi"},
+		{a: "1", b: "21", want: "2"},
+		{a: "az", b: "b", want: "azi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Between() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Between(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetweenInvalid(t *testing.T) {
+	tests := []struct {
+		a, b string
+		err  error
+	}{
+		{a: "2", b: "1", err: ErrInvalidRange},
+		{a: "1", b: "1", err: ErrInvalidRange},
+		{a: "1i", b: "1", err: ErrInvalidRange},
+		{a: "10", b: "", err: ErrInvalidKey},
+		{a: "", b: "0", err: ErrInvalidKey},
+		{a: "A", b: "", err: ErrInvalidKey},
+		{a: "", b: "a-b", err: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		_, err := Between(tt.a, tt.b)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Between(%q, %q) error = %v, want %v", tt.a, tt.b, err, tt.err)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		a    string
+		want string
+	}{
+		{a: "", want: "i"},
+		{a: "i", want: "i001"},
+		{a: "1", want: "1001"},
+		{a: "abcdef", want: "abce"},
+		{a: "1zzz", want: "2"},
+		{a: "zzzy", want: "zzzz"},
+		{a: "zzzz", want: "zzzzi"},
+		{a: "zzzzzz", want: "zzzzzzi"},
+	}
+
+	for _, tt := range tests {
+		got, err := Next(tt.a)
+		if err != nil {
+			t.Fatalf("Next(%q) error = %v", tt.a, err)
+		}
+		if got != tt.want {
+			t.Errorf("Next(%q) = %q, want %q", tt.a, got, tt.want)
+		}
+	}
+
+	_, err := Next("a0")
+	if !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Next(%q) error = %v, want %v", "a0", err, ErrInvalidKey)
+	}
+}
+
+// TestBetweenInvariants inserts keys over and over at the start, at the end
+// and between neighbours of a list, checking every key against its bounds.
+func TestBetweenInvariants(t *testing.T) {
+	keys := []string{"i"}
+	for round := range 300 {
+		at := round * 7 % (len(keys) + 1)
+
+		var a, b string
+		if at > 0 {
+			a = keys[at-1]
+		}
+		if at < len(keys) {
+			b = keys[at]
+		}
+
+		key, err := Between(a, b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q) error = %v", a, b, err)
+		}
+		checkKey(t, key, a, b)
+
+		keys = append(keys[:at], append([]string{key}, keys[at:]...)...)
+	}
+
+	// Halving the same gap over and over grows keys without breaking order.
+	a, b := "1", "2"
+	for i := range 100 {
+		key, err := Between(a, b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q) error = %v", a, b, err)
+		}
+		checkKey(t, key, a, b)
+
+		if i%2 == 0 {
+			a = key
+		} else {
+			b = key
+		}
+	}
+}
+
+func TestNextInvariants(t *testing.T) {
+	var a string
+	for range 2000 {
+		key, err := Next(a)
+		if err != nil {
+			t.Fatalf("Next(%q) error = %v", a, err)
+		}
+		checkKey(t, key, a, "")
+
+		if len(key) > stepDepth {
+			t.Fatalf("Next(%q) = %q, want at most %d digits", a, key, stepDepth)
+		}
+		a = key
+	}
+}
+
+func checkKey(t *testing.T, key, a, b string) {
+	t.Helper()
+
+	if !valid(key) || strings.HasSuffix(key, "0") {
+		t.Fatalf("key %q between %q and %q is not valid", key, a, b)
+	}
+	if key <= a || (b != "" && key >= b) {
+		t.Fatalf("key %q does not sort between %q and %q", key, a, b)
+	}
+}
@@ -0,0 +1,36 @@
+// Package markdown renders user-written GitHub Flavored Markdown to HTML that
+// is safe to embed in a page.
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	renderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy allows the formatting produced from Markdown and strips the rest,
+	// including raw HTML written in the source. Links get rel="nofollow".
+	policy = func() *bluemonday.Policy {
+		p := bluemonday.UGCPolicy()
+		p.AllowAttrs("type", "checked", "disabled").OnElements("input")
+		p.RequireNoFollowOnLinks(true)
+		p.AddTargetBlankToFullyQualifiedLinks(true)
+		return p
+	}()
+)
+
+// Render converts src to sanitised HTML.
+func Render(src string) (string, error) {
+	var buf bytes.Buffer
+	err := renderer.Convert([]byte(src), &buf)
+	if err != nil {
+		return "", err
+	}
+
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,756 @@
+// Package quickadd reads a task typed as a single line of text, such as
+// "Pay rent tomorrow 9am !!high #finance every month", into its title, due
+// date, priority, labels and recurrence.
+//
+// The phrases it understands are:
+//
+//   - dates: today, tomorrow, day after tomorrow, weekdays ("fri", "next
+//     friday", "this friday"), next week, next month, weekend, in N days,
+//     weeks or months, ISO dates (2026-11-03) and month days ("nov 3",
+//     "3rd november 2027"), optionally after on, by or due
+//   - times: 9am, 9:30pm, 21:00, noon, midnight, "at 9", in N hours or
+//     minutes
+//   - priorities: !low, !medium, !high, !urgent or !1 to !5, with any number
+//     of bangs, and a bare !! or !!!
+//   - labels: #name
+//   - recurrence: daily, weekly, monthly, yearly, every day, every other
+//     week, every 3 months, every weekday, every weekend and weekday lists
+//     such as "every mon and thu"
+//
+// Everything else is the title. Text in double quotes is always kept in the
+// title, so "Meet at 9am" can be a title. When a kind of phrase appears more
+// than once the last one wins and the others are left in the title.
+package quickadd
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrEmptyTitle = errors.New("nothing is left for the title")
+
+// Kinds of the phrases.
+const (
+	KindDate       = "date"
+	KindTime       = "time"
+	KindPriority   = "priority"
+	KindLabel      = "label"
+	KindRecurrence = "recurrence"
+)
+
+// Priorities, on the 1 to 5 scale of tasks.
+const (
+	PriorityLow    = 2
+	PriorityMedium = 3
+	PriorityHigh   = 4
+	PriorityUrgent = 5
+)
+
+// Result is what was understood of a line. Due is in the location of the
+// reference time given to Parse and falls at midnight when AllDay is set.
+// Priority is 0 when none was given.
+type Result struct {
+	Title    string     `json:"title"`
+	Due      *time.Time `json:"dueDate"`
+	AllDay   bool       `json:"allDay"`
+	Priority int        `json:"priority"`
+	Labels   []string   `json:"labels"`
+	RRule    string     `json:"rrule"`
+	Matches  []Match    `json:"matches"`
+}
+
+// Match is a phrase that was understood, as it was typed.
+type Match struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+var (
+	clockRe = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm|a\.m\.|p\.m\.)?$`)
+	isoRe   = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	dayRe   = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+	yearRe  = regexp.MustCompile(`^\d{4}$`)
+)
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var priorities = map[string]int{
+	"low":    PriorityLow,
+	"medium": PriorityMedium,
+	"med":    PriorityMedium,
+	"normal": PriorityMedium,
+	"high":   PriorityHigh,
+	"urgent": PriorityUrgent,
+}
+
+// prepositions may lead a date or a time and are then part of it.
+var prepositions = []string{"on", "by", "at", "due"}
+
+// word is a whitespace separated word, or a quoted text when literal.
+type word struct {
+	raw     string
+	norm    string
+	literal bool
+}
+
+// phrase is a match over the words [start, end), applied to the state once
+// the last phrase of each kind is known.
+type phrase struct {
+	kind       string
+	start, end int
+	apply      func(s *state)
+}
+
+// state collects the values of the phrases.
+type state struct {
+	date         *time.Time
+	hour, minute int
+	hasTime      bool
+	priority     int
+	labels       []string
+	rrule        string
+	byDay        []time.Weekday
+}
+
+type parser struct {
+	words []word
+	now   time.Time
+	today time.Time
+}
+
+// Parse reads a line typed at now. Relative dates and times are resolved in
+// the location of now.
+func Parse(text string, now time.Time) (*Result, error) {
+	p := &parser{
+		words: split(text),
+		now:   now,
+		today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+	}
+
+	phrases := make([]*phrase, 0)
+	for i := 0; i < len(p.words); {
+		ph := p.match(i)
+		if ph == nil {
+			i++
+			continue
+		}
+		phrases = append(phrases, ph)
+		i = ph.end
+	}
+
+	// Only the last phrase of a kind counts, labels aside.
+	kept := make([]*phrase, 0, len(phrases))
+	for i, ph := range phrases {
+		later := slices.ContainsFunc(phrases[i+1:], func(other *phrase) bool {
+			return other.kind == ph.kind
+		})
+		if ph.kind == KindLabel || !later {
+			kept = append(kept, ph)
+		}
+	}
+
+	s := &state{}
+	result := &Result{
+		Labels:  make([]string, 0),
+		Matches: make([]Match, 0, len(kept)),
+	}
+	used := make([]bool, len(p.words))
+	for _, ph := range kept {
+		ph.apply(s)
+		for i := ph.start; i < ph.end; i++ {
+			used[i] = true
+		}
+		result.Matches = append(result.Matches, Match{
+			Kind: ph.kind,
+			Text: p.text(ph.start, ph.end),
+		})
+	}
+
+	title := make([]string, 0, len(p.words))
+	for i, w := range p.words {
+		if !used[i] {
+			title = append(title, w.raw)
+		}
+	}
+	result.Title = strings.TrimSpace(strings.Join(title, " "))
+	if result.Title == "" {
+		return nil, ErrEmptyTitle
+	}
+
+	result.Priority = s.priority
+	result.Labels = append(result.Labels, s.labels...)
+	result.RRule = s.rrule
+	result.Due, result.AllDay = p.due(s)
+
+	return result, nil
+}
+
+// due combines the date, time and recurrence of s. A time without a date
+// falls on the next day it is still ahead, and a recurrence without a date
+// starts on its first day from today.
+func (p *parser) due(s *state) (*time.Time, bool) {
+	var day time.Time
+	switch {
+	case s.date != nil:
+		day = *s.date
+	case s.rrule != "" || s.hasTime:
+		day = p.nextDay(p.today, s.byDay)
+	default:
+		return nil, false
+	}
+
+	if !s.hasTime {
+		return &day, true
+	}
+
+	due := time.Date(day.Year(), day.Month(), day.Day(), s.hour, s.minute, 0, 0, day.Location())
+	if s.date == nil && !due.After(p.now) {
+		day = p.nextDay(day.AddDate(0, 0, 1), s.byDay)
+		due = time.Date(day.Year(), day.Month(), day.Day(), s.hour, s.minute, 0, 0, day.Location())
+	}
+
+	return &due, false
+}
+
+// nextDay returns the first day from day falling on one of days, day itself
+// when days is empty.
+func (p *parser) nextDay(day time.Time, days []time.Weekday) time.Time {
+	if len(days) == 0 {
+		return day
+	}
+
+	for !slices.Contains(days, day.Weekday()) {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return day
+}
+
+// match returns the phrase starting at word i, if any.
+func (p *parser) match(i int) *phrase {
+	if p.words[i].literal {
+		return nil
+	}
+
+	if ph := p.label(i); ph != nil {
+		return ph
+	}
+	if ph := p.priority(i); ph != nil {
+		return ph
+	}
+	if ph := p.recurrence(i); ph != nil {
+		return ph
+	}
+
+	// Up to two prepositions, as in "due on friday", join the date or time
+	// they lead.
+	for j := i; j < len(p.words) && j <= i+2; j++ {
+		if ph := p.date(j); ph != nil {
+			ph.start = i
+			return ph
+		}
+		if ph := p.clock(j, j > i && p.word(j-1) == "at"); ph != nil {
+			ph.start = i
+			return ph
+		}
+		if !slices.Contains(prepositions, p.word(j)) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) label(i int) *phrase {
+	raw := strings.TrimRight(p.words[i].raw, ",;.")
+	name, ok := strings.CutPrefix(raw, "#")
+	if !ok || name == "" || !isLabelName(name) {
+		return nil
+	}
+
+	return &phrase{
+		kind:  KindLabel,
+		start: i,
+		end:   i + 1,
+		apply: func(s *state) {
+			if !slices.Contains(s.labels, name) {
+				s.labels = append(s.labels, name)
+			}
+		},
+	}
+}
+
+func isLabelName(name string) bool {
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-/", r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *parser) priority(i int) *phrase {
+	w := p.word(i)
+	name := strings.TrimLeft(w, "!")
+	bangs := len(w) - len(name)
+	if bangs == 0 {
+		return nil
+	}
+
+	priority, ok := priorities[name]
+	switch {
+	case ok:
+	case name == "" && bangs == 2:
+		priority = PriorityHigh
+	case name == "" && bangs == 3:
+		priority = PriorityUrgent
+	case len(name) == 1 && name[0] >= '1' && name[0] <= '5':
+		priority = int(name[0] - '0')
+	default:
+		return nil
+	}
+
+	return &phrase{
+		kind:  KindPriority,
+		start: i,
+		end:   i + 1,
+		apply: func(s *state) {
+			s.priority = priority
+		},
+	}
+}
+
+func (p *parser) recurrence(i int) *phrase {
+	rule := func(end int, rrule string, byDay []time.Weekday) *phrase {
+		return &phrase{
+			kind:  KindRecurrence,
+			start: i,
+			end:   end,
+			apply: func(s *state) {
+				s.rrule = rrule
+				s.byDay = byDay
+			},
+		}
+	}
+
+	switch p.word(i) {
+	case "daily":
+		return rule(i+1, "FREQ=DAILY", nil)
+	case "weekly":
+		return rule(i+1, "FREQ=WEEKLY", nil)
+	case "monthly":
+		return rule(i+1, "FREQ=MONTHLY", nil)
+	case "yearly", "annually":
+		return rule(i+1, "FREQ=MONTHLY;INTERVAL=12", nil)
+	case "every":
+	default:
+		return nil
+	}
+
+	j := i + 1
+	switch p.word(j) {
+	case "weekday", "weekdays":
+		days := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return rule(j+1, weeklyRule(days), days)
+	case "weekend", "weekends":
+		days := []time.Weekday{time.Saturday, time.Sunday}
+		return rule(j+1, weeklyRule(days), days)
+	}
+
+	if days, end := p.weekdayList(j); len(days) > 0 {
+		return rule(end, weeklyRule(days), days)
+	}
+
+	interval, plural := 1, false
+	switch w := p.word(j); {
+	case w == "other":
+		interval, plural = 2, false
+		j++
+	case isCount(w):
+		interval, _ = strconv.Atoi(w)
+		plural = interval != 1
+		j++
+	}
+
+	unit := p.word(j)
+	if plural {
+		unit = strings.TrimSuffix(unit, "s")
+	}
+	rrule := ""
+	switch unit {
+	case "day":
+		rrule = "FREQ=DAILY"
+	case "week":
+		rrule = "FREQ=WEEKLY"
+	case "month":
+		rrule = "FREQ=MONTHLY"
+	case "year":
+		rrule, interval = "FREQ=MONTHLY", interval*12
+	default:
+		return nil
+	}
+	if interval > 1 {
+		rrule += ";INTERVAL=" + strconv.Itoa(interval)
+	}
+
+	return rule(j+1, rrule, nil)
+}
+
+// weekdayList reads weekdays separated by commas, "and" or "&" from word i,
+// returning them and the end of the list.
+func (p *parser) weekdayList(i int) ([]time.Weekday, int) {
+	days := make([]time.Weekday, 0)
+	end := i
+	for j := i; j < len(p.words) && !p.words[j].literal; j++ {
+		w := p.word(j)
+		if w == "and" || w == "&" {
+			continue
+		}
+
+		found := false
+		for _, name := range strings.Split(w, ",") {
+			if name == "" {
+				continue
+			}
+			day, ok := weekdays[name]
+			if !ok {
+				return days, end
+			}
+			found = true
+			if !slices.Contains(days, day) {
+				days = append(days, day)
+			}
+		}
+		if found {
+			end = j + 1
+		}
+	}
+
+	return days, end
+}
+
+func weeklyRule(days []time.Weekday) string {
+	codes := make([]string, len(days))
+	for i, day := range days {
+		codes[i] = weekdayCodes[day]
+	}
+
+	return "FREQ=WEEKLY;BYDAY=" + strings.Join(codes, ",")
+}
+
+func (p *parser) date(i int) *phrase {
+	on := func(end int, day time.Time) *phrase {
+		return &phrase{
+			kind:  KindDate,
+			start: i,
+			end:   end,
+			apply: func(s *state) {
+				s.date = &day
+			},
+		}
+	}
+
+	w := p.word(i)
+	switch w {
+	case "today":
+		return on(i+1, p.today)
+	case "tomorrow", "tmr", "tmrw":
+		return on(i+1, p.today.AddDate(0, 0, 1))
+	case "weekend":
+		return on(i+1, p.weekend())
+	case "day":
+		if p.word(i+1) == "after" && p.word(i+2) == "tomorrow" {
+			return on(i+3, p.today.AddDate(0, 0, 2))
+		}
+	case "this":
+		if day, ok := weekdays[p.word(i+1)]; ok {
+			return on(i+2, p.today.AddDate(0, 0, p.daysUntil(day, false)))
+		}
+		if p.word(i+1) == "weekend" {
+			return on(i+2, p.weekend())
+		}
+	case "next":
+		if day, ok := weekdays[p.word(i+1)]; ok {
+			return on(i+2, p.today.AddDate(0, 0, p.daysUntil(day, true)))
+		}
+		switch p.word(i + 1) {
+		case "week":
+			return on(i+2, p.today.AddDate(0, 0, p.daysUntil(time.Monday, true)))
+		case "month":
+			return on(i+2, time.Date(p.today.Year(), p.today.Month()+1, 1, 0, 0, 0, 0, p.today.Location()))
+		}
+	case "in":
+		return p.in(i)
+	}
+
+	if day, ok := weekdays[w]; ok {
+		return on(i+1, p.today.AddDate(0, 0, p.daysUntil(day, true)))
+	}
+
+	if isoRe.MatchString(w) {
+		day, err := time.ParseInLocation(time.DateOnly, w, p.today.Location())
+		if err != nil {
+			return nil
+		}
+		return on(i+1, day)
+	}
+
+	// "nov 3", "november 3rd 2027", "3 nov" and "3rd of november".
+	month, monthFirst := months[w]
+	dayWord, end := p.word(i+1), i+2
+	if !monthFirst {
+		dayWord = w
+		end = i + 1
+		if p.word(end) == "of" {
+			end++
+		}
+		if month, monthFirst = months[p.word(end)]; !monthFirst {
+			return nil
+		}
+		end++
+	}
+
+	m := dayRe.FindStringSubmatch(dayWord)
+	if m == nil {
+		return nil
+	}
+	dayOfMonth, _ := strconv.Atoi(m[1])
+
+	year, explicitYear := p.today.Year(), false
+	if yearRe.MatchString(p.word(end)) {
+		year, _ = strconv.Atoi(p.word(end))
+		explicitYear = true
+		end++
+	}
+
+	day := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, p.today.Location())
+	if day.Day() != dayOfMonth {
+		return nil
+	}
+	if !explicitYear && day.Before(p.today) {
+		day = day.AddDate(1, 0, 0)
+	}
+
+	return on(end, day)
+}
+
+// in reads "in N days" and the like. Hours and minutes set the time as well.
+func (p *parser) in(i int) *phrase {
+	n, ok := 0, true
+	switch w := p.word(i + 1); {
+	case w == "a" || w == "an":
+		n = 1
+	case isCount(w):
+		n, _ = strconv.Atoi(w)
+	default:
+		ok = false
+	}
+	if !ok {
+		return nil
+	}
+
+	var at time.Time
+	timed := false
+	switch strings.TrimSuffix(p.word(i+2), "s") {
+	case "day":
+		at = p.today.AddDate(0, 0, n)
+	case "week":
+		at = p.today.AddDate(0, 0, 7*n)
+	case "month":
+		at = p.today.AddDate(0, n, 0)
+	case "hour", "hr":
+		at, timed = p.now.Add(time.Duration(n)*time.Hour), true
+	case "minute", "min":
+		at, timed = p.now.Add(time.Duration(n)*time.Minute), true
+	default:
+		return nil
+	}
+
+	return &phrase{
+		kind:  KindDate,
+		start: i,
+		end:   i + 3,
+		apply: func(s *state) {
+			day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+			s.date = &day
+			if timed {
+				s.hour, s.minute, s.hasTime = at.Hour(), at.Minute(), true
+			}
+		},
+	}
+}
+
+// clock reads a time of day. A bare hour such as "9" only counts after "at".
+func (p *parser) clock(i int, afterAt bool) *phrase {
+	at := func(end, hour, minute int) *phrase {
+		return &phrase{
+			kind:  KindTime,
+			start: i,
+			end:   end,
+			apply: func(s *state) {
+				s.hour, s.minute, s.hasTime = hour, minute, true
+			},
+		}
+	}
+
+	w := p.word(i)
+	switch w {
+	case "noon", "midday":
+		return at(i+1, 12, 0)
+	case "midnight":
+		return at(i+1, 0, 0)
+	}
+
+	m := clockRe.FindStringSubmatch(w)
+	if m == nil {
+		return nil
+	}
+
+	end := i + 1
+	meridiem := m[3]
+	if meridiem == "" {
+		switch next := p.word(i + 1); next {
+		case "am", "pm", "a.m.", "p.m.":
+			meridiem = next
+			end++
+		}
+	}
+	if meridiem == "" && m[2] == "" && !afterAt {
+		return nil
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	switch {
+	case minute > 59:
+		return nil
+	case meridiem == "":
+		if hour > 23 {
+			return nil
+		}
+	case hour < 1 || hour > 12:
+		return nil
+	case meridiem[0] == 'a':
+		hour %= 12
+	default:
+		hour = hour%12 + 12
+	}
+
+	return at(end, hour, minute)
+}
+
+// daysUntil returns how many days ahead day next falls, a week ahead rather
+// than today when strict.
+func (p *parser) daysUntil(day time.Weekday, strict bool) int {
+	n := (int(day) - int(p.today.Weekday()) + 7) % 7
+	if n == 0 && strict {
+		n = 7
+	}
+
+	return n
+}
+
+// weekend returns the coming Saturday, or today during a weekend.
+func (p *parser) weekend() time.Time {
+	if p.today.Weekday() == time.Sunday {
+		return p.today
+	}
+
+	return p.today.AddDate(0, 0, p.daysUntil(time.Saturday, false))
+}
+
+// word returns the normalised word i, empty past the end or for quoted text.
+func (p *parser) word(i int) string {
+	if i >= len(p.words) || p.words[i].literal {
+		return ""
+	}
+
+	return p.words[i].norm
+}
+
+func (p *parser) text(start, end int) string {
+	raws := make([]string, 0, end-start)
+	for _, w := range p.words[start:end] {
+		raws = append(raws, w.raw)
+	}
+
+	return strings.Join(raws, " ")
+}
+
+// isCount reports whether w is a positive count of at most three digits.
+func isCount(w string) bool {
+	n, err := strconv.Atoi(w)
+	return err == nil && n > 0 && len(w) <= 3
+}
+
+// split cuts text into words, keeping the text between double quotes as a
+// single literal word. An unclosed quote runs to the end of the text.
+func split(text string) []word {
+	words := make([]word, 0)
+	for text != "" {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		if text == "" {
+			break
+		}
+
+		if quoted, ok := strings.CutPrefix(text, `"`); ok {
+			literal, rest, _ := strings.Cut(quoted, `"`)
+			if literal = strings.TrimSpace(literal); literal != "" {
+				words = append(words, word{raw: literal, literal: true})
+			}
+			text = rest
+			continue
+		}
+
+		end := strings.IndexFunc(text, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '"'
+		})
+		if end < 0 {
+			end = len(text)
+		}
+
+		raw := text[:end]
+		words = append(words, word{
+			raw:  raw,
+			norm: strings.TrimRight(strings.ToLower(raw), ",;"),
+		})
+		text = text[end:]
+	}
+
+	return words
+}
@@ -0,0 +1,233 @@
+package quickadd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// now is Sunday 18 October 2026, 14:30 at UTC+7.
+var now = time.Date(2026, time.October, 18, 14, 30, 0, 0, time.FixedZone("ICT", 7*60*60))
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text     string
+		title    string
+		due      string // in the location of now, empty for none
+		allDay   bool
+		priority int
+		labels   []string
+		rrule    string
+	}{
+		{
+			text:     "Pay rent tomorrow 9am !!high #finance every month",
+			title:    "Pay rent",
+			due:      "2026-10-19 09:00",
+			priority: PriorityHigh,
+			labels:   []string{"finance"},
+			rrule:    "FREQ=MONTHLY",
+		},
+		{text: "Buy milk", title: "Buy milk"},
+		{text: "Buy milk today", title: "Buy milk", due: "2026-10-18 00:00", allDay: true},
+		{text: "Call mom tmrw", title: "Call mom", due: "2026-10-19 00:00", allDay: true},
+		{text: "Dentist day after tomorrow", title: "Dentist", due: "2026-10-20 00:00", allDay: true},
+		{text: "Standup monday at 10", title: "Standup", due: "2026-10-19 10:00"},
+		{text: "Standup on Monday at 10:15am", title: "Standup", due: "2026-10-19 10:15"},
+		{text: "Church sunday", title: "Church", due: "2026-10-25 00:00", allDay: true},
+		{text: "Church this sunday", title: "Church", due: "2026-10-18 00:00", allDay: true},
+		{text: "Review next friday", title: "Review", due: "2026-10-23 00:00", allDay: true},
+		{text: "Plan sprint next week", title: "Plan sprint", due: "2026-10-19 00:00", allDay: true},
+		{text: "Invoice next month", title: "Invoice", due: "2026-11-01 00:00", allDay: true},
+		{text: "Hike this weekend", title: "Hike", due: "2026-10-18 00:00", allDay: true},
+		{text: "Renew passport in 3 weeks", title: "Renew passport", due: "2026-11-08 00:00", allDay: true},
+		{text: "Check oven in 45 minutes", title: "Check oven", due: "2026-10-18 15:15"},
+		{text: "Call back in an hour", title: "Call back", due: "2026-10-18 15:30"},
+		{text: "Taxes due by 2027-04-15", title: "Taxes", due: "2027-04-15 00:00", allDay: true},
+		{text: "Party nov 3rd 7pm", title: "Party", due: "2026-11-03 19:00"},
+		{text: "Gift 1st of march", title: "Gift", due: "2027-03-01 00:00", allDay: true},
+		{text: "Gift March 1 2026", title: "Gift", due: "2026-03-01 00:00", allDay: true},
+		{text: "Lunch noon", title: "Lunch", due: "2026-10-19 12:00"},
+		{text: "Call at 3pm", title: "Call", due: "2026-10-18 15:00"},
+		{text: "Call at 2 pm", title: "Call", due: "2026-10-19 14:00"},
+		{text: "Backup at 21:00", title: "Backup", due: "2026-10-18 21:00"},
+		{text: "Sleep midnight", title: "Sleep", due: "2026-10-19 00:00"},
+		{text: "Fix bug !urgent", title: "Fix bug", priority: PriorityUrgent},
+		{text: "Fix bug !!!", title: "Fix bug", priority: PriorityUrgent},
+		{text: "Fix bug !2", title: "Fix bug", priority: 2},
+		{text: "Fix bug !low !high", title: "Fix bug !low", priority: PriorityHigh},
+		{text: "Read #books #Fun, #books", title: "Read", labels: []string{"books", "Fun"}},
+		{text: "Water plants every day", title: "Water plants", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=DAILY"},
+		{text: "Water plants daily 8am", title: "Water plants", due: "2026-10-19 08:00", rrule: "FREQ=DAILY"},
+		{text: "Gym every mon, wed and fri 7am", title: "Gym", due: "2026-10-19 07:00", rrule: "FREQ=WEEKLY;BYDAY=MO,WE,FR"},
+		{text: "Report every weekday", title: "Report", due: "2026-10-19 00:00", allDay: true, rrule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{text: "Clean every other week", title: "Clean", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=WEEKLY;INTERVAL=2"},
+		{text: "Rotate keys every 3 months", title: "Rotate keys", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=MONTHLY;INTERVAL=3"},
+		{text: "Birthday nov 3 yearly", title: "Birthday", due: "2026-11-03 00:00", allDay: true, rrule: "FREQ=MONTHLY;INTERVAL=12"},
+		{text: "Ask every question", title: "Ask every question"},
+		{text: `"Meet at 9am" tomorrow`, title: "Meet at 9am", due: "2026-10-19 00:00", allDay: true},
+		{text: "Buy 2 apples", title: "Buy 2 apples"},
+		{text: "Email report due", title: "Email report due"},
+		{text: "Review monday report friday", title: "Review monday report", due: "2026-10-23 00:00", allDay: true},
+		{text: "Pay on feb 30", title: "Pay on feb 30"},
+
+		// Relative weekdays, from a Sunday.
+		{text: "Gym fri", title: "Gym", due: "2026-10-23 00:00", allDay: true},
+		{text: "Gym thurs", title: "Gym", due: "2026-10-22 00:00", allDay: true},
+		{text: "Gym this saturday", title: "Gym", due: "2026-10-24 00:00", allDay: true},
+		{text: "Gym next sunday", title: "Gym", due: "2026-10-25 00:00", allDay: true},
+		{text: "Gym by tues 6pm", title: "Gym", due: "2026-10-20 18:00"},
+		{text: "Call tomorrow friday", title: "Call tomorrow", due: "2026-10-23 00:00", allDay: true},
+
+		// Recurrences.
+		{text: "Stretch every 2 days", title: "Stretch", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=DAILY;INTERVAL=2"},
+		{text: "Pay every 1 month", title: "Pay", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=MONTHLY"},
+		{text: "Laundry weekly", title: "Laundry", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=WEEKLY"},
+		{text: "Renew every year", title: "Renew", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=MONTHLY;INTERVAL=12"},
+		{text: "Renew every 2 years", title: "Renew", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=MONTHLY;INTERVAL=24"},
+		{text: "Review annually", title: "Review", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=MONTHLY;INTERVAL=12"},
+		{text: "Rest every weekend", title: "Rest", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=WEEKLY;BYDAY=SA,SU"},
+		{text: "Call every tue & thu", title: "Call", due: "2026-10-20 00:00", allDay: true, rrule: "FREQ=WEEKLY;BYDAY=TU,TH"},
+		{text: "Gym every sun 2pm", title: "Gym", due: "2026-10-25 14:00", rrule: "FREQ=WEEKLY;BYDAY=SU"},
+		{text: "Sync weekly monthly", title: "Sync weekly", due: "2026-10-18 00:00", allDay: true, rrule: "FREQ=MONTHLY"},
+		{text: "Wait every 0 days", title: "Wait every 0 days"},
+
+		// Priorities.
+		{text: "Fix bug !med", title: "Fix bug", priority: PriorityMedium},
+		{text: "Fix bug !normal", title: "Fix bug", priority: PriorityMedium},
+		{text: "Fix bug !!!low", title: "Fix bug", priority: PriorityLow},
+		{text: "Fix bug !HIGH", title: "Fix bug", priority: PriorityHigh},
+		{text: "Fix bug !!", title: "Fix bug", priority: PriorityHigh},
+		{text: "Fix bug !5", title: "Fix bug", priority: 5},
+		{text: "Fix bug !6", title: "Fix bug !6"},
+		{text: "Fix bug !", title: "Fix bug !"},
+		{text: "Fix bug !!!!", title: "Fix bug !!!!"},
+
+		// Labels.
+		{text: "Plan trip #travel #2026 #work/q4", title: "Plan trip", labels: []string{"travel", "2026", "work/q4"}},
+		{text: "Plan trip #Travel #travel", title: "Plan trip", labels: []string{"Travel", "travel"}},
+		{text: "Tag #a.b and #", title: "Tag #a.b and #"},
+
+		// Ambiguous or invalid phrases stay in the title.
+		{text: "Meet at 25", title: "Meet at 25"},
+		{text: "Meet 13pm", title: "Meet 13pm"},
+		{text: "Meet 9:75", title: "Meet 9:75"},
+		{text: "Buy 9 eggs", title: "Buy 9 eggs"},
+		{text: "Pay on feb 29", title: "Pay on feb 29"},
+		{text: "Trip 2026-02-30", title: "Trip 2026-02-30"},
+		{text: "Move in 1000 days", title: "Move in 1000 days"},
+		{text: "Next steps", title: "Next steps"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			result, err := Parse(tt.text, now)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if result.Title != tt.title {
+				t.Errorf("Title = %q, want %q", result.Title, tt.title)
+			}
+
+			due := ""
+			if result.Due != nil {
+				if result.Due.Location() != now.Location() {
+					t.Errorf("Due location = %v, want %v", result.Due.Location(), now.Location())
+				}
+				due = result.Due.Format("2006-01-02 15:04")
+			}
+			if due != tt.due {
+				t.Errorf("Due = %q, want %q", due, tt.due)
+			}
+
+			if result.AllDay != tt.allDay {
+				t.Errorf("AllDay = %v, want %v", result.AllDay, tt.allDay)
+			}
+			if result.Priority != tt.priority {
+				t.Errorf("Priority = %d, want %d", result.Priority, tt.priority)
+			}
+			if !slices.Equal(result.Labels, tt.labels) {
+				t.Errorf("Labels = %q, want %q", result.Labels, tt.labels)
+			}
+			if result.RRule != tt.rrule {
+				t.Errorf("RRule = %q, want %q", result.RRule, tt.rrule)
+			}
+		})
+	}
+}
+
+// TestParseTimeZones checks that dates are resolved in the location of the
+// reference time, across a change to daylight saving time.
+func TestParseTimeZones(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	// Saturday 7 March 2026, the day before clocks go forward in New York.
+	beforeDST := time.Date(2026, time.March, 7, 10, 0, 0, 0, newYork)
+	// Sunday 18 October 2026 at 22:00 UTC is already Monday at UTC+7.
+	lateUTC := time.Date(2026, time.October, 18, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		now  time.Time
+		text string
+		due  string // with its UTC offset
+	}{
+		{now: beforeDST, text: "Run tomorrow 9am", due: "2026-03-08 09:00 -0400"},
+		{now: beforeDST, text: "Run today 9pm", due: "2026-03-07 21:00 -0500"},
+		{now: beforeDST, text: "Run daily 9am", due: "2026-03-08 09:00 -0400"},
+		{now: beforeDST, text: "Run in 2 days", due: "2026-03-09 00:00 -0400"},
+		{now: beforeDST, text: "Call in 24 hours", due: "2026-03-08 11:00 -0400"},
+		{now: lateUTC, text: "Buy milk today", due: "2026-10-18 00:00 +0000"},
+		{now: lateUTC.In(now.Location()), text: "Buy milk today", due: "2026-10-19 00:00 +0700"},
+		{now: lateUTC.In(now.Location()), text: "Standup monday 9am", due: "2026-10-26 09:00 +0700"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.now.Location().String()+"/"+tt.text, func(t *testing.T) {
+			result, err := Parse(tt.text, tt.now)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if result.Due == nil {
+				t.Fatalf("Due = nil, want %q", tt.due)
+			}
+
+			if result.Due.Location() != tt.now.Location() {
+				t.Errorf("Due location = %v, want %v", result.Due.Location(), tt.now.Location())
+			}
+			if due := result.Due.Format("2006-01-02 15:04 -0700"); due != tt.due {
+				t.Errorf("Due = %q, want %q", due, tt.due)
+			}
+		})
+	}
+}
+
+func TestParseMatches(t *testing.T) {
+	result, err := Parse("Pay rent due on Nov 3 at 9am !!high #finance every month", now)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []Match{
+		{Kind: KindDate, Text: "due on Nov 3"},
+		{Kind: KindTime, Text: "at 9am"},
+		{Kind: KindPriority, Text: "!!high"},
+		{Kind: KindLabel, Text: "#finance"},
+		{Kind: KindRecurrence, Text: "every month"},
+	}
+	if !slices.Equal(result.Matches, want) {
+		t.Errorf("Matches = %+v, want %+v", result.Matches, want)
+	}
+}
+
+func TestParseEmptyTitle(t *testing.T) {
+	for _, text := range []string{"", "   ", "tomorrow 9am #home", `""`} {
+		_, err := Parse(text, now)
+		if !errors.Is(err, ErrEmptyTitle) {
+			t.Errorf("Parse(%q) error = %v, want %v", text, err, ErrEmptyTitle)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package rrulex
+
+import (
+	"slices"
+	"time"
+)
+
+// maxEmptyPeriods bounds the search for rules that rarely or never match,
+// such as BYMONTHDAY=31 with INTERVAL=12 starting in a short month.
+const maxEmptyPeriods = 1000
+
+// Iterator walks the occurrences of a rule in chronological order. As RFC 5545
+// requires, dtstart is always the first occurrence and counts towards COUNT.
+// Every occurrence keeps the clock time and location of dtstart.
+type Iterator struct {
+	rule    *Rule
+	dtstart time.Time
+	period  int
+	pending []time.Time
+	emitted int
+	done    bool
+}
+
+func (r *Rule) Iterator(dtstart time.Time) *Iterator {
+	return &Iterator{
+		rule:    r,
+		dtstart: dtstart,
+	}
+}
+
+// Next returns the next occurrence, or false once the rule is exhausted.
+func (it *Iterator) Next() (time.Time, bool) {
+	if it.done || (it.rule.Count > 0 && it.emitted >= it.rule.Count) {
+		return time.Time{}, false
+	}
+
+	next := it.dtstart
+	if it.emitted > 0 {
+		if !it.fill() {
+			return time.Time{}, false
+		}
+
+		next, it.pending = it.pending[0], it.pending[1:]
+	}
+
+	if !it.rule.Until.IsZero() && next.After(it.rule.Until) {
+		it.done = true
+		return time.Time{}, false
+	}
+
+	it.emitted++
+	return next, true
+}
+
+// Seek skips the occurrences before t, so that Next returns the first one at
+// or after t. Skipped occurrences count towards COUNT and Emitted. Rules with
+// a fixed number of occurrences per period, or per week of periods, jump over
+// whole periods, so seeking far from dtstart does not walk every occurrence.
+func (it *Iterator) Seek(t time.Time) {
+	for !it.done && (it.rule.Count == 0 || it.emitted < it.rule.Count) {
+		if it.emitted == 0 {
+			if !it.dtstart.Before(t) {
+				return
+			}
+			it.emitted++
+			continue
+		}
+
+		if len(it.pending) == 0 {
+			it.skipPeriods(t)
+			if !it.fill() {
+				return
+			}
+		}
+
+		if !it.pending[0].Before(t) {
+			return
+		}
+		it.pending = it.pending[1:]
+		it.emitted++
+	}
+}
+
+// Emitted returns how many occurrences Next returned or Seek skipped, that
+// is the index of the occurrence Next returns.
+func (it *Iterator) Emitted() int {
+	return it.emitted
+}
+
+// fill expands periods until there are pending occurrences, reporting false
+// once it gives up on finding any.
+func (it *Iterator) fill() bool {
+	empty := 0
+	for len(it.pending) == 0 {
+		if empty > maxEmptyPeriods {
+			it.done = true
+			return false
+		}
+
+		for _, t := range it.expand(it.period) {
+			if t.After(it.dtstart) {
+				it.pending = append(it.pending, t)
+			}
+		}
+		it.period++
+		empty++
+	}
+
+	return true
+}
+
+// skipPeriods jumps over the whole blocks of periods ending before the day of
+// t, counting their occurrences without listing them. The first period is
+// always expanded, as it drops the candidates up to dtstart.
+func (it *Iterator) skipPeriods(t time.Time) {
+	if it.period == 0 {
+		return
+	}
+
+	r, start := it.rule, it.dtstart
+
+	// The periods cover width days every length days from the day anchor,
+	// and a block of them always holds the same number of occurrences.
+	var anchor time.Time
+	var length, width, block int
+	switch {
+	case r.Freq == Daily && len(r.ByMonthDay) == 0:
+		anchor = start
+		length, width, block = r.Interval, 1, 1
+		if len(r.ByDay) > 0 {
+			block = 7
+		}
+	case r.Freq == Weekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		anchor = it.at(start.Year(), start.Month(), start.Day()-offset)
+		length, width, block = 7*r.Interval, 7, 1
+	default:
+		return
+	}
+
+	day := daysBetween(anchor, t.In(start.Location()))
+	if day < width {
+		return
+	}
+	// Periods before end lie wholly on days before the day of t.
+	end := (day-width)/length + 1
+	blocks := (end - it.period) / block
+	if blocks <= 0 {
+		return
+	}
+
+	perBlock := 0
+	for p := it.period; p < it.period+block; p++ {
+		perBlock += len(it.expand(p))
+	}
+
+	it.period += blocks * block
+	it.emitted += blocks * perBlock
+}
+
+// daysBetween returns the number of calendar days from the day of a to the
+// day of b.
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// Take returns the first n occurrences starting at dtstart.
+func (r *Rule) Take(dtstart time.Time, n int) []time.Time {
+	it := r.Iterator(dtstart)
+	occurrences := make([]time.Time, 0, n)
+	for len(occurrences) < n {
+		t, ok := it.Next()
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, t)
+	}
+
+	return occurrences
+}
+
+// Nth returns the occurrence at index n, dtstart being index 0.
+func (r *Rule) Nth(dtstart time.Time, n int) (time.Time, bool) {
+	it := r.Iterator(dtstart)
+	for i := 0; ; i++ {
+		t, ok := it.Next()
+		if !ok || i == n {
+			return t, ok
+		}
+	}
+}
+
+// expand lists the candidate occurrences of the given period in order.
+func (it *Iterator) expand(period int) []time.Time {
+	r, start := it.rule, it.dtstart
+	step := period * r.Interval
+
+	switch r.Freq {
+	case Daily:
+		day := it.at(start.Year(), start.Month(), start.Day()+step)
+		if !r.matchesWeekday(day) || !r.matchesMonthDay(day) {
+			return nil
+		}
+		return []time.Time{day}
+
+	case Weekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := it.at(start.Year(), start.Month(), start.Day()-offset+7*step)
+
+		days := []time.Weekday{start.Weekday()}
+		if len(r.ByDay) > 0 {
+			days = days[:0]
+			for _, day := range r.ByDay {
+				days = append(days, day.Weekday)
+			}
+		}
+
+		candidates := make([]time.Time, 0, len(days))
+		for _, day := range days {
+			shift := (int(day) - int(r.WeekStart) + 7) % 7
+			candidates = append(candidates, it.at(weekStart.Year(), weekStart.Month(), weekStart.Day()+shift))
+		}
+		return sortUnique(candidates)
+
+	case Monthly:
+		first := it.at(start.Year(), start.Month()+time.Month(step), 1)
+		days := r.monthDays(first, start.Day())
+
+		candidates := make([]time.Time, 0, len(days))
+		for _, day := range days {
+			candidates = append(candidates, it.at(first.Year(), first.Month(), day))
+		}
+		return candidates
+	}
+
+	return nil
+}
+
+// monthDays resolves BYMONTHDAY and BYDAY to the sorted days of the month
+// starting at first. With both, only the days matching both are kept. With
+// neither, the month repeats defaultDay, and is skipped when it is too short.
+func (r *Rule) monthDays(first time.Time, defaultDay int) []int {
+	daysInMonth := time.Date(first.Year(), first.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	var byMonthDay, byDay []int
+	for _, day := range r.ByMonthDay {
+		if day < 0 {
+			day += daysInMonth + 1
+		}
+		if day >= 1 && day <= daysInMonth {
+			byMonthDay = append(byMonthDay, day)
+		}
+	}
+
+	for _, wd := range r.ByDay {
+		firstMatch := 1 + (int(wd.Weekday)-int(first.Weekday())+7)%7
+		switch {
+		case wd.N == 0:
+			for day := firstMatch; day <= daysInMonth; day += 7 {
+				byDay = append(byDay, day)
+			}
+		case wd.N > 0:
+			byDay = append(byDay, firstMatch+7*(wd.N-1))
+		default:
+			lastMatch := firstMatch + 7*((daysInMonth-firstMatch)/7)
+			byDay = append(byDay, lastMatch+7*(wd.N+1))
+		}
+	}
+	byDay = slices.DeleteFunc(byDay, func(day int) bool {
+		return day < 1 || day > daysInMonth
+	})
+
+	var days []int
+	switch {
+	case len(r.ByMonthDay) > 0 && len(r.ByDay) > 0:
+		for _, day := range byMonthDay {
+			if slices.Contains(byDay, day) {
+				days = append(days, day)
+			}
+		}
+	case len(r.ByMonthDay) > 0:
+		days = byMonthDay
+	case len(r.ByDay) > 0:
+		days = byDay
+	case defaultDay <= daysInMonth:
+		days = []int{defaultDay}
+	}
+
+	slices.Sort(days)
+	return slices.Compact(days)
+}
+
+func (r *Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(r.ByDay, func(day WeekdayNum) bool {
+		return day.Weekday == t.Weekday()
+	})
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, day := range r.ByMonthDay {
+		if day < 0 {
+			day += daysInMonth + 1
+		}
+		if day == t.Day() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// at builds a time on the given day, normalizing overflowing days and months,
+// at the clock time of dtstart.
+func (it *Iterator) at(year int, month time.Month, day int) time.Time {
+	s := it.dtstart
+	return time.Date(year, month, day, s.Hour(), s.Minute(), s.Second(), s.Nanosecond(), s.Location())
+}
+
+func sortUnique(times []time.Time) []time.Time {
+	slices.SortFunc(times, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+
+	return slices.CompactFunc(times, func(a, b time.Time) bool {
+		return a.Equal(b)
+	})
+}
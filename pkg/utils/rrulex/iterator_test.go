@@ -0,0 +1,256 @@
+package rrulex
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// ict is UTC+7, so that occurrences at 09:00 fall at 02:00 UTC.
+var ict = time.FixedZone("ICT", 7*60*60)
+
+func TestTake(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart string // at 09:00 in ict
+		n       int
+		want    []string
+	}{
+		{
+			name:    "daily",
+			rule:    "FREQ=DAILY",
+			dtstart: "2026-01-05",
+			n:       3,
+			want:    []string{"2026-01-05", "2026-01-06", "2026-01-07"},
+		},
+		{
+			name:    "daily with interval across months",
+			rule:    "FREQ=DAILY;INTERVAL=2",
+			dtstart: "2026-01-29",
+			n:       3,
+			want:    []string{"2026-01-29", "2026-01-31", "2026-02-02"},
+		},
+		{
+			name:    "daily on weekends keeps dtstart first",
+			rule:    "FREQ=DAILY;BYDAY=SA,SU",
+			dtstart: "2026-01-05",
+			n:       3,
+			want:    []string{"2026-01-05", "2026-01-10", "2026-01-11"},
+		},
+		{
+			name:    "weekly",
+			rule:    "FREQ=WEEKLY",
+			dtstart: "2026-01-05",
+			n:       3,
+			want:    []string{"2026-01-05", "2026-01-12", "2026-01-19"},
+		},
+		{
+			name:    "weekly by day",
+			rule:    "FREQ=WEEKLY;BYDAY=TH,MO",
+			dtstart: "2026-01-05",
+			n:       4,
+			want:    []string{"2026-01-05", "2026-01-08", "2026-01-12", "2026-01-15"},
+		},
+		{
+			name:    "every other week",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU",
+			dtstart: "2026-01-05",
+			n:       3,
+			want:    []string{"2026-01-05", "2026-01-06", "2026-01-20"},
+		},
+		{
+			name:    "monthly",
+			rule:    "FREQ=MONTHLY",
+			dtstart: "2026-01-05",
+			n:       3,
+			want:    []string{"2026-01-05", "2026-02-05", "2026-03-05"},
+		},
+		{
+			name:    "monthly on the 31st skips short months",
+			rule:    "FREQ=MONTHLY",
+			dtstart: "2026-01-31",
+			n:       4,
+			want:    []string{"2026-01-31", "2026-03-31", "2026-05-31", "2026-07-31"},
+		},
+		{
+			name:    "monthly on the last day",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			dtstart: "2026-01-31",
+			n:       4,
+			want:    []string{"2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30"},
+		},
+		{
+			name:    "monthly on the second monday",
+			rule:    "FREQ=MONTHLY;BYDAY=2MO",
+			dtstart: "2026-01-12",
+			n:       3,
+			want:    []string{"2026-01-12", "2026-02-09", "2026-03-09"},
+		},
+		{
+			name:    "monthly on the last friday",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR",
+			dtstart: "2026-01-30",
+			n:       3,
+			want:    []string{"2026-01-30", "2026-02-27", "2026-03-27"},
+		},
+		{
+			name:    "monthly on friday the 13th",
+			rule:    "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			dtstart: "2026-02-13",
+			n:       3,
+			want:    []string{"2026-02-13", "2026-03-13", "2026-11-13"},
+		},
+		{
+			name:    "count stops the rule",
+			rule:    "FREQ=DAILY;COUNT=2",
+			dtstart: "2026-01-05",
+			n:       5,
+			want:    []string{"2026-01-05", "2026-01-06"},
+		},
+		{
+			name:    "date only until includes the whole day",
+			rule:    "FREQ=DAILY;UNTIL=20260107",
+			dtstart: "2026-01-05",
+			n:       5,
+			want:    []string{"2026-01-05", "2026-01-06", "2026-01-07"},
+		},
+		{
+			name:    "until is compared in UTC",
+			rule:    "FREQ=DAILY;UNTIL=20260107T010000Z",
+			dtstart: "2026-01-05",
+			n:       5,
+			want:    []string{"2026-01-05", "2026-01-06"},
+		},
+		{
+			name:    "until on an occurrence includes it",
+			rule:    "FREQ=DAILY;UNTIL=20260107T020000Z",
+			dtstart: "2026-01-05",
+			n:       5,
+			want:    []string{"2026-01-05", "2026-01-06", "2026-01-07"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			dtstart := at(t, tt.dtstart)
+			occurrences := rule.Take(dtstart, tt.n)
+
+			got := make([]string, 0, len(occurrences))
+			for _, occurrence := range occurrences {
+				if occurrence.Location() != ict || occurrence.Hour() != 9 {
+					t.Errorf("occurrence %v does not keep the clock time and location of dtstart", occurrence)
+				}
+				got = append(got, occurrence.Format(time.DateOnly))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Take() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNth(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=MO,TH;COUNT=4")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	dtstart := at(t, "2026-01-05")
+	for n, want := range []string{"2026-01-05", "2026-01-08", "2026-01-12", "2026-01-15"} {
+		got, ok := rule.Nth(dtstart, n)
+		if !ok || got.Format(time.DateOnly) != want {
+			t.Errorf("Nth(%d) = %v, %v, want %s", n, got, ok, want)
+		}
+	}
+
+	if got, ok := rule.Nth(dtstart, 4); ok {
+		t.Errorf("Nth(4) = %v, want none past COUNT", got)
+	}
+}
+
+func TestIteratorGivesUp(t *testing.T) {
+	// February never has a 30th, so only dtstart occurs.
+	rule, err := Parse("FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	dtstart := at(t, "2026-02-28")
+	it := rule.Iterator(dtstart)
+	if got, ok := it.Next(); !ok || !got.Equal(dtstart) {
+		t.Fatalf("Next() = %v, %v, want dtstart", got, ok)
+	}
+
+	for range 2 {
+		if got, ok := it.Next(); ok {
+			t.Fatalf("Next() = %v, want none", got)
+		}
+	}
+
+	if it.period <= maxEmptyPeriods {
+		t.Errorf("period = %d, want the search to stop after %d empty periods", it.period, maxEmptyPeriods)
+	}
+}
+
+func at(t *testing.T, day string) time.Time {
+	t.Helper()
+
+	d, err := time.ParseInLocation(time.DateOnly, day, ict)
+	if err != nil {
+		t.Fatalf("ParseInLocation(%q) error = %v", day, err)
+	}
+
+	return d.Add(9 * time.Hour)
+}
+
+// TestSeek checks that seeking lands on the same occurrence, at the same
+// index, as walking from dtstart.
+func TestSeek(t *testing.T) {
+	rules := []string{
+		"FREQ=DAILY",
+		"FREQ=DAILY;INTERVAL=3",
+		"FREQ=DAILY;INTERVAL=2;BYDAY=MO,FR",
+		"FREQ=DAILY;BYMONTHDAY=1,-1",
+		"FREQ=WEEKLY",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SA;WKST=SU",
+		"FREQ=MONTHLY;BYDAY=-1FR",
+		"FREQ=MONTHLY",
+		"FREQ=DAILY;COUNT=40",
+		"FREQ=WEEKLY;BYDAY=MO,TH;UNTIL=20260601",
+	}
+	targets := []string{"2026-01-01", "2026-01-05", "2026-01-06", "2026-03-15", "2026-05-31", "2027-02-10"}
+
+	for _, s := range rules {
+		rule, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", s, err)
+		}
+
+		dtstart := at(t, "2026-01-05")
+		for _, target := range targets {
+			seekTo := at(t, target).Add(-3 * time.Hour)
+
+			walk := rule.Iterator(dtstart)
+			want, wantOK := walk.Next()
+			for wantOK && want.Before(seekTo) {
+				want, wantOK = walk.Next()
+			}
+
+			seek := rule.Iterator(dtstart)
+			seek.Seek(seekTo)
+			index := seek.Emitted()
+			got, ok := seek.Next()
+
+			if ok != wantOK || !got.Equal(want) || (ok && index != walk.Emitted()-1) {
+				t.Errorf("%s: Seek(%s) then Next() = %v, %v at %d, want %v, %v at %d",
+					s, target, got, ok, index, want, wantOK, walk.Emitted()-1)
+			}
+		}
+	}
+}
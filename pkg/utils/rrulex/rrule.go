@@ -0,0 +1,233 @@
+package rrulex
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// WeekdayNum is a BYDAY entry. N selects the nth (or, when negative, the nth
+// last) such weekday of the month and is 0 for every one of them.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule is the subset of an RFC 5545 RRULE supported for tasks: FREQ of
+// DAILY, WEEKLY or MONTHLY with INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY
+// and WKST.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	WeekStart  time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+// An "RRULE:" prefix is accepted. A floating or date-only UNTIL is read as UTC.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{
+		Interval:  1,
+		WeekStart: time.Monday,
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate %s", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(value)
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly {
+				err = fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(value)
+		case "COUNT":
+			rule.Count, err = parsePositive(value)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseByMonthDay(value)
+		case "WKST":
+			day, ok := weekdays[value]
+			if !ok {
+				err = fmt.Errorf("invalid WKST %q", value)
+			}
+			rule.WeekStart = day
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case rule.Count > 0 && !rule.Until.IsZero():
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	case rule.Freq != Monthly && slices.ContainsFunc(rule.ByDay, func(d WeekdayNum) bool { return d.N != 0 }):
+		return nil, fmt.Errorf("%w: numbered BYDAY requires FREQ=MONTHLY", ErrInvalidRule)
+	case rule.Freq == Weekly && len(rule.ByMonthDay) > 0:
+		return nil, fmt.Errorf("%w: BYMONTHDAY is not allowed with FREQ=WEEKLY", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+// String formats the rule in its canonical RRULE form, without the prefix.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			name := weekdayNames[day.Weekday]
+			if day.N != 0 {
+				name = strconv.Itoa(day.N) + name
+			}
+			days = append(days, name)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+const (
+	untilLayout         = "20060102T150405Z"
+	untilFloatingLayout = "20060102T150405"
+	untilDateLayout     = "20060102"
+)
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{untilLayout, untilFloatingLayout} {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	// A date-only UNTIL includes the whole day.
+	t, err := time.Parse(untilDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+	}
+
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+func parsePositive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("expected a positive integer, got %q", value)
+	}
+
+	return n, nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	days := make([]WeekdayNum, 0)
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+
+		var n int
+		if prefix := item[:len(item)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid BYDAY %q", item)
+			}
+		}
+
+		days = append(days, WeekdayNum{Weekday: day, N: n})
+	}
+
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	days := make([]int, 0)
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(item)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY %q", item)
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
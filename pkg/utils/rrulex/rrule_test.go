@@ -0,0 +1,67 @@
+package rrulex
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string // canonical form
+	}{
+		{rule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10"},
+		{rule: "FREQ=DAILY;INTERVAL=1", want: "FREQ=DAILY"},
+		{rule: "freq=monthly; byday=2mo", want: "FREQ=MONTHLY;BYDAY=2MO"},
+		{rule: "FREQ=MONTHLY;BYDAY=-1FR", want: "FREQ=MONTHLY;BYDAY=-1FR"},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=-1,15", want: "FREQ=MONTHLY;BYMONTHDAY=-1,15"},
+		{rule: "FREQ=WEEKLY;WKST=SU;BYDAY=SA,SU", want: "FREQ=WEEKLY;BYDAY=SA,SU;WKST=SU"},
+		{rule: "FREQ=DAILY;UNTIL=20261231T120000Z", want: "FREQ=DAILY;UNTIL=20261231T120000Z"},
+		{rule: "FREQ=DAILY;UNTIL=20261231T120000", want: "FREQ=DAILY;UNTIL=20261231T120000Z"},
+		{rule: "FREQ=DAILY;UNTIL=20261231", want: "FREQ=DAILY;UNTIL=20261231T235959Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"RRULE:",
+		"FREQ",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;COUNT=2;UNTIL=20261231",
+		"FREQ=WEEKLY;WKST=XX",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=-32",
+	} {
+		_, err := Parse(rule)
+		if !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Parse(%q) error = %v, want %v", rule, err, ErrInvalidRule)
+		}
+	}
+}
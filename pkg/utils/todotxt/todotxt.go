@@ -0,0 +1,170 @@
+// Package todotxt reads and writes single tasks in the todo.txt format
+// described at https://github.com/todotxt/todo.txt.
+package todotxt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrEmptyTask = errors.New("empty task")
+
+const dateLayout = "2006-01-02"
+
+// Task is one line of a todo.txt file. Text is the description without its
+// projects, contexts and key:value tags, which are kept apart in the order
+// they appear.
+type Task struct {
+	Done           bool
+	Priority       byte
+	CompletionDate *time.Time
+	CreationDate   *time.Time
+	Text           string
+	Projects       []string
+	Contexts       []string
+	Tags           []Tag
+}
+
+// Tag is a key:value extension such as "due:2024-01-31".
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Tag returns the value of the first tag with the given key.
+func (t *Task) Tag(key string) (string, bool) {
+	i := slices.IndexFunc(t.Tags, func(tag Tag) bool {
+		return tag.Key == key
+	})
+	if i < 0 {
+		return "", false
+	}
+
+	return t.Tags[i].Value, true
+}
+
+// Parse reads a single todo.txt line. Dates are read as UTC midnights.
+func Parse(line string) (*Task, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, ErrEmptyTask
+	}
+
+	task := &Task{}
+	if fields[0] == "x" {
+		task.Done = true
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 && isPriority(fields[0]) {
+		task.Priority = fields[0][1]
+		fields = fields[1:]
+	}
+
+	// A completed task may carry a completion date followed by a creation
+	// date, an open one only a creation date.
+	dates := make([]*time.Time, 0, 2)
+	for len(fields) > 0 && len(dates) < 2 {
+		date, err := time.Parse(dateLayout, fields[0])
+		if err != nil {
+			break
+		}
+		dates = append(dates, &date)
+		fields = fields[1:]
+	}
+
+	switch {
+	case len(dates) == 2 && !task.Done:
+		return nil, fmt.Errorf("unexpected completion date on an open task")
+	case len(dates) == 2:
+		task.CompletionDate, task.CreationDate = dates[0], dates[1]
+	case len(dates) == 1 && task.Done:
+		task.CompletionDate = dates[0]
+	case len(dates) == 1:
+		task.CreationDate = dates[0]
+	}
+
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		switch {
+		case len(field) > 1 && field[0] == '+':
+			task.Projects = append(task.Projects, field[1:])
+		case len(field) > 1 && field[0] == '@':
+			task.Contexts = append(task.Contexts, field[1:])
+		case isTag(field):
+			key, value, _ := strings.Cut(field, ":")
+			task.Tags = append(task.Tags, Tag{Key: key, Value: value})
+		default:
+			words = append(words, field)
+		}
+	}
+	task.Text = strings.Join(words, " ")
+
+	if task.Text == "" {
+		return nil, ErrEmptyTask
+	}
+
+	return task, nil
+}
+
+// String formats the task as a todo.txt line. Whitespace in the text is
+// collapsed, and replaced with underscores in projects, contexts and tags.
+func (t *Task) String() string {
+	parts := make([]string, 0, 4+len(t.Projects)+len(t.Contexts)+len(t.Tags))
+	if t.Done {
+		parts = append(parts, "x")
+	}
+	if t.Priority != 0 {
+		parts = append(parts, "("+string(t.Priority)+")")
+	}
+	if t.Done && t.CompletionDate != nil {
+		parts = append(parts, t.CompletionDate.Format(dateLayout))
+	}
+	// Without a completion date, the creation date of a completed task would
+	// be read as one.
+	if t.CreationDate != nil && (!t.Done || t.CompletionDate != nil) {
+		parts = append(parts, t.CreationDate.Format(dateLayout))
+	}
+
+	parts = append(parts, strings.Fields(t.Text)...)
+	for _, project := range t.Projects {
+		parts = append(parts, "+"+word(project))
+	}
+	for _, context := range t.Contexts {
+		parts = append(parts, "@"+word(context))
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, word(tag.Key)+":"+word(tag.Value))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Date formats a date the way todo.txt tags such as due: expect.
+func Date(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+// ParseDate reads a date written by Date as a UTC midnight.
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse(dateLayout, s)
+}
+
+func isPriority(field string) bool {
+	return len(field) == 3 && field[0] == '(' && field[1] >= 'A' && field[1] <= 'Z' && field[2] == ')'
+}
+
+// isTag reports whether field is a key:value tag. Neither side may be empty
+// or contain another colon, and the value may not start with a slash, which
+// leaves out URLs.
+func isTag(field string) bool {
+	key, value, ok := strings.Cut(field, ":")
+	return ok && key != "" && value != "" && !strings.Contains(value, ":") && !strings.HasPrefix(value, "/")
+}
+
+func word(s string) string {
+	return strings.Join(strings.Fields(s), "_")
+}
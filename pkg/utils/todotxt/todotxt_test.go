@@ -0,0 +1,183 @@
+package todotxt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(s string) *time.Time {
+	d, err := ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+	return &d
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want *Task
+	}{
+		{
+			line: "Call mom",
+			want: &Task{Text: "Call mom"},
+		},
+		{
+			line: "(A) 2026-10-01 Call mom +family @phone due:2026-10-20",
+			want: &Task{
+				Priority:     'A',
+				CreationDate: date("2026-10-01"),
+				Text:         "Call mom",
+				Projects:     []string{"family"},
+				Contexts:     []string{"phone"},
+				Tags:         []Tag{{Key: "due", Value: "2026-10-20"}},
+			},
+		},
+		{
+			line: "x 2026-10-18 2026-10-01 Pay rent +home",
+			want: &Task{
+				Done:           true,
+				CompletionDate: date("2026-10-18"),
+				CreationDate:   date("2026-10-01"),
+				Text:           "Pay rent",
+				Projects:       []string{"home"},
+			},
+		},
+		{
+			line: "x 2026-10-18 Pay rent",
+			want: &Task{Done: true, CompletionDate: date("2026-10-18"), Text: "Pay rent"},
+		},
+		{
+			line: "x (B) Pay rent",
+			want: &Task{Done: true, Priority: 'B', Text: "Pay rent"},
+		},
+		{
+			// Only a leading x marks the task done, and (b) is no priority.
+			line: "(b) xylophone x lesson",
+			want: &Task{Text: "(b) xylophone x lesson"},
+		},
+		{
+			// A date past the first ones is part of the text.
+			line: "Meet 2026-10-20 in  the  hall",
+			want: &Task{Text: "Meet 2026-10-20 in the hall"},
+		},
+		{
+			line: "Read https://example.com/a:b email me@example.com + @ key: :value a:b:c",
+			want: &Task{Text: "Read https://example.com/a:b email me@example.com + @ key: :value a:b:c"},
+		},
+		{
+			line: "Plan +work +q4 @office t:2026-10-19 rec:1w due:2026-10-25",
+			want: &Task{
+				Text:     "Plan",
+				Projects: []string{"work", "q4"},
+				Contexts: []string{"office"},
+				Tags: []Tag{
+					{Key: "t", Value: "2026-10-19"},
+					{Key: "rec", Value: "1w"},
+					{Key: "due", Value: "2026-10-25"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := Parse(tt.line)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		line string
+		err  error
+	}{
+		{line: "", err: ErrEmptyTask},
+		{line: "   \t", err: ErrEmptyTask},
+		{line: "x", err: ErrEmptyTask},
+		{line: "(A) +work @office due:2026-10-20", err: ErrEmptyTask},
+		{line: "2026-10-18 2026-10-01 Pay rent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			task, err := Parse(tt.line)
+			if err == nil {
+				t.Fatalf("Parse() = %+v, want an error", task)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTag(t *testing.T) {
+	task, err := Parse("Plan due:2026-10-20 due:2026-10-21")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if value, ok := task.Tag("due"); !ok || value != "2026-10-20" {
+		t.Errorf("Tag(due) = %q, %v, want the first one", value, ok)
+	}
+	if value, ok := task.Tag("t"); ok {
+		t.Errorf("Tag(t) = %q, want none", value)
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name string
+		task *Task
+		want string
+	}{
+		{
+			name: "open",
+			task: &Task{
+				Priority:     'A',
+				CreationDate: date("2026-10-01"),
+				Text:         "Call   mom",
+				Projects:     []string{"big family"},
+				Contexts:     []string{"phone"},
+				Tags:         []Tag{{Key: "due", Value: "2026-10-20"}},
+			},
+			want: "(A) 2026-10-01 Call mom +big_family @phone due:2026-10-20",
+		},
+		{
+			name: "done",
+			task: &Task{Done: true, CompletionDate: date("2026-10-18"), CreationDate: date("2026-10-01"), Text: "Pay rent"},
+			want: "x 2026-10-18 2026-10-01 Pay rent",
+		},
+		{
+			name: "done without completion date",
+			task: &Task{Done: true, CreationDate: date("2026-10-01"), Text: "Pay rent"},
+			want: "x Pay rent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.task.String()
+			if got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			parsed, err := Parse(got)
+			if err != nil {
+				t.Fatalf("Parse(String()) error = %v", err)
+			}
+			if parsed.String() != got {
+				t.Errorf("Parse(String()).String() = %q, want %q", parsed.String(), got)
+			}
+		})
+	}
+}